@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"slices"
 	"strconv"
+	"yora/adapters/onebot/api"
 	"yora/adapters/onebot/client"
 	"yora/adapters/onebot/messages"
+	"yora/adapters/onebot/models"
 	"yora/pkg/adapter"
 	"yora/pkg/event"
 	"yora/pkg/message"
@@ -16,21 +18,60 @@ import (
 	"yora/adapters/onebot/events"
 )
 
-var _ adapter.Adapter = (*Adapter)(nil)
+var (
+	_ adapter.Adapter          = (*Adapter)(nil)
+	_ adapter.Connector        = (*Adapter)(nil)
+	_ adapter.HTTPHandler      = (*Adapter)(nil)
+	_ adapter.HealthReporter   = (*Adapter)(nil)
+	_ adapter.MessageConverter = (*Adapter)(nil)
+	_ adapter.ForwardSender    = (*Adapter)(nil)
+)
 
 type Adapter struct {
-	Client *client.Client
+	ctx        context.Context
+	clients    *client.Registry  // 按账号管理的连接
+	heartbeats *heartbeatMonitor // 按账号记录心跳
 }
 
 // HandleWebSocket implements adapter.Adapter.
 func (a *Adapter) HandleWebSocket(w http.ResponseWriter, r *http.Request, f func(message []byte)) error {
-	a.Client.HandleWebSocket(w, r, f)
+	a.clients.HandleWebSocket(w, r, f)
+
+	return nil
+}
 
+// HandleHTTP implements adapter.HTTPHandler.
+func (a *Adapter) HandleHTTP(w http.ResponseWriter, r *http.Request, f func(message []byte)) error {
+	if a.clients.Config().Mode != client.ModeHTTP {
+		w.WriteHeader(http.StatusNotFound)
+		return fmt.Errorf("当前连接模式不是 HTTP")
+	}
+	a.clients.HandleHTTP(w, r, f)
 	return nil
 }
 
+// Connect implements adapter.Connector.
+// 仅正向 WebSocket 模式需要主动连接，其他模式直接返回
+func (a *Adapter) Connect(ctx context.Context, f func(message []byte)) error {
+	if a.clients.Config().Mode != client.ModeForwardWS {
+		return nil
+	}
+	return a.clients.Connect(ctx, f)
+}
+
+// 设置连接配置
+func (a *Adapter) SetConfig(cfg client.Config) *Adapter {
+	a.clients.SetConfig(cfg)
+	return a
+}
+
+// 获取账号连接管理器
+func (a *Adapter) Clients() *client.Registry {
+	return a.clients
+}
+
 // Send implements adapter.Adapter.
-func (a *Adapter) Send(userId string, groupId string, message message.Message) (any, error) {
+func (a *Adapter) Send(ctx context.Context, userId string, groupId string, message message.Message) (string, error) {
 	uid, err := strconv.Atoi(userId)
 	if err != nil {
 		uid = 0
@@ -39,25 +80,125 @@ func (a *Adapter) Send(userId string, groupId string, message message.Message) (
 	if err != nil {
 		gid = 0
 	}
-	msg, ok := message.(messages.Message)
-	if !ok {
-		return nil, fmt.Errorf("message 类型应为 message.Message，实际为 %T", message)
+	if message == nil {
+		return "", fmt.Errorf("消息不能为空")
+	}
+	msg := messages.FromMessage(message)
+
+	c, err := a.clients.Pick(adapter.SelfIDFromContext(ctx))
+	if err != nil {
+		return "", err
 	}
 
-	return a.Client.Send(uid, gid, msg)
+	resp, err := c.SendContext(ctx, uid, gid, msg)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(resp.Data.MessageID), nil
+}
+
+// SendForward implements adapter.ForwardSender.
+// 群聊使用 send_group_forward_msg，私聊使用 send_private_forward_msg
+func (a *Adapter) SendForward(ctx context.Context, userId string, groupId string, nodes []message.ForwardNode) (string, error) {
+	c, err := a.clients.Pick(adapter.SelfIDFromContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	gid, _ := strconv.Atoi(groupId)
+	if gid != 0 {
+		req := models.SendGroupForwardMessageRequest{GroupID: gid, Messages: forwardNodes(nodes)}
+		resp, err := client.CallContext[models.SendGroupForwardMessageRequest, models.SendGroupForwardMessageResponse](ctx, c, "send_group_forward_msg", req)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(resp.Data.MessageID), nil
+	}
+
+	uid, err := strconv.Atoi(userId)
+	if err != nil {
+		return "", fmt.Errorf("无效的用户ID %q: %w", userId, err)
+	}
+	req := models.SendPrivateForwardMessageRequest{UserID: uid, Messages: forwardNodes(nodes)}
+	resp, err := client.CallContext[models.SendPrivateForwardMessageRequest, models.SendPrivateForwardMessageResponse](ctx, c, "send_private_forward_msg", req)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(resp.Data.MessageID), nil
+}
+
+// 将标准转发节点转换为 OneBot 的 node 消息段
+func forwardNodes(nodes []message.ForwardNode) []models.MessageNode {
+	result := make([]models.MessageNode, 0, len(nodes))
+	for _, n := range nodes {
+		data := models.NewNodeData(n.UserID, n.Nickname)
+		if n.Content != nil {
+			for _, seg := range messages.FromMessage(n.Content) {
+				switch s := seg.(type) {
+				case *messages.Segment:
+					data.Content = append(data.Content, *s)
+				case messages.Segment:
+					data.Content = append(data.Content, s)
+				default:
+					data.Content = append(data.Content, *messages.ToNativeSegment(seg))
+				}
+			}
+		}
+		result = append(result, models.MessageNode{Type: "node", Data: data})
+	}
+	return result
 }
 
 // CallAPI implements adapter.Adapter.
-func (a *Adapter) CallAPI(action string, params any) (any, error) {
-	return a.Client.CallAPI(action, params)
+func (a *Adapter) CallAPI(ctx context.Context, action string, params any) (any, error) {
+	c, err := a.clients.Pick(adapter.SelfIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return c.CallAPIContext(ctx, action, params)
 }
 
 func NewAdapter() *Adapter {
 
 	ctx := context.Background()
-	return &Adapter{
-		Client: client.GetClient(ctx),
+	a := &Adapter{
+		ctx:     ctx,
+		clients: client.NewRegistry(ctx),
 	}
+	a.heartbeats = newHeartbeatMonitor(a.connected)
+	return a
+}
+
+// 账号连接是否建立，HTTP 模式无长连接，视为已连接
+//
+// 与发送时选择连接的方式一致：正向 WebSocket 模式下账号未知的默认连接代表所有账号。
+func (a *Adapter) connected(selfID string) bool {
+	if a.clients.Config().Mode == client.ModeHTTP {
+		return true
+	}
+	c, err := a.clients.Pick(selfID)
+	return err == nil && c.IsConnected()
+}
+
+// Health implements adapter.HealthReporter.
+// 正向 WebSocket 模式下收到第一个元事件前账号未知，按默认连接的状态报告
+func (a *Adapter) Health() []adapter.Health {
+	health := a.heartbeats.snapshot(a.clients.SelfIDs())
+	if len(health) > 0 {
+		return health
+	}
+	if c, ok := a.clients.Client(""); ok {
+		connected := c.IsConnected()
+		return []adapter.Health{{Connected: connected, Online: true, Good: true, Enabled: true, Healthy: connected}}
+	}
+	return health
+}
+
+// OnConnectionLost implements adapter.HealthReporter.
+// 超过心跳间隔的 3 倍未收到心跳时调用 f
+func (a *Adapter) OnConnectionLost(f func(h adapter.Health)) {
+	a.heartbeats.setOnLost(f)
+	a.heartbeats.start(a.ctx)
 }
 
 // GetCapabilities implements adapter.Adapter.
@@ -89,6 +230,13 @@ func (a *Adapter) GetCapabilities() adapter.Capabilities {
 			"reply",
 			"text",
 			"file",
+			// 标准消息段，发送时转换为 OneBot 消息段
+			message.TypeAudio,
+			message.TypeEmoji,
+			message.TypeLink,
+			message.TypeContact,
+			message.TypeCode,
+			message.TypeQuote,
 		},
 		MaxMessageLength: 0,
 		MaxFileSize:      0,
@@ -117,35 +265,60 @@ func (a *Adapter) ParseEvent(raw any) (event.Event, error) {
 		if err := json.Unmarshal(data, &e); err != nil {
 			return nil, fmt.Errorf("解析 MessageEvent 失败: %w", err)
 		}
-		return &e, nil
-	case "notice":
-		var e events.NoticeEvent
-		if err := json.Unmarshal(data, &e); err != nil {
-			return nil, fmt.Errorf("解析 NoticeEvent 失败: %w", err)
+		// 字符串格式（CQ 码）的消息转换为消息段；未上报 message_format 时按消息的类型判断
+		raw, isString := e.MessageValue.(string)
+		if isString && (e.MessageFormat == client.FormatString || e.MessageFormat == "") {
+			msg, err := messages.ParseCQ(raw)
+			if err != nil {
+				return nil, fmt.Errorf("解析 MessageEvent 失败: %w", err)
+			}
+			e.MessageValue = msg
 		}
 		return &e, nil
+	case "notice":
+		return events.ParseNotice(data)
 	case "meta_event":
-		var e events.MetaEvent
-		if err := json.Unmarshal(data, &e); err != nil {
-			return nil, fmt.Errorf("解析 MetaEvent 失败: %w", err)
+		e, err := events.ParseMeta(data)
+		if err != nil {
+			return nil, err
 		}
-		return &e, nil
+		a.heartbeats.observe(e)
+		return e, nil
 	case "request":
-		var e events.RequestEvent
-		if err := json.Unmarshal(data, &e); err != nil {
-			return nil, fmt.Errorf("解析 RequestEvent 失败: %w", err)
+		e, err := events.ParseRequest(data)
+		if err != nil {
+			return nil, err
 		}
-		return &e, nil
+		// 使用接收请求的账号处理请求
+		if bindable, ok := e.(interface{ SetAPI(events.RequestAPI) }); ok {
+			if c, err := a.clients.Pick(e.SelfID()); err == nil {
+				bindable.SetAPI(api.New(c))
+			}
+		}
+		return e, nil
 	default:
 		return nil, fmt.Errorf("未知事件类型: %s", base.Type)
 	}
 
 }
 
-// todo ParseMessage implements adapter.Adapter.
+// ParseMessage 解析字符串格式（CQ 码）的消息
 func (a *Adapter) ParseMessage(raw string) ([]message.Segment, error) {
-	var segments []message.Segment
-	return segments, nil
+	msg, err := messages.ParseCQ(raw)
+	if err != nil {
+		return nil, err
+	}
+	return msg.Segments(), nil
+}
+
+// ToNative implements adapter.MessageConverter.
+func (a *Adapter) ToNative(msg message.Message) message.Message {
+	return messages.FromMessage(msg)
+}
+
+// ToStandard implements adapter.MessageConverter.
+func (a *Adapter) ToStandard(msg message.Message) message.Message {
+	return messages.ToStandard(msg)
 }
 
 // Protocol implements adapter.Adapter.
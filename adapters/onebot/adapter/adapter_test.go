@@ -0,0 +1,102 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"yora/adapters/onebot/events"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/on"
+	"yora/pkg/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventStringFormat(t *testing.T) {
+	a := NewAdapter()
+	raw := `{"post_type":"message","message_type":"group","group_id":1,"user_id":2,"message_format":"string","message":"[CQ:at,qq=3] hi &#91;1&#93;"}`
+
+	e, err := a.ParseEvent([]byte(raw))
+	require.NoError(t, err)
+	msg := e.(event.MessageEvent).Message()
+	require.Len(t, msg.Segments(), 2)
+	assert.Equal(t, "at", msg.Segments()[0].Type())
+	assert.Equal(t, " hi [1]", msg.PlainText())
+
+	_, err = a.ParseEvent([]byte(`{"post_type":"message","message":"[CQ:at,qq=3"}`))
+	assert.Error(t, err)
+
+	// 上报为数组格式时，字符串消息按纯文本处理
+	e, err = a.ParseEvent([]byte(`{"post_type":"message","message_type":"private","user_id":2,"message_format":"array","message":"[CQ:at,qq=3]"}`))
+	require.NoError(t, err)
+	msg = e.(event.MessageEvent).Message()
+	require.Len(t, msg.Segments(), 1)
+	assert.Equal(t, "[CQ:at,qq=3]", msg.PlainText())
+
+	segs, err := a.ParseMessage("[CQ:face,id=1]ok")
+	require.NoError(t, err)
+	assert.Len(t, segs, 2)
+}
+
+func TestStandardMessage(t *testing.T) {
+	a := NewAdapter()
+	raw := `{"post_type":"message","message_type":"group","group_id":1,"user_id":2,"message":[{"type":"at","data":{"qq":"3"}},{"type":"face","data":{"id":"14"}},{"type":"text","data":{"text":" hi"}}]}`
+
+	e, err := a.ParseEvent([]byte(raw))
+	require.NoError(t, err)
+	me := e.(event.MessageEvent)
+
+	// 原生消息保持 OneBot 格式
+	assert.Equal(t, "at", me.Message().Segments()[0].Type())
+	_, ok := me.Message().Segments()[0].GetData("user_id")
+	assert.False(t, ok)
+
+	segs := me.StandardMessage().Segments()
+	require.Len(t, segs, 3)
+	assert.Equal(t, message.TypeAt, segs[0].Type())
+	assert.Equal(t, "3", message.StringData(segs[0], "user_id"))
+	assert.Equal(t, message.TypeEmoji, segs[1].Type())
+	assert.Equal(t, " hi", me.StandardMessage().PlainText())
+}
+
+func TestForwardNodesToNative(t *testing.T) {
+	nodes := forwardNodes([]message.ForwardNode{
+		message.NewForwardNode("10", "帮助", message.NewBuilder().At("3").Text(" hi").Build()),
+		message.NewForwardNode("10", "帮助", nil),
+	})
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "node", nodes[0].Type)
+	assert.Equal(t, "10", nodes[0].Data.UserID)
+	assert.Equal(t, "帮助", nodes[0].Data.Nickname)
+	require.Len(t, nodes[0].Data.Content, 2)
+	assert.Equal(t, "at", nodes[0].Data.Content[0].Type())
+	assert.Equal(t, "3", message.StringData(&nodes[0].Data.Content[0], "qq"))
+	assert.Empty(t, nodes[1].Data.Content)
+}
+
+func TestNoticeInjectedByType(t *testing.T) {
+	a := NewAdapter()
+	poke, err := a.ParseEvent([]byte(`{"post_type":"notice","notice_type":"notify","sub_type":"poke","user_id":1,"target_id":2}`))
+	require.NoError(t, err)
+	increase, err := a.ParseEvent([]byte(`{"post_type":"notice","notice_type":"group_increase","user_id":1,"group_id":3}`))
+	require.NoError(t, err)
+
+	var target string
+	m := on.OnNoticeOf[*events.PokeNotice](handler.NewHandler(func(n *events.PokeNotice) {
+		target = n.TargetID()
+	}))
+
+	ctx := context.Background()
+	assert.False(t, m.Match(ctx, increase))
+	require.True(t, m.Match(ctx, poke))
+
+	ctx = handler.WithScope(ctx, handler.NewScope(poke, provider.Event()))
+	require.NoError(t, m.Call(ctx, poke))
+	assert.Equal(t, "2", target)
+
+	assert.True(t, on.OnNoticeSubType("notify", "poke", nil).Match(ctx, poke))
+	assert.True(t, on.OnNoticeType("group_increase", nil).Match(ctx, increase))
+	assert.False(t, on.OnNoticeType("group_increase", nil).Match(ctx, poke))
+}
@@ -0,0 +1,164 @@
+package adapter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"yora/adapters/onebot/events"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+)
+
+// 心跳超时倍数：超过 interval 的该倍数仍未收到心跳时视为连接失活
+const heartbeatTimeoutFactor = 3
+
+// 心跳检查周期
+const heartbeatCheckInterval = time.Second
+
+// 单个账号的心跳状态
+type heartbeatState struct {
+	last     time.Time
+	interval time.Duration
+	online   bool
+	good     bool
+	enabled  bool
+	lost     bool
+}
+
+// heartbeatMonitor 按账号记录元事件，检测心跳超时
+type heartbeatMonitor struct {
+	mu        sync.Mutex
+	states    map[string]*heartbeatState
+	onLost    func(h adapter.Health)
+	connected func(selfID string) bool
+	now       func() time.Time
+	startOnce sync.Once
+}
+
+func newHeartbeatMonitor(connected func(selfID string) bool) *heartbeatMonitor {
+	return &heartbeatMonitor{
+		states:    make(map[string]*heartbeatState),
+		connected: connected,
+		now:       time.Now,
+	}
+}
+
+// 获取账号状态，不存在时创建，调用方需持有锁
+func (m *heartbeatMonitor) state(selfID string) *heartbeatState {
+	s, ok := m.states[selfID]
+	if !ok {
+		s = &heartbeatState{online: true, good: true, enabled: true}
+		m.states[selfID] = s
+	}
+	return s
+}
+
+// observe 记录元事件
+func (m *heartbeatMonitor) observe(e event.MetaEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.state(e.SelfID())
+	switch e := e.(type) {
+	case *events.HeartbeatEvent:
+		s.last = m.now()
+		s.interval = e.Interval()
+		s.online = e.Online()
+		s.good = e.Good()
+		s.lost = false
+	case *events.LifecycleEvent:
+		switch events.SubType(e.SubType()) {
+		case events.SubTypeConnect, events.SubTypeEnable:
+			// 重新连接后从现在开始计算心跳超时
+			s.last = m.now()
+			s.enabled = true
+			s.lost = false
+		case events.SubTypeDisable:
+			s.enabled = false
+		}
+	}
+}
+
+// start 启动后台检查，重复调用无效
+func (m *heartbeatMonitor) start(ctx context.Context) {
+	m.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(heartbeatCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					m.check()
+				}
+			}
+		}()
+	})
+}
+
+// check 标记心跳超时的账号，并对新失活的账号调用回调
+func (m *heartbeatMonitor) check() {
+	now := m.now()
+
+	m.mu.Lock()
+	var lost []adapter.Health
+	for selfID, s := range m.states {
+		if s.lost || s.last.IsZero() || s.interval <= 0 {
+			continue
+		}
+		if now.Sub(s.last) > s.interval*heartbeatTimeoutFactor {
+			s.lost = true
+			lost = append(lost, m.health(selfID, s))
+		}
+	}
+	onLost := m.onLost
+	m.mu.Unlock()
+
+	if onLost == nil {
+		return
+	}
+	for _, h := range lost {
+		onLost(h)
+	}
+}
+
+// 生成账号健康状态，调用方需持有锁
+func (m *heartbeatMonitor) health(selfID string, s *heartbeatState) adapter.Health {
+	connected := m.connected == nil || m.connected(selfID)
+	return adapter.Health{
+		SelfID:        selfID,
+		Connected:     connected,
+		Online:        s.online,
+		Good:          s.good,
+		Enabled:       s.enabled,
+		LastHeartbeat: s.last,
+		Interval:      s.interval,
+		Healthy:       connected && !s.lost && s.online && s.good && s.enabled,
+	}
+}
+
+// snapshot 所有已知账号的健康状态，按账号排序
+func (m *heartbeatMonitor) snapshot(selfIDs []string) []adapter.Health {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range selfIDs {
+		m.state(id)
+	}
+
+	result := make([]adapter.Health, 0, len(m.states))
+	for selfID, s := range m.states {
+		result = append(result, m.health(selfID, s))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SelfID < result[j].SelfID })
+	return result
+}
+
+// setOnLost 设置失活回调
+func (m *heartbeatMonitor) setOnLost(f func(h adapter.Health)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLost = f
+}
@@ -0,0 +1,129 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yora/adapters/onebot/client"
+	"yora/pkg/adapter"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatLost(t *testing.T) {
+	a := NewAdapter()
+	now := time.Unix(1000, 0)
+	a.heartbeats.now = func() time.Time { return now }
+	a.heartbeats.connected = func(string) bool { return true }
+
+	var lost []adapter.Health
+	a.heartbeats.setOnLost(func(h adapter.Health) { lost = append(lost, h) })
+
+	_, err := a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"heartbeat","self_id":10,"interval":5000,"status":{"online":true,"good":true}}`))
+	require.NoError(t, err)
+
+	health := a.Health()
+	require.Len(t, health, 1)
+	assert.Equal(t, "10", health[0].SelfID)
+	assert.Equal(t, 5*time.Second, health[0].Interval)
+	assert.True(t, health[0].Healthy)
+
+	// 未超过 3 倍间隔
+	now = now.Add(15 * time.Second)
+	a.heartbeats.check()
+	assert.Empty(t, lost)
+
+	now = now.Add(time.Second)
+	a.heartbeats.check()
+	require.Len(t, lost, 1)
+	assert.Equal(t, "10", lost[0].SelfID)
+	assert.False(t, lost[0].Healthy)
+	assert.False(t, a.Health()[0].Healthy)
+
+	// 只通知一次
+	a.heartbeats.check()
+	assert.Len(t, lost, 1)
+
+	// 重新连接后从连接时刻开始计算超时，不会立即再次判定失活
+	now = now.Add(time.Minute)
+	_, err = a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"lifecycle","sub_type":"connect","self_id":10}`))
+	require.NoError(t, err)
+	a.heartbeats.check()
+	assert.Len(t, lost, 1)
+	assert.True(t, a.Health()[0].Healthy)
+
+	now = now.Add(16 * time.Second)
+	a.heartbeats.check()
+	assert.Len(t, lost, 2)
+
+	// 恢复心跳
+	_, err = a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"heartbeat","self_id":10,"interval":5000,"status":{"online":true,"good":true}}`))
+	require.NoError(t, err)
+	assert.True(t, a.Health()[0].Healthy)
+}
+
+func TestHeartbeatStatus(t *testing.T) {
+	a := NewAdapter()
+	a.heartbeats.connected = func(string) bool { return true }
+
+	_, err := a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"heartbeat","self_id":10,"interval":5000,"status":{"online":false,"good":true}}`))
+	require.NoError(t, err)
+	h := a.Health()[0]
+	assert.False(t, h.Online)
+	assert.False(t, h.Healthy)
+
+	_, err = a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"lifecycle","sub_type":"disable","self_id":11}`))
+	require.NoError(t, err)
+	h = a.Health()[1]
+	assert.Equal(t, "11", h.SelfID)
+	assert.False(t, h.Enabled)
+	assert.False(t, h.Healthy)
+
+	_, err = a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"lifecycle","sub_type":"enable","self_id":11}`))
+	require.NoError(t, err)
+	assert.True(t, a.Health()[1].Healthy)
+}
+
+func TestHealthForwardWebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage() // 保持连接直到客户端关闭
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := NewAdapter().SetConfig(client.Config{
+		Mode: client.ModeForwardWS,
+		URL:  "ws" + strings.TrimPrefix(server.URL, "http"),
+	})
+	assert.Empty(t, a.Health(), "尚未连接")
+	require.NoError(t, a.Connect(ctx, func([]byte) {}))
+
+	// 收到心跳前账号未知，按默认连接报告
+	require.Eventually(t, func() bool {
+		health := a.Health()
+		return len(health) == 1 && health[0].Healthy
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// 收到心跳后按实际账号报告，连接回退到默认连接
+	_, err := a.ParseEvent([]byte(`{"post_type":"meta_event","meta_event_type":"heartbeat","self_id":10,"interval":5000,"status":{"online":true,"good":true}}`))
+	require.NoError(t, err)
+	health := a.Health()
+	require.Len(t, health, 1)
+	assert.Equal(t, "10", health[0].SelfID)
+	assert.True(t, health[0].Connected)
+	assert.True(t, health[0].Healthy)
+}
@@ -10,7 +10,7 @@ import (
 func TestCanSendImage(t *testing.T) {
 	h := NewTestHelper(t)
 
-	resp, err := h.api.CanSendImage()
+	resp, err := h.api.CanSendImage(h.ctx)
 
 	h.StatusOk(resp, err, "可以发送图片")
 
@@ -20,7 +20,7 @@ func TestCanSendImage(t *testing.T) {
 func TestCanSendRecord(t *testing.T) {
 	h := NewTestHelper(t)
 
-	resp, err := h.api.CanSendRecord()
+	resp, err := h.api.CanSendRecord(h.ctx)
 
 	h.StatusOk(resp, err, "可以发送语音")
 	assert.Equal(t, resp.Data.Yes, true, "可以发送语音")
@@ -29,7 +29,7 @@ func TestCanSendRecord(t *testing.T) {
 func TestUploadImage(t *testing.T) {
 	h := NewTestHelper(t)
 
-	resp, err := h.api.UploadImage(ImageURL)
+	resp, err := h.api.UploadImage(h.ctx, ImageURL)
 	h.StatusOk(resp, err, "上传图片")
 	assert.True(t, strings.HasPrefix(resp.Data, "http"), "上传图片")
 }
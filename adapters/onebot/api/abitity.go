@@ -1,29 +1,29 @@
 package api
 
 import (
-	"yora/adapters/onebot/client"
+	"context"
 	"yora/adapters/onebot/models"
 )
 
 // 检查是否可以发送图片
-func (api *API) CanSendImage() (*models.CanSendImageResponse, error) {
+func (api *API) CanSendImage(ctx context.Context) (*models.CanSendImageResponse, error) {
 	req := models.CanSendImageRequest{}
-	return client.Call[models.CanSendImageRequest, models.CanSendImageResponse](api.client, "can_send_image", req)
+	return call[models.CanSendImageRequest, models.CanSendImageResponse](ctx, api, "can_send_image", req)
 
 }
 
 // 检查是否可以发送语音
-func (api *API) CanSendRecord() (*models.CanSendRecordResponse, error) {
+func (api *API) CanSendRecord(ctx context.Context) (*models.CanSendRecordResponse, error) {
 	req := models.CanSendRecordRequest{}
-	return client.Call[models.CanSendRecordRequest, models.CanSendRecordResponse](api.client, "can_send_record", req)
+	return call[models.CanSendRecordRequest, models.CanSendRecordResponse](ctx, api, "can_send_record", req)
 
 }
 
 // 上传图片
-func (api *API) UploadImage(file string) (*models.UploadImageResponse, error) {
+func (api *API) UploadImage(ctx context.Context, file string) (*models.UploadImageResponse, error) {
 	req := models.UploadImageRequest{
 		File: file,
 	}
-	return client.Call[models.UploadImageRequest, models.UploadImageResponse](api.client, "upload_image", req)
+	return call[models.UploadImageRequest, models.UploadImageResponse](ctx, api, "upload_image", req)
 
 }
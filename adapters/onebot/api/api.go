@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"yora/adapters/onebot/client"
+	"yora/pkg/adapter"
 )
 
 var (
@@ -11,17 +12,45 @@ var (
 	instance *API
 )
 
+// API OneBot API 调用
+//
+// 所有方法的 ctx 用于取消调用与设置超时，未设置截止时间时使用配置中该 API 的默认超时。
+// 调用失败（响应 status 不为 ok）时返回 *client.APIError，
+// 可用 client.IsPermissionDenied、client.IsNotFound、client.IsRateLimited 判断原因。
 type API struct {
-	client *client.Client
+	client *client.Client // 固定使用的连接，为空时按 ctx 中的账号选择
 }
 
-func newAPI() *API {
-	return &API{client: client.GetClient(context.Background())}
+// New 创建使用指定连接的 API，用于多账号时按账号调用
+func New(c *client.Client) *API {
+	return &API{client: c}
 }
 
+// GetAPI 获取按账号选择连接的 API：每次调用时根据 ctx 中的 self_id（事件处理时为接收事件的账号）
+// 从默认连接管理器中选择连接，ctx 中没有账号时使用默认连接
 func GetAPI() *API {
 	once.Do(func() {
-		instance = newAPI()
+		instance = &API{}
 	})
 	return instance
 }
+
+// 选择本次调用使用的连接
+func (api *API) clientFor(ctx context.Context) (*client.Client, error) {
+	if api.client != nil {
+		return api.client, nil
+	}
+	if r := client.DefaultRegistry(); r != nil {
+		return r.Pick(adapter.SelfIDFromContext(ctx))
+	}
+	return client.GetClient(ctx), nil
+}
+
+// 使用 ctx 对应账号的连接调用 API
+func call[ReqType any, RespType any](ctx context.Context, api *API, action string, req ReqType) (*RespType, error) {
+	c, err := api.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.CallContext[ReqType, RespType](ctx, c, action, req)
+}
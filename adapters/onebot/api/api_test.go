@@ -5,13 +5,18 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 	"yora/adapters/onebot/client"
+	"yora/pkg/adapter"
 
+	"github.com/gorilla/websocket"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +34,9 @@ var (
 var (
 	onceTest     sync.Once
 	instanceTest *TestHelper
+
+	configOnce sync.Once
+	configErr  error
 )
 
 type Config struct {
@@ -59,7 +67,12 @@ func NewTestHelperInstance(t *testing.T) *TestHelper {
 	}
 }
 
+// 需要真实 OneBot 连接的测试使用，未找到 yora.yaml 时跳过
 func NewTestHelper(t *testing.T) *TestHelper {
+	configOnce.Do(func() { configErr = applyConfig() })
+	if configErr != nil {
+		t.Skipf("跳过集成测试: %v", configErr)
+	}
 	onceTest.Do(func() {
 		instanceTest = NewTestHelperInstance(t)
 	})
@@ -149,10 +162,11 @@ func loadConfig() (*Config, error) {
 	return &c, nil
 }
 
-func init() {
+// 从 yora.yaml 读取集成测试参数
+func applyConfig() error {
 	c, err := loadConfig()
 	if err != nil {
-		panic(err)
+		return err
 	}
 	ImageURL = c.Tests.ImageUrl
 	GID = c.Tests.GID
@@ -160,6 +174,7 @@ func init() {
 	LocalFile = c.Tests.LocalFile
 	LocalImage = c.Tests.LocalImage
 	TID = c.Tests.TID
+	return nil
 }
 
 // 封装初始化函数
@@ -177,3 +192,41 @@ func initAPITestServer(ctx context.Context) {
 	fmt.Println("等待连接...")
 
 }
+
+func TestGetAPIPicksClientBySelfID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := client.DefaultRegistry()
+	if r == nil {
+		r = client.NewRegistry(ctx)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.HandleWebSocket(w, req, func([]byte) {})
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	for _, id := range []string{"20001", "20002"} {
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"X-Self-ID": {id}})
+		require.NoError(t, err)
+		defer conn.Close()
+	}
+	require.Eventually(t, func() bool {
+		_, okA := r.Client("20001")
+		_, okB := r.Client("20002")
+		return okA && okB
+	}, time.Second, 10*time.Millisecond)
+
+	for _, id := range []string{"20001", "20002"} {
+		c, err := GetAPI().clientFor(adapter.WithSelfID(ctx, id))
+		require.NoError(t, err)
+		assert.Equal(t, id, c.SelfID())
+	}
+
+	// 指定连接的 API 不受 ctx 中账号影响
+	fixed, _ := r.Client("20001")
+	c, err := New(fixed).clientFor(adapter.WithSelfID(ctx, "20002"))
+	require.NoError(t, err)
+	assert.Same(t, fixed, c)
+}
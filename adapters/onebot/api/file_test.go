@@ -15,7 +15,7 @@ func TestGetPrivateFile(t *testing.T) {
 
 	h.t.Skip("私有文件API未实现")
 
-	resp, err := h.api.GetPrivateFile(UID, "", "")
+	resp, err := h.api.GetPrivateFile(h.ctx, UID, "", "")
 	h.StatusOk(resp, err, "获取私有文件")
 
 	assert.True(t, strings.HasPrefix(resp.Data.URL, "http"), "URL格式验证")
@@ -35,7 +35,7 @@ func NewTestFileHelper(t *testing.T) *TestFileHelper {
 
 // 创建临时测试文件夹并返回清理函数
 func (h *TestFileHelper) createTempFolder(folderName string) (string, func()) {
-	_, err := h.api.CreateGroupFolder(GID, folderName)
+	_, err := h.api.CreateGroupFolder(h.ctx, GID, folderName)
 	if err != nil {
 		h.t.Logf("创建临时文件夹失败，可能已存在: %v", err)
 	}
@@ -46,7 +46,7 @@ func (h *TestFileHelper) createTempFolder(folderName string) (string, func()) {
 	}
 
 	cleanup := func() {
-		if _, err := h.api.DeleteGroupFolder(GID, folderID); err != nil {
+		if _, err := h.api.DeleteGroupFolder(h.ctx, GID, folderID); err != nil {
 			h.t.Logf("清理临时文件夹失败: %v", err)
 		}
 	}
@@ -61,7 +61,7 @@ func (h *TestFileHelper) uploadTempFile(fileName, folder string) (string, func()
 		h.t.Fatalf("获取文件绝对路径失败: %v", err)
 	}
 
-	_, err = h.api.UploadGroupFile(GID, absFile, fileName, folder)
+	_, err = h.api.UploadGroupFile(h.ctx, GID, absFile, fileName, folder)
 	if err != nil {
 		h.t.Fatalf("上传临时文件失败: %v", err)
 	}
@@ -72,7 +72,7 @@ func (h *TestFileHelper) uploadTempFile(fileName, folder string) (string, func()
 	}
 
 	cleanup := func() {
-		if _, err := h.api.DeleteGroupFile(GID, fileID); err != nil {
+		if _, err := h.api.DeleteGroupFile(h.ctx, GID, fileID); err != nil {
 			h.t.Logf("清理临时文件失败: %v", err)
 		}
 	}
@@ -91,13 +91,13 @@ func (h *TestFileHelper) searchFileInDirectory(groupID int, folderID, filename s
 
 	// 根据是否有folderID决定调用哪个API
 	if folderID == "" {
-		respRoot, err := h.api.GetGroupRootFiles(groupID)
+		respRoot, err := h.api.GetGroupRootFiles(h.ctx, groupID)
 		if err != nil {
 			return "", err
 		}
 		resp = respRoot
 	} else {
-		respSub, err := h.api.GetGroupSubFiles(groupID, folderID)
+		respSub, err := h.api.GetGroupSubFiles(h.ctx, groupID, folderID)
 		if err != nil {
 			return "", err
 		}
@@ -136,7 +136,7 @@ func (h *TestFileHelper) searchFileInDirectory(groupID int, folderID, filename s
 
 // 获取文件夹ID
 func (h *TestFileHelper) getFolderIDByName(groupID int, folderName string) (string, error) {
-	resp, err := h.api.GetGroupRootFiles(groupID)
+	resp, err := h.api.GetGroupRootFiles(h.ctx, groupID)
 	if err != nil {
 		return "", err
 	}
@@ -160,7 +160,7 @@ func TestGetGroupFileURL(t *testing.T) {
 	defer cleanup()
 
 	// 测试获取文件URL
-	resp, err := h.api.GetGroupFileURL(GID, fileID)
+	resp, err := h.api.GetGroupFileURL(h.ctx, GID, fileID)
 	h.StatusOk(resp, err, "获取群文件URL")
 	assert.True(t, strings.HasPrefix(resp.Data.URL, "http"), "URL格式验证")
 
@@ -169,7 +169,7 @@ func TestGetGroupFileURL(t *testing.T) {
 func TestGetGroupRootFiles(t *testing.T) {
 	h := NewTestFileHelper(t)
 
-	resp, err := h.api.GetGroupRootFiles(GID)
+	resp, err := h.api.GetGroupRootFiles(h.ctx, GID)
 	h.StatusOk(resp, err, "获取群根文件")
 
 }
@@ -183,7 +183,7 @@ func TestGetGroupSubFiles(t *testing.T) {
 	defer cleanup()
 
 	// 测试获取子文件
-	resp, err := h.api.GetGroupSubFiles(GID, folderID)
+	resp, err := h.api.GetGroupSubFiles(h.ctx, GID, folderID)
 	h.StatusOk(resp, err, "获取群子文件")
 
 }
@@ -202,7 +202,7 @@ func TestMoveGroupFile(t *testing.T) {
 	defer cleanupFile()
 
 	// 移动文件
-	resp, err := h.api.MoveGroupFile(GID, fileID, "", folderID)
+	resp, err := h.api.MoveGroupFile(h.ctx, GID, fileID, "", folderID)
 
 	h.StatusOk(resp, err, "移动群文件")
 
@@ -216,7 +216,7 @@ func TestDeleteGroupFile(t *testing.T) {
 	fileID, _ := h.uploadTempFile(fileName, "/") // 不使用cleanup，因为我们要手动删除
 
 	// 删除文件
-	resp, err := h.api.DeleteGroupFile(GID, fileID)
+	resp, err := h.api.DeleteGroupFile(h.ctx, GID, fileID)
 	h.StatusOk(resp, err, "删除群文件")
 
 }
@@ -227,12 +227,12 @@ func TestCreateGroupFolder(t *testing.T) {
 	folderName := "test_create_folder"
 
 	// 创建文件夹
-	resp, err := h.api.CreateGroupFolder(GID, folderName)
+	resp, err := h.api.CreateGroupFolder(h.ctx, GID, folderName)
 	h.StatusOk(resp, err, "创建群文件夹")
 
 	// 清理：删除创建的文件夹
 	if folderID, err := h.getFolderIDByName(GID, folderName); err == nil {
-		rp, err := h.api.DeleteGroupFolder(GID, folderID)
+		rp, err := h.api.DeleteGroupFolder(h.ctx, GID, folderID)
 		h.StatusOk(rp, err, "删除群文件夹")
 	}
 }
@@ -245,7 +245,7 @@ func TestDeleteGroupFolder(t *testing.T) {
 	folderID, _ := h.createTempFolder(folderName) // 不使用cleanup，因为我们要手动删除
 
 	// 删除文件夹
-	resp, err := h.api.DeleteGroupFolder(GID, folderID)
+	resp, err := h.api.DeleteGroupFolder(h.ctx, GID, folderID)
 	h.StatusOk(resp, err, "删除群文件夹")
 
 }
@@ -260,7 +260,7 @@ func TestRenameGroupFolder(t *testing.T) {
 	defer cleanup()
 
 	// 重命名文件夹
-	resp, err := h.api.RenameGroupFolder(GID, folderID, newName)
+	resp, err := h.api.RenameGroupFolder(h.ctx, GID, folderID, newName)
 	h.StatusOk(resp, err, "重命名群文件夹")
 
 }
@@ -276,12 +276,12 @@ func TestUploadGroupFile(t *testing.T) {
 	fileName := "test_upload.jpg"
 
 	// 上传文件
-	resp, err := h.api.UploadGroupFile(GID, absFile, fileName, "/")
+	resp, err := h.api.UploadGroupFile(h.ctx, GID, absFile, fileName, "/")
 	h.StatusOk(resp, err, "上传群文件")
 
 	// 清理：删除上传的文件
 	if fileID, err := h.getFileIDByName(GID, fileName); err == nil {
-		h.api.DeleteGroupFile(GID, fileID)
+		h.api.DeleteGroupFile(h.ctx, GID, fileID)
 	}
 
 }
@@ -299,7 +299,7 @@ func TestUploadPrivateFile(t *testing.T) {
 	fileName := "test_private_upload.jpg"
 
 	// 上传私有文件
-	resp, err := h.api.UploadPrivateFile(UID, absFile, fileName)
+	resp, err := h.api.UploadPrivateFile(h.ctx, UID, absFile, fileName)
 
 	h.StatusOk(resp, err, "上传私有文件")
 	if err == nil {
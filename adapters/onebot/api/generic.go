@@ -1,14 +1,14 @@
 package api
 
 import (
-	"yora/adapters/onebot/client"
+	"context"
 	"yora/adapters/onebot/models"
 )
 
 // 获取自定义表情
-func (api *API) FetchCustomFace() (*models.FetchCustomFaceResponse, error) {
+func (api *API) FetchCustomFace(ctx context.Context) (*models.FetchCustomFaceResponse, error) {
 	req := models.FetchCustomFaceRequest{}
-	return client.Call[models.FetchCustomFaceRequest, models.FetchCustomFaceResponse](api.client, "fetch_custom_face", req)
+	return call[models.FetchCustomFaceRequest, models.FetchCustomFaceResponse](ctx, api, "fetch_custom_face", req)
 
 }
 
@@ -16,11 +16,11 @@ func (api *API) FetchCustomFace() (*models.FetchCustomFaceResponse, error) {
 //
 // 参数：
 //   - emojiIDs: 表情 ID 列表
-func (api *API) FetchMfaceKey(emojiIDs []string) (*models.FetchMfaceKeyResponse, error) {
+func (api *API) FetchMfaceKey(ctx context.Context, emojiIDs []string) (*models.FetchMfaceKeyResponse, error) {
 	req := models.FetchMfaceKeyRequest{
 		Emoji_IDs: emojiIDs,
 	}
-	return client.Call[models.FetchMfaceKeyRequest, models.FetchMfaceKeyResponse](api.client, "fetch_mface_key", req)
+	return call[models.FetchMfaceKeyRequest, models.FetchMfaceKeyResponse](ctx, api, "fetch_mface_key", req)
 
 }
 
@@ -30,13 +30,13 @@ func (api *API) FetchMfaceKey(emojiIDs []string) (*models.FetchMfaceKeyResponse,
 //   - userID: 用户 ID
 //   - messageID: 消息 ID
 //   - emojiID: 表情 ID
-func (api *API) JoinFriendEmojiChain(userID int, messageID int, emojiID int) (*models.JoinFriendEmojiChainResponse, error) {
+func (api *API) JoinFriendEmojiChain(ctx context.Context, userID int, messageID int, emojiID int) (*models.JoinFriendEmojiChainResponse, error) {
 	req := models.JoinFriendEmojiChainRequest{
 		UserID:    userID,
 		MessageID: messageID,
 		EmojiID:   emojiID,
 	}
-	return client.Call[models.JoinFriendEmojiChainRequest, models.JoinFriendEmojiChainResponse](api.client, ".join_friend_emoji_chain", req)
+	return call[models.JoinFriendEmojiChainRequest, models.JoinFriendEmojiChainResponse](ctx, api, ".join_friend_emoji_chain", req)
 
 }
 
@@ -45,12 +45,12 @@ func (api *API) JoinFriendEmojiChain(userID int, messageID int, emojiID int) (*m
 // 参数：
 //   - groupID: 群 ID
 //   - chatType: 聊天类型（如 1 表示群聊）
-func (api *API) GetAICharacters(groupID int, chatType int) (*models.GetAICharactersResponse, error) {
+func (api *API) GetAICharacters(ctx context.Context, groupID int, chatType int) (*models.GetAICharactersResponse, error) {
 	req := models.GetAICharactersRequest{
 		GroupID:  groupID,
 		ChatType: chatType,
 	}
-	return client.Call[models.GetAICharactersRequest, models.GetAICharactersResponse](api.client, "get_ai_characters", req)
+	return call[models.GetAICharactersRequest, models.GetAICharactersResponse](ctx, api, "get_ai_characters", req)
 
 }
 
@@ -58,11 +58,11 @@ func (api *API) GetAICharacters(groupID int, chatType int) (*models.GetAICharact
 //
 // 参数：
 //   - domain: 域名，如 ".qq.com"
-func (api *API) GetCookies(domain string) (*models.GetCookiesResponse, error) {
+func (api *API) GetCookies(ctx context.Context, domain string) (*models.GetCookiesResponse, error) {
 	req := models.GetCookiesRequest{
 		Domain: domain,
 	}
-	return client.Call[models.GetCookiesRequest, models.GetCookiesResponse](api.client, "get_cookies", req)
+	return call[models.GetCookiesRequest, models.GetCookiesResponse](ctx, api, "get_cookies", req)
 
 }
 
@@ -70,18 +70,18 @@ func (api *API) GetCookies(domain string) (*models.GetCookiesResponse, error) {
 //
 // 参数：
 //   - domain: 域名，如 ".qq.com"
-func (api *API) GetCredentials(domain string) (*models.GetCredentialsResponse, error) {
+func (api *API) GetCredentials(ctx context.Context, domain string) (*models.GetCredentialsResponse, error) {
 	req := models.GetCredentialsRequest{
 		Domain: domain,
 	}
-	return client.Call[models.GetCredentialsRequest, models.GetCredentialsResponse](api.client, "get_credentials", req)
+	return call[models.GetCredentialsRequest, models.GetCredentialsResponse](ctx, api, "get_credentials", req)
 
 }
 
 // 获取 CSRF Token
-func (api *API) GetCSRFToken() (*models.GetCSRFTokenResponse, error) {
+func (api *API) GetCSRFToken(ctx context.Context) (*models.GetCSRFTokenResponse, error) {
 	req := models.GetCSRFTokenRequest{}
-	return client.Call[models.GetCSRFTokenRequest, models.GetCSRFTokenResponse](api.client, "get_csrf_token", req)
+	return call[models.GetCSRFTokenRequest, models.GetCSRFTokenResponse](ctx, api, "get_csrf_token", req)
 
 }
 
@@ -91,13 +91,13 @@ func (api *API) GetCSRFToken() (*models.GetCSRFTokenResponse, error) {
 //   - groupID: 群 ID
 //   - messageID: 消息 ID
 //   - emojiID: 表情 ID
-func (api *API) JoinGroupEmojiChain(groupID int, messageID int, emojiID int) (*models.JoinGroupEmojiChainResponse, error) {
+func (api *API) JoinGroupEmojiChain(ctx context.Context, groupID int, messageID int, emojiID int) (*models.JoinGroupEmojiChainResponse, error) {
 	req := models.JoinGroupEmojiChainRequest{
 		GroupID:   groupID,
 		MessageID: messageID,
 		EmojiID:   emojiID,
 	}
-	return client.Call[models.JoinGroupEmojiChainRequest, models.JoinGroupEmojiChainResponse](api.client, ".join_group_emoji_chain", req)
+	return call[models.JoinGroupEmojiChainRequest, models.JoinGroupEmojiChainResponse](ctx, api, ".join_group_emoji_chain", req)
 
 }
 
@@ -105,11 +105,11 @@ func (api *API) JoinGroupEmojiChain(groupID int, messageID int, emojiID int) (*m
 //
 // 参数：
 //   - image: http/https/file/base64
-func (api *API) OCRImage(image string) (*models.OCRImageResponse, error) {
+func (api *API) OCRImage(ctx context.Context, image string) (*models.OCRImageResponse, error) {
 	req := models.OCRImageRequest{
 		Image: image,
 	}
-	return client.Call[models.OCRImageRequest, models.OCRImageResponse](api.client, "ocr_image", req)
+	return call[models.OCRImageRequest, models.OCRImageResponse](ctx, api, "ocr_image", req)
 
 }
 
@@ -117,11 +117,11 @@ func (api *API) OCRImage(image string) (*models.OCRImageResponse, error) {
 //
 // 参数：
 //   - file: http/https/file/base64
-func (api *API) SetQQAvatar(file string) (*models.SetQQAvatarResponse, error) {
+func (api *API) SetQQAvatar(ctx context.Context, file string) (*models.SetQQAvatarResponse, error) {
 	req := models.SetQQAvatarRequest{
 		File: file,
 	}
-	return client.Call[models.SetQQAvatarRequest, models.SetQQAvatarResponse](api.client, "set_qq_avatar", req)
+	return call[models.SetQQAvatarRequest, models.SetQQAvatarResponse](ctx, api, "set_qq_avatar", req)
 
 }
 
@@ -130,12 +130,12 @@ func (api *API) SetQQAvatar(file string) (*models.SetQQAvatarResponse, error) {
 // 参数：
 //   - userID: 用户 ID
 //   - times: 点赞次数（通常为 1~10）
-func (api *API) SendLike(userID int, times int) (*models.SendLikeResponse, error) {
+func (api *API) SendLike(ctx context.Context, userID int, times int) (*models.SendLikeResponse, error) {
 	req := models.SendLikeRequest{
 		UserID: userID,
 		Times:  times,
 	}
-	return client.Call[models.SendLikeRequest, models.SendLikeResponse](api.client, "send_like", req)
+	return call[models.SendLikeRequest, models.SendLikeResponse](ctx, api, "send_like", req)
 
 }
 
@@ -144,17 +144,17 @@ func (api *API) SendLike(userID int, times int) (*models.SendLikeResponse, error
 // 参数：
 //   - userID: 用户 ID
 //   - block: 是否拉黑（true 表示拉黑该好友）
-func (api *API) DeleteFriend(userID string, block bool) (*models.DeleteFriendResponse, error) {
+func (api *API) DeleteFriend(ctx context.Context, userID string, block bool) (*models.DeleteFriendResponse, error) {
 	req := models.DeleteFriendRequest{
 		UserID: userID,
 		Block:  block,
 	}
-	return client.Call[models.DeleteFriendRequest, models.DeleteFriendResponse](api.client, "delete_friend", req)
+	return call[models.DeleteFriendRequest, models.DeleteFriendResponse](ctx, api, "delete_friend", req)
 
 }
 
 // 获取 rkey
-func (api *API) GetRKey() (*models.GetRKeyResponse, error) {
-	return client.Call[interface{}, models.GetRKeyResponse](api.client, "get_rkey", struct{}{})
+func (api *API) GetRKey(ctx context.Context) (*models.GetRKeyResponse, error) {
+	return call[interface{}, models.GetRKeyResponse](ctx, api, "get_rkey", struct{}{})
 
 }
@@ -24,7 +24,7 @@ func TestFetchCustomFace(t *testing.T) {
 	h := NewGenericTestHelper(t)
 	h.t.Skip("跳过获取自定义表情测试")
 
-	resp, err := h.api.FetchCustomFace()
+	resp, err := h.api.FetchCustomFace(h.ctx)
 	h.StatusOk(resp, err, "获取自定义表情")
 
 	t.Logf("获取自定义表情成功，返回数据: %+v", resp)
@@ -35,7 +35,7 @@ func TestFetchMfaceKey(t *testing.T) {
 	h := NewGenericTestHelper(t)
 	h.t.Skip("跳过获取商城表情key测试")
 
-	// resp, err := h.api.FetchMfaceKey(emojiIDs)
+	// resp, err := h.api.FetchMfaceKey(h.ctx, emojiIDs)
 	// h.StatusOk(resp, err, "获取商城表情key")
 
 	// t.Logf("获取商城表情key成功，表情ID数量: %d, 返回数据: %+v", len(emojiIDs), resp)
@@ -55,7 +55,7 @@ func TestJoinFriendEmojiChain(t *testing.T) {
 	// }
 	// emojiID := 1
 
-	// resp, err := h.api.JoinFriendEmojiChain(userID, messageID, emojiID)
+	// resp, err := h.api.JoinFriendEmojiChain(h.ctx, userID, messageID, emojiID)
 	// h.StatusOk(resp, err, "加入好友表情接龙")
 
 	// t.Logf("加入好友表情接龙成功，用户ID: %d, 消息ID: %d, 表情ID: %d", userID, messageID, emojiID)
@@ -68,7 +68,7 @@ func TestGetAICharacters(t *testing.T) {
 	groupID := GID
 	chatType := 1 // 群聊类型
 
-	resp, err := h.api.GetAICharacters(groupID, chatType)
+	resp, err := h.api.GetAICharacters(h.ctx, groupID, chatType)
 	h.StatusOk(resp, err, "获取群AI声色")
 
 	t.Logf("获取群AI声色成功，群ID: %d, 聊天类型: %d, 返回数据: %+v", groupID, chatType, resp)
@@ -81,7 +81,7 @@ func TestGetCookies(t *testing.T) {
 	h.t.Skip("跳过获取Cookies测试")
 
 	domain := ".qq.com"
-	resp, err := h.api.GetCookies(domain)
+	resp, err := h.api.GetCookies(h.ctx, domain)
 	h.StatusOk(resp, err, "获取Cookies")
 
 	t.Logf("获取Cookies成功，域名: %s, 返回数据: %+v", domain, resp)
@@ -93,7 +93,7 @@ func TestGetCredentials(t *testing.T) {
 	h.t.Skip("跳过获取QQ接口凭证测试")
 
 	domain := ".qq.com"
-	resp, err := h.api.GetCredentials(domain)
+	resp, err := h.api.GetCredentials(h.ctx, domain)
 	h.StatusOk(resp, err, "获取QQ接口凭证")
 
 	t.Logf("获取QQ接口凭证成功，域名: %s, 返回数据: %+v", domain, resp)
@@ -103,7 +103,7 @@ func TestGetCredentials(t *testing.T) {
 func TestGetCSRFToken(t *testing.T) {
 	h := NewGenericTestHelper(t)
 
-	resp, err := h.api.GetCSRFToken()
+	resp, err := h.api.GetCSRFToken(h.ctx)
 	h.StatusOk(resp, err, "获取CSRF Token")
 
 	t.Logf("获取CSRF Token成功，返回数据: %+v", resp)
@@ -121,7 +121,7 @@ func TestJoinGroupEmojiChain(t *testing.T) {
 	// }
 	// emojiID := 1
 
-	// resp, err := h.api.JoinGroupEmojiChain(groupID, messageID, emojiID)
+	// resp, err := h.api.JoinGroupEmojiChain(h.ctx, groupID, messageID, emojiID)
 	// h.StatusOk(resp, err, "加入群表情接龙")
 	// t.Logf("加入群表情接龙成功，群ID: %d, 消息ID: %d, 表情ID: %d", groupID, messageID, emojiID)
 }
@@ -130,7 +130,7 @@ func TestJoinGroupEmojiChain(t *testing.T) {
 func TestOCRImage(t *testing.T) {
 	h := NewGenericTestHelper(t)
 
-	resp, err := h.api.OCRImage(ImageURL)
+	resp, err := h.api.OCRImage(h.ctx, ImageURL)
 	h.StatusOk(resp, err, "OCR图像识别")
 
 	t.Logf("OCR图像识别成功，返回数据: %+v", resp)
@@ -154,7 +154,7 @@ func TestSetQQAvatar(t *testing.T) {
 	// 	return
 	// }
 
-	resp, err := h.api.SetQQAvatar(ImageURL)
+	resp, err := h.api.SetQQAvatar(h.ctx, ImageURL)
 	assert.NoError(t, err)
 
 	t.Logf("设置QQ头像成功，返回数据: %+v", resp)
@@ -168,7 +168,7 @@ func TestSendLike(t *testing.T) {
 	userID := UID
 	times := 1 // 点赞1次
 
-	resp, err := h.api.SendLike(userID, times)
+	resp, err := h.api.SendLike(h.ctx, userID, times)
 	if err != nil {
 		t.Logf("点赞用户资料可能失败（正常现象，可能有频率限制）: %v", err)
 	} else {
@@ -183,7 +183,7 @@ func TestDeleteFriend(t *testing.T) {
 
 	block := false // 不拉黑
 
-	resp, err := h.api.DeleteFriend(strconv.Itoa(UID), block)
+	resp, err := h.api.DeleteFriend(h.ctx, strconv.Itoa(UID), block)
 	h.StatusOk(resp, err, "删除好友")
 
 	t.Logf("删除好友成功，用户ID: %d, 是否拉黑: %v", UID, block)
@@ -193,7 +193,7 @@ func TestDeleteFriend(t *testing.T) {
 func TestGetRKey(t *testing.T) {
 	h := NewGenericTestHelper(t)
 
-	resp, err := h.api.GetRKey()
+	resp, err := h.api.GetRKey(h.ctx)
 	h.StatusOk(resp, err, "获取rkey")
 
 }
@@ -24,11 +24,11 @@ func NewGroupTestHelper(t *testing.T) *GroupTestHelper {
 func (h *GroupTestHelper) createTestNotice(groupID int) (string, func()) {
 	content := "测试公告内容 - " + time.Now().Format("2006-01-02 15:04:05")
 
-	resp, err := h.api.SendGroupNotice(groupID, content, "")
+	resp, err := h.api.SendGroupNotice(h.ctx, groupID, content, "")
 	h.StatusOk(resp, err, "创建测试公告")
 
 	// 获取刚创建的公告ID
-	resp2, err := h.api.GetGroupNotice(groupID)
+	resp2, err := h.api.GetGroupNotice(h.ctx, groupID)
 	h.StatusOk(resp2, err, "获取测试公告ID")
 
 	require.NotEmpty(h.t, resp.Data, "公告列表不能为空")
@@ -37,7 +37,7 @@ func (h *GroupTestHelper) createTestNotice(groupID int) (string, func()) {
 	require.NotEmpty(h.t, noticeID, "公告ID不能为空")
 
 	cleanup := func() {
-		resp, err := h.api.DeleteGroupNotice(groupID, noticeID)
+		resp, err := h.api.DeleteGroupNotice(h.ctx, groupID, noticeID)
 		h.StatusOk(resp, err, "清理测试公告")
 	}
 
@@ -61,7 +61,7 @@ func TestDeleteGroupNotice(t *testing.T) {
 	defer cleanup()
 
 	// 删除公告
-	resp, err := h.api.DeleteGroupNotice(groupID, noticeID)
+	resp, err := h.api.DeleteGroupNotice(h.ctx, groupID, noticeID)
 	h.StatusOk(resp, err, "删除群公告")
 
 	t.Logf("删除群公告成功，群ID: %d, 公告ID: %s", groupID, noticeID)
@@ -72,7 +72,7 @@ func TestGetGroupNotice(t *testing.T) {
 	h := NewGroupTestHelper(t)
 	groupID := GID
 
-	resp, err := h.api.GetGroupNotice(groupID)
+	resp, err := h.api.GetGroupNotice(h.ctx, groupID)
 	h.StatusOk(resp, err, "获取群公告")
 	assert.IsType(t, []interface{}{}, resp.Data, "Data字段应为slice类型")
 
@@ -89,7 +89,7 @@ func TestGetAIRecord(t *testing.T) {
 	text := "你好，这是一个测试语音"
 	chatType := 1
 
-	resp, err := h.api.GetAIRecord(character, groupID, text, chatType)
+	resp, err := h.api.GetAIRecord(h.ctx, character, groupID, text, chatType)
 	h.StatusOk(resp, err, "获取AI语音")
 
 	t.Logf("获取AI语音成功，声色ID: %s, 群ID: %d, 文本: %s", character, groupID, text)
@@ -102,7 +102,7 @@ func TestGetGroupHonorInfo(t *testing.T) {
 	groupID := GID
 	honorType := "all"
 
-	resp, err := h.api.GetGroupHonorInfo(groupID, honorType)
+	resp, err := h.api.GetGroupHonorInfo(h.ctx, groupID, honorType)
 	h.StatusOk(resp, err, "获取群荣耀信息")
 
 	t.Logf("获取群荣耀信息成功，群ID: %d, 荣耀类型: %s", groupID, honorType)
@@ -116,7 +116,7 @@ func TestSetGroupAdmin(t *testing.T) {
 	groupID := GID
 	userID := TID
 
-	resp, err := h.api.SetGroupAdmin(groupID, userID, true)
+	resp, err := h.api.SetGroupAdmin(h.ctx, groupID, userID, true)
 	h.StatusOk(resp, err, "设置群管理员")
 }
 
@@ -125,14 +125,14 @@ func TestSetGroupBan(t *testing.T) {
 
 	h := NewGroupTestHelper(t)
 
-	resp, err := h.api.SetGroupBan(TID, GID, 60)
+	resp, err := h.api.SetGroupBan(h.ctx, TID, GID, 60)
 	h.StatusOk(resp, err, "设置群成员禁言")
 
 	t.Logf("设置群成员禁言成功，用户ID: %d, 群ID: %d, 禁言时长: 60秒", TID, GID)
 
 	// 解除禁言
 	time.Sleep(1 * time.Second)
-	resp2, err2 := h.api.SetGroupBan(TID, GID, 0)
+	resp2, err2 := h.api.SetGroupBan(h.ctx, TID, GID, 0)
 	h.StatusOk(resp2, err2, "解除群成员禁言")
 }
 
@@ -146,7 +146,7 @@ func TestSetGroupBotStatus(t *testing.T) {
 	botID := 123456 // 模拟Bot ID
 	enable := 1     // 启用
 
-	resp, err := h.api.SetGroupBotStatus(groupID, botID, enable)
+	resp, err := h.api.SetGroupBotStatus(h.ctx, groupID, botID, enable)
 	h.StatusOk(resp, err, "设置群Bot发言状态")
 
 	t.Logf("设置群Bot发言状态成功，群ID: %d, BotID: %d, 状态: %d", groupID, botID, enable)
@@ -163,7 +163,7 @@ func TestSendGroupBotCallback(t *testing.T) {
 	data1 := "test_data_1"
 	data2 := "test_data_2"
 
-	resp, err := h.api.SendGroupBotCallback(groupID, botID, data1, data2)
+	resp, err := h.api.SendGroupBotCallback(h.ctx, groupID, botID, data1, data2)
 	h.StatusOk(resp, err, "调用群机器人回调")
 
 	t.Logf("调用群机器人回调成功，群ID: %d, BotID: %d", groupID, botID)
@@ -179,14 +179,14 @@ func TestSetGroupCard(t *testing.T) {
 	testCard := "测试名片-" + time.Now().Format("15:04:05")
 
 	// 设置测试名片
-	resp, err := h.api.SetGroupCard(userID, groupID, testCard)
+	resp, err := h.api.SetGroupCard(h.ctx, userID, groupID, testCard)
 	h.StatusOk(resp, err, "设置群名片")
 
 	t.Logf("设置群名片成功，用户ID: %d, 群ID: %d, 名片: %s", userID, groupID, testCard)
 
 	// 恢复原始名片
 	time.Sleep(1 * time.Second)
-	resp2, err2 := h.api.SetGroupCard(userID, groupID, originalCard)
+	resp2, err2 := h.api.SetGroupCard(h.ctx, userID, groupID, originalCard)
 	h.StatusOk(resp2, err2, "恢复原始名片")
 }
 
@@ -196,7 +196,7 @@ func TestKickGroupMember(t *testing.T) {
 
 	h.t.Skip("跳过踢出群成员测试")
 
-	resp, err := h.api.KickGroupMember(TID, GID, false)
+	resp, err := h.api.KickGroupMember(h.ctx, TID, GID, false)
 	h.StatusOk(resp, err, "踢出群成员")
 
 	t.Logf("踢出群成员成功，用户ID: %d, 群ID: %d", TID, GID)
@@ -208,7 +208,7 @@ func TestLeaveGroup(t *testing.T) {
 
 	h.t.Skip("跳过退出群测试")
 
-	resp, err := h.api.LeaveGroup(GID, false)
+	resp, err := h.api.LeaveGroup(h.ctx, GID, false)
 	h.StatusOk(resp, err, "退出群")
 
 	t.Logf("退出群成功，群ID: %d", GID)
@@ -222,19 +222,19 @@ func TestSendGroupNotice(t *testing.T) {
 	content := "测试公告内容 - " + time.Now().Format("2006-01-02 15:04:05")
 	image := ""
 
-	resp, err := h.api.SendGroupNotice(groupID, content, image)
+	resp, err := h.api.SendGroupNotice(h.ctx, groupID, content, image)
 	h.StatusOk(resp, err, "发送群公告")
 
 	t.Logf("发送群公告成功，群ID: %d, 内容: %s", groupID, content)
 
 	// 清理：删除刚发送的公告
 	time.Sleep(1 * time.Second)
-	resp2, err := h.api.GetGroupNotice(groupID)
+	resp2, err := h.api.GetGroupNotice(h.ctx, groupID)
 	h.StatusOk(resp2, err, "获取公告用于清理")
 
 	assert.NotEmpty(t, resp.Data, "公告列表不为空")
 
-	resp3, cleanupErr := h.api.DeleteGroupNotice(groupID, resp2.Data[0].NoticeID)
+	resp3, cleanupErr := h.api.DeleteGroupNotice(h.ctx, groupID, resp2.Data[0].NoticeID)
 	h.StatusOk(resp3, cleanupErr, "清理测试公告")
 
 }
@@ -243,7 +243,7 @@ func TestSendGroupNotice(t *testing.T) {
 func TestSetGroupName(t *testing.T) {
 	h := NewGroupTestHelper(t)
 
-	resp, err := h.api.SetGroupName(GID, "qq测试群")
+	resp, err := h.api.SetGroupName(h.ctx, GID, "qq测试群")
 	h.StatusOk(resp, err, "设置群名称")
 
 	t.Logf("设置群名称成功，群ID: %d, 名称: qq测试群", GID)
@@ -255,7 +255,7 @@ func TestSetGroupWholeBan(t *testing.T) {
 	groupID := GID
 	enable := true
 
-	resp, err := h.api.SetGroupWholeBan(groupID, enable)
+	resp, err := h.api.SetGroupWholeBan(h.ctx, groupID, enable)
 	h.StatusOk(resp, err, "设置全体禁言")
 
 }
@@ -272,7 +272,7 @@ func TestSetGroupPortrait(t *testing.T) {
 
 	file := "data:image/jpeg;base64," + imageBase64
 
-	resp, err := h.api.SetGroupPortrait(groupID, file)
+	resp, err := h.api.SetGroupPortrait(h.ctx, groupID, file)
 	h.StatusOk(resp, err, "设置群头像")
 
 	t.Logf("设置群头像成功，群ID: %d", groupID)
@@ -291,14 +291,14 @@ func TestSetEmojiReaction(t *testing.T) {
 
 	require.Greater(t, messageID, 0, "消息ID必须大于0")
 
-	resp, err := h.api.SetEmojiReaction(groupID, messageID, code, isAdd)
+	resp, err := h.api.SetEmojiReaction(h.ctx, groupID, messageID, code, isAdd)
 	h.StatusOk(resp, err, "设置群表情回复")
 
 	t.Logf("设置群表情回复成功，群ID: %d, 消息ID: %d, 表情代码: %s", groupID, messageID, code)
 
 	// 移除表情回复
 	time.Sleep(1 * time.Second)
-	resp2, err2 := h.api.SetEmojiReaction(groupID, messageID, code, false)
+	resp2, err2 := h.api.SetEmojiReaction(h.ctx, groupID, messageID, code, false)
 	h.StatusOk(resp2, err2, "移除表情回复")
 }
 
@@ -313,7 +313,7 @@ func TestSetGroupSpecialTitle(t *testing.T) {
 	specialTitle := "测试头衔"
 	duration := 3600 // 1小时
 
-	resp, err := h.api.SetGroupSpecialTitle(groupID, userID, specialTitle, duration)
+	resp, err := h.api.SetGroupSpecialTitle(h.ctx, groupID, userID, specialTitle, duration)
 	h.StatusOk(resp, err, "设置群专属头衔")
 
 	t.Logf("设置群专属头衔成功，群ID: %d, 用户ID: %d, 头衔: %s, 有效期: %d秒",
@@ -321,6 +321,6 @@ func TestSetGroupSpecialTitle(t *testing.T) {
 
 	// 清理：移除头衔
 	time.Sleep(2 * time.Second)
-	resp2, err2 := h.api.SetGroupSpecialTitle(groupID, userID, "", 0)
+	resp2, err2 := h.api.SetGroupSpecialTitle(h.ctx, groupID, userID, "", 0)
 	h.StatusOk(resp2, err2, "移除专属头衔")
 }
@@ -1,13 +1,13 @@
 package api
 
 import (
-	"yora/adapters/onebot/client"
+	"context"
 	"yora/adapters/onebot/models"
 )
 
-func (api *API) GetFriendList() (*models.GetFriendListResponse, error) {
+func (api *API) GetFriendList(ctx context.Context) (*models.GetFriendListResponse, error) {
 	req := models.GetFriendListRequest{}
-	return client.Call[models.GetFriendListRequest, models.GetFriendListResponse](api.client, "get_friend_list", req)
+	return call[models.GetFriendListRequest, models.GetFriendListResponse](ctx, api, "get_friend_list", req)
 
 }
 
@@ -16,12 +16,12 @@ func (api *API) GetFriendList() (*models.GetFriendListResponse, error) {
 // 参数：
 //   - groupID: 群号
 //   - noCache: 是否不使用缓存（true 表示跳过缓存，实时获取）
-func (api *API) GetGroupInfo(groupID int, noCache bool) (*models.GetGroupInfoResponse, error) {
+func (api *API) GetGroupInfo(ctx context.Context, groupID int, noCache bool) (*models.GetGroupInfoResponse, error) {
 	req := models.GetGroupInfoRequest{
 		GroupID: groupID,
 		NoCache: noCache,
 	}
-	return client.Call[models.GetGroupInfoRequest, models.GetGroupInfoResponse](api.client, "get_group_info", req)
+	return call[models.GetGroupInfoRequest, models.GetGroupInfoResponse](ctx, api, "get_group_info", req)
 
 }
 
@@ -29,11 +29,11 @@ func (api *API) GetGroupInfo(groupID int, noCache bool) (*models.GetGroupInfoRes
 //
 // 参数：
 //   - groupID: 群号
-func (api *API) GetGroupMemberList(groupID int) (*models.GetGroupMemberListResponse, error) {
+func (api *API) GetGroupMemberList(ctx context.Context, groupID int) (*models.GetGroupMemberListResponse, error) {
 	req := models.GetGroupMemberListRequest{
 		GroupID: groupID,
 	}
-	return client.Call[models.GetGroupMemberListRequest, models.GetGroupMemberListResponse](api.client, "get_group_member_list", req)
+	return call[models.GetGroupMemberListRequest, models.GetGroupMemberListResponse](ctx, api, "get_group_member_list", req)
 
 }
 
@@ -43,13 +43,13 @@ func (api *API) GetGroupMemberList(groupID int) (*models.GetGroupMemberListRespo
 //   - groupID: 群号
 //   - userID: 用户 QQ 号
 //   - noCache: 是否不使用缓存（true 表示跳过缓存，实时获取）
-func (api *API) GetGroupMemberInfo(groupID int, userID int, noCache bool) (*models.GetGroupMemberInfoResponse, error) {
+func (api *API) GetGroupMemberInfo(ctx context.Context, groupID int, userID int, noCache bool) (*models.GetGroupMemberInfoResponse, error) {
 	req := models.GetGroupMemberInfoRequest{
 		GroupID: groupID,
 		UserID:  userID,
 		NoCache: noCache,
 	}
-	return client.Call[models.GetGroupMemberInfoRequest, models.GetGroupMemberInfoResponse](api.client, "get_group_member_info", req)
+	return call[models.GetGroupMemberInfoRequest, models.GetGroupMemberInfoResponse](ctx, api, "get_group_member_info", req)
 
 }
 
@@ -57,25 +57,25 @@ func (api *API) GetGroupMemberInfo(groupID int, userID int, noCache bool) (*mode
 //
 // 参数：
 //   - noCache: 是否不使用缓存（true 表示跳过缓存，实时获取）
-func (api *API) GetGroupList(noCache bool) (*models.GetGroupListResponse, error) {
+func (api *API) GetGroupList(ctx context.Context, noCache bool) (*models.GetGroupListResponse, error) {
 	req := models.GetGroupListRequest{
 		NoCache: noCache,
 	}
-	return client.Call[models.GetGroupListRequest, models.GetGroupListResponse](api.client, "get_group_list", req)
+	return call[models.GetGroupListRequest, models.GetGroupListResponse](ctx, api, "get_group_list", req)
 
 }
 
 // GetLoginInfo 获取当前登录账号信息
-func (api *API) GetLoginInfo() (*models.GetLoginInfoResponse, error) {
+func (api *API) GetLoginInfo(ctx context.Context) (*models.GetLoginInfoResponse, error) {
 	req := models.GetLoginInfoRequest{}
-	return client.Call[models.GetLoginInfoRequest, models.GetLoginInfoResponse](api.client, "get_login_info", req)
+	return call[models.GetLoginInfoRequest, models.GetLoginInfoResponse](ctx, api, "get_login_info", req)
 
 }
 
 // GetStatus 获取状态信息（包括在线情况、运行时间、插件状态等）
-func (api *API) GetStatus() (*models.GetStatusResponse, error) {
+func (api *API) GetStatus(ctx context.Context) (*models.GetStatusResponse, error) {
 	req := models.GetStatusRequest{}
-	return client.Call[models.GetStatusRequest, models.GetStatusResponse](api.client, "get_status", req)
+	return call[models.GetStatusRequest, models.GetStatusResponse](ctx, api, "get_status", req)
 
 }
 
@@ -84,18 +84,18 @@ func (api *API) GetStatus() (*models.GetStatusResponse, error) {
 // 参数：
 //   - userID: 用户 QQ 号
 //   - noCache: 是否不使用缓存（true 表示跳过缓存，实时获取）
-func (api *API) GetStrangerInfo(userID int, noCache bool) (*models.GetStrangerInfoResponse, error) {
+func (api *API) GetStrangerInfo(ctx context.Context, userID int, noCache bool) (*models.GetStrangerInfoResponse, error) {
 	req := models.GetStrangerInfoRequest{
 		UserID:  userID,
 		NoCache: noCache,
 	}
-	return client.Call[models.GetStrangerInfoRequest, models.GetStrangerInfoResponse](api.client, "get_stranger_info", req)
+	return call[models.GetStrangerInfoRequest, models.GetStrangerInfoResponse](ctx, api, "get_stranger_info", req)
 
 }
 
 // 获取版本信息
-func (api *API) GetVersionInfo() (*models.GetVersionInfoResponse, error) {
+func (api *API) GetVersionInfo(ctx context.Context) (*models.GetVersionInfoResponse, error) {
 	req := models.GetVersionInfoRequest{}
-	return client.Call[models.GetVersionInfoRequest, models.GetVersionInfoResponse](api.client, "get_version_info", req)
+	return call[models.GetVersionInfoRequest, models.GetVersionInfoResponse](ctx, api, "get_version_info", req)
 
 }
@@ -20,7 +20,7 @@ func NewInfoTestHelper(t *testing.T) *InfoTestHelper {
 func TestGetFriendList(t *testing.T) {
 	h := NewInfoTestHelper(t)
 
-	resp, err := h.api.GetFriendList()
+	resp, err := h.api.GetFriendList(h.ctx)
 	h.StatusOk(resp, err, "获取好友列表")
 
 	t.Logf("获取好友列表成功，好友数量: %d", len(resp.Data))
@@ -39,7 +39,7 @@ func TestGetGroupInfo(t *testing.T) {
 	groupID := GID
 
 	// 测试使用缓存
-	resp, err := h.api.GetGroupInfo(groupID, false)
+	resp, err := h.api.GetGroupInfo(h.ctx, groupID, false)
 	h.StatusOk(resp, err, "获取群信息（使用缓存）")
 
 	h.t.Logf("获取群信息成功, 群名 = %s", resp.Data.GroupName)
@@ -52,7 +52,7 @@ func TestGetGroupMemberList(t *testing.T) {
 
 	groupID := GID
 
-	resp, err := h.api.GetGroupMemberList(groupID)
+	resp, err := h.api.GetGroupMemberList(h.ctx, groupID)
 	h.StatusOk(resp, err, "获取群成员列表")
 
 	if len(resp.Data) > 0 {
@@ -70,14 +70,14 @@ func TestGetGroupMemberInfo(t *testing.T) {
 	groupID := GID
 
 	// 测试使用缓存
-	resp, err := h.api.GetGroupMemberInfo(groupID, TID, false)
+	resp, err := h.api.GetGroupMemberInfo(h.ctx, groupID, TID, false)
 	h.StatusOk(resp, err, "获取群成员信息（使用缓存）")
 
 	t.Logf("获取群成员信息成功（使用缓存），群ID: %d, 用户ID: %d, 昵称: %s, 角色: %s",
 		groupID, TID, resp.Data.Nickname, resp.Data.Role)
 
 	// 测试不使用缓存
-	resp2, err2 := h.api.GetGroupMemberInfo(groupID, TID, true)
+	resp2, err2 := h.api.GetGroupMemberInfo(h.ctx, groupID, TID, true)
 	h.StatusOk(resp2, err2, "获取群成员信息（不使用缓存）")
 
 	t.Logf("获取群成员信息成功（不使用缓存），群ID: %d, 用户ID: %d, 昵称: %s, 角色: %s",
@@ -90,7 +90,7 @@ func TestGetGroupList(t *testing.T) {
 	h := NewInfoTestHelper(t)
 
 	// 测试使用缓存
-	resp, err := h.api.GetGroupList(false)
+	resp, err := h.api.GetGroupList(h.ctx, false)
 	h.StatusOk(resp, err, "获取群列表（使用缓存）")
 
 	t.Logf("获取群列表成功（使用缓存），群数量: %d", len(resp.Data))
@@ -101,7 +101,7 @@ func TestGetGroupList(t *testing.T) {
 	}
 
 	// 测试不使用缓存
-	resp2, err2 := h.api.GetGroupList(true)
+	resp2, err2 := h.api.GetGroupList(h.ctx, true)
 	h.StatusOk(resp2, err2, "获取群列表（不使用缓存）")
 
 	t.Logf("获取群列表成功（不使用缓存），群数量: %d", len(resp2.Data))
@@ -111,7 +111,7 @@ func TestGetGroupList(t *testing.T) {
 func TestGetLoginInfo(t *testing.T) {
 	h := NewInfoTestHelper(t)
 
-	resp, err := h.api.GetLoginInfo()
+	resp, err := h.api.GetLoginInfo(h.ctx)
 	h.StatusOk(resp, err, "获取当前登录账号信息")
 
 	t.Logf("获取当前登录账号信息成功，用户ID: %d, 昵称: %s",
@@ -122,7 +122,7 @@ func TestGetLoginInfo(t *testing.T) {
 func TestGetStatus(t *testing.T) {
 	h := NewInfoTestHelper(t)
 
-	resp, err := h.api.GetStatus()
+	resp, err := h.api.GetStatus(h.ctx)
 	h.StatusOk(resp, err, "获取状态信息")
 
 	t.Logf("获取状态信息成功，在线状态: %v, 运行状态: %v",
@@ -137,14 +137,14 @@ func TestGetStrangerInfo(t *testing.T) {
 	userID := TID
 
 	// 测试使用缓存
-	resp, err := h.api.GetStrangerInfo(userID, false)
+	resp, err := h.api.GetStrangerInfo(h.ctx, userID, false)
 	h.StatusOk(resp, err, "获取陌生人信息（使用缓存）")
 
 	t.Logf("获取陌生人信息成功（使用缓存），用户ID: %d, 昵称: %s, 性别: %s, 年龄: %d",
 		userID, resp.Data.Nickname, resp.Data.Sex, resp.Data.Age)
 
 	// 测试不使用缓存
-	resp2, err2 := h.api.GetStrangerInfo(userID, true)
+	resp2, err2 := h.api.GetStrangerInfo(h.ctx, userID, true)
 	h.StatusOk(resp2, err2, "获取陌生人信息（不使用缓存）")
 
 	t.Logf("获取陌生人信息成功（不使用缓存），用户ID: %d, 昵称: %s, 性别: %s, 年龄: %d",
@@ -155,7 +155,7 @@ func TestGetStrangerInfo(t *testing.T) {
 func TestGetVersionInfo(t *testing.T) {
 	h := NewInfoTestHelper(t)
 
-	resp, err := h.api.GetVersionInfo()
+	resp, err := h.api.GetVersionInfo(h.ctx)
 	h.StatusOk(resp, err, "获取版本信息")
 
 	t.Logf("获取版本信息成功，应用名: %s, 版本: %s, 协议版本: %s",
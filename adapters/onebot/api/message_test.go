@@ -23,14 +23,14 @@ func NewMessageTestHelper(t *testing.T) *MessageTestHelper {
 
 // 发送群消息并获取消息ID
 func (h *MessageTestHelper) sendGroupMessageAndGetID() (int, func()) {
-	resp, err := h.api.SendMessage(0, GID, messages.New("测试消息"))
+	resp, err := h.api.SendMessage(h.ctx, 0, GID, messages.New("测试消息"))
 	h.StatusOk(resp, err, "发送群消息")
 
 	// 等待消息发送成功
 	time.Sleep(time.Second * 2)
 
 	callback := func() {
-		resp2, err := h.api.DeleteMessage(resp.Data.MessageID)
+		resp2, err := h.api.DeleteMessage(h.ctx, resp.Data.MessageID)
 		h.StatusOk(resp2, err, "撤回消息")
 	}
 
@@ -40,13 +40,13 @@ func (h *MessageTestHelper) sendGroupMessageAndGetID() (int, func()) {
 
 // 发送私聊消息并获取消息ID
 func (h *MessageTestHelper) sendPrivateMessageAndGetID() (int, func()) {
-	resp, err := h.api.SendMessage(UID, 0, messages.New("测试消息"))
+	resp, err := h.api.SendMessage(h.ctx, UID, 0, messages.New("测试消息"))
 	h.StatusOk(resp, err, "发送私聊消息")
 
 	time.Sleep(time.Second * 3)
 
 	callback := func() {
-		resp2, err := h.api.DeleteMessage(resp.Data.MessageID)
+		resp2, err := h.api.DeleteMessage(h.ctx, resp.Data.MessageID)
 		h.StatusOk(resp2, err, "撤回消息")
 	}
 
@@ -62,7 +62,7 @@ func TestDeleteEssenceMessage(t *testing.T) {
 	messageID := 12345
 
 	// 执行测试
-	resp, err := h.api.DeleteEssenceMessage(messageID)
+	resp, err := h.api.DeleteEssenceMessage(h.ctx, messageID)
 	h.StatusOk(resp, err, "删除精华消息")
 
 }
@@ -73,12 +73,12 @@ func TestDeleteMessage(t *testing.T) {
 	// 测试撤回群消息
 	h := NewMessageTestHelper(t)
 	// mid, _ := h.sendGroupMessageAndGetID()
-	// resp, err := h.api.DeleteMessage(mid)
+	// resp, err := h.api.DeleteMessage(h.ctx, mid)
 	// h.StatusOk(resp, err, "撤回群聊消息")
 
 	// 测试撤回私聊消息
 	pid, _ := h.sendPrivateMessageAndGetID()
-	resp2, err2 := h.api.DeleteMessage(pid)
+	resp2, err2 := h.api.DeleteMessage(h.ctx, pid)
 	h.StatusOk(resp2, err2, "撤回私聊消息")
 
 }
@@ -90,7 +90,7 @@ func TestPrivatePoke(t *testing.T) {
 	h.t.Skip("私聊戳一戳接口未实现")
 
 	// 执行测试
-	resp, err := h.api.PrivatePoke(UID)
+	resp, err := h.api.PrivatePoke(h.ctx, UID)
 	h.StatusOk(resp, err, "私聊戳一戳")
 
 }
@@ -100,7 +100,7 @@ func TestGetEssenceMessageList(t *testing.T) {
 	h := NewMessageTestHelper(t)
 
 	// 执行测试
-	resp, err := h.api.GetEssenceMessageList(GID)
+	resp, err := h.api.GetEssenceMessageList(h.ctx, GID)
 
 	// 验证结果
 	h.StatusOk(resp, err, "获取精华消息列表应该成功")
@@ -115,7 +115,7 @@ func TestGetForwardMessage(t *testing.T) {
 	messageID := "test_forward_id"
 
 	// 执行测试
-	resp, err := h.api.GetForwardMessage(messageID)
+	resp, err := h.api.GetForwardMessage(h.ctx, messageID)
 
 	// 验证结果
 	h.StatusOk(resp, err, "获取合并转发消息应该成功")
@@ -135,7 +135,7 @@ func TestGetFriendChatHistory(t *testing.T) {
 	count := 20
 
 	// 执行测试
-	resp, err := h.api.GetFriendChatHistory(userID, mid, count)
+	resp, err := h.api.GetFriendChatHistory(h.ctx, userID, mid, count)
 
 	// 验证结果
 	h.StatusOk(resp, err, "获取好友历史聊天记录应该成功")
@@ -151,7 +151,7 @@ func TestGetGroupChatHistory(t *testing.T) {
 	defer cleanup()
 
 	// 执行测试
-	resp, err := h.api.GetGroupChatHistory(GID, strconv.Itoa(messageID), count)
+	resp, err := h.api.GetGroupChatHistory(h.ctx, GID, strconv.Itoa(messageID), count)
 
 	// 验证结果
 	h.StatusOk(resp, err, "获取群历史聊天记录应该成功")
@@ -167,7 +167,7 @@ func TestGetMessage(t *testing.T) {
 	defer callback()
 
 	// 执行测试
-	resp, err := h.api.GetMessage(mid)
+	resp, err := h.api.GetMessage(h.ctx, mid)
 
 	// 验证结果
 	h.StatusOk(resp, err, "获取消息应该成功")
@@ -183,7 +183,7 @@ func TestGroupPoke(t *testing.T) {
 	userID := UID
 
 	// 执行测试
-	resp, err := h.api.GroupPoke(groupID, userID)
+	resp, err := h.api.GroupPoke(h.ctx, groupID, userID)
 
 	// 验证结果
 	h.StatusOk(resp, err, "群里戳一戳应该成功")
@@ -197,7 +197,7 @@ func TestMarkMessageAsRead(t *testing.T) {
 	defer callback()
 
 	// 执行测试
-	resp, err := h.api.MarkMessageAsRead(mid)
+	resp, err := h.api.MarkMessageAsRead(h.ctx, mid)
 
 	h.StatusOk(resp, err, "标记消息为已读")
 
@@ -214,7 +214,7 @@ func TestConstructForwardMessage(t *testing.T) {
 	msgs.AddNode(strconv.Itoa(UID), "张三").AddContentToLast(messages.NewAtSegment(strconv.Itoa(TID)))
 
 	// 执行测试
-	resp, err := h.api.ConstructForwardMessage(msgs.Messages)
+	resp, err := h.api.ConstructForwardMessage(h.ctx, msgs.Messages)
 
 	// 验证结果
 	h.StatusOk(resp, err, "构造合并转发消息应该成功")
@@ -232,7 +232,7 @@ func TestSendGroupAIVoice(t *testing.T) {
 	chatType := 1
 
 	// 执行测试
-	resp, err := h.api.SendGroupAIVoice(character, groupID, text, chatType)
+	resp, err := h.api.SendGroupAIVoice(h.ctx, character, groupID, text, chatType)
 
 	// 验证结果
 	h.StatusOk(resp, err, "发送群AI语音应该成功")
@@ -250,7 +250,7 @@ func TestSendGroupForwardMessage(t *testing.T) {
 	msgs.AddNode(strconv.Itoa(UID), "张三").AddContentToLast(messages.NewAtSegment(strconv.Itoa(TID)))
 
 	// 执行测试
-	resp, err := h.api.SendGroupForwardMessage(groupID, msgs.Messages)
+	resp, err := h.api.SendGroupForwardMessage(h.ctx, groupID, msgs.Messages)
 
 	// 验证结果
 	h.StatusOk(resp, err, "发送群聊合并转发消息应该成功")
@@ -267,7 +267,7 @@ func TestSendMessage(t *testing.T) {
 	msg := messages.NewMessageBuilder().Append(messages.NewAtSegment(strconv.Itoa(TID))).Append(messages.NewAtSegment(strconv.Itoa(UID)))
 
 	// 执行测试
-	resp, err := h.api.SendMessage(userID, groupID, msg)
+	resp, err := h.api.SendMessage(h.ctx, userID, groupID, msg)
 
 	// 验证结果
 	h.StatusOk(resp, err, "发送消息应该成功")
@@ -284,7 +284,7 @@ func TestSendPrivateForwardMessage(t *testing.T) {
 	msgs.AddNode(strconv.Itoa(UID), "张三").AddContentToLast(messages.NewAtSegment(strconv.Itoa(TID)))
 
 	// 执行测试
-	resp, err := h.api.SendPrivateForwardMessage(userID, msgs.Messages)
+	resp, err := h.api.SendPrivateForwardMessage(h.ctx, userID, msgs.Messages)
 
 	// 验证结果
 	h.StatusOk(resp, err, "发送私聊合并转发消息应该成功")
@@ -299,7 +299,7 @@ func TestSetEssenceMessage(t *testing.T) {
 	defer call()
 
 	// 执行测试
-	resp, err := h.api.SetEssenceMessage(mid)
+	resp, err := h.api.SetEssenceMessage(h.ctx, mid)
 
 	// 验证结果
 	h.StatusOk(resp, err, "设置精华消息应该成功")
@@ -313,7 +313,7 @@ func TestSendPrivateMessage(t *testing.T) {
 	userID := UID
 
 	// 执行测试
-	resp, err := h.api.SendPrivateMessage(userID, messages.New("测试"))
+	resp, err := h.api.SendPrivateMessage(h.ctx, userID, messages.New("测试"))
 
 	// 验证结果
 	h.StatusOk(resp, err, "发送私聊消息应该成功")
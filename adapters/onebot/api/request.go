@@ -1,28 +1,40 @@
 package api
 
 import (
-	"yora/adapters/onebot/client"
+	"context"
 	"yora/adapters/onebot/models"
 )
 
 // 处理加好友请求
-func (api *API) SetFriendAdd(flag string, approve bool, reason string) error {
+//
+// 参数：
+//   - flag: 请求标识
+//   - approve: 是否同意
+//   - remark: 同意后的好友备注
+func (api *API) SetFriendAdd(ctx context.Context, flag string, approve bool, remark string) error {
 	req := models.SetFriendAddRequest{
 		Flag:    flag,
 		Approve: approve,
-		Reason:  reason,
+		Remark:  remark,
 	}
-	_, err := client.Call[models.SetFriendAddRequest, interface{}](api.client, "set_friend_add", req)
+	_, err := call[models.SetFriendAddRequest, interface{}](ctx, api, "set_friend_add", req)
 	return err
 }
 
 // 处理加群请求/邀请
-func (api *API) SetGroupAdd(flag string, approve bool, reason string) error {
+//
+// 参数：
+//   - flag: 请求标识
+//   - subType: add 或 invite
+//   - approve: 是否同意
+//   - reason: 拒绝理由
+func (api *API) SetGroupAdd(ctx context.Context, flag string, subType string, approve bool, reason string) error {
 	req := models.SetGroupAddRequest{
 		Flag:    flag,
+		SubType: subType,
 		Approve: approve,
 		Reason:  reason,
 	}
-	_, err := client.Call[models.SetGroupAddRequest, interface{}](api.client, "set_group_add", req)
+	_, err := call[models.SetGroupAddRequest, interface{}](ctx, api, "set_group_add", req)
 	return err
 }
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// 校验请求携带的 access_token，支持 Authorization: Bearer 与 ?access_token= 两种方式
+func checkAccessToken(r *http.Request, token string) bool {
+	got := r.URL.Query().Get("access_token")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if t, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			got = t
+		} else if t, ok := strings.CutPrefix(auth, "Token "); ok {
+			got = t
+		}
+	}
+	if got == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAccessToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/onebot/v11/ws", nil)
+	assert.False(t, checkAccessToken(req, "token"))
+
+	req.Header.Set("Authorization", "Bearer token")
+	assert.True(t, checkAccessToken(req, "token"))
+
+	req.Header.Set("Authorization", "Bearer wrong")
+	assert.False(t, checkAccessToken(req, "token"))
+
+	req = httptest.NewRequest(http.MethodGet, "/onebot/v11/ws?access_token=token", nil)
+	assert.True(t, checkAccessToken(req, "token"))
+}
+
+func TestHandleWebSocketRejectsUnauthorized(t *testing.T) {
+	c := newClient(context.Background()).SetConfig(Config{AccessToken: "token"})
+
+	req := httptest.NewRequest(http.MethodGet, "/onebot/v11/ws?access_token=wrong", nil)
+	w := httptest.NewRecorder()
+	c.HandleWebSocket(w, req, func([]byte) {})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
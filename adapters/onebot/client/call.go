@@ -1,7 +1,10 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 	"yora/adapters/onebot/messages"
@@ -10,11 +13,21 @@ import (
 )
 
 func Call[ReqType any, RespType any](c *Client, action string, req ReqType) (*RespType, error) {
+	return CallContext[ReqType, RespType](context.Background(), c, action, req)
+}
+
+// CallContext 同 Call，ctx 用于取消调用、设置超时及指定发送优先级
+func CallContext[ReqType any, RespType any](ctx context.Context, c *Client, action string, req ReqType) (*RespType, error) {
 
 	l := log.NewAPI("Call")
 
 	// 2. 调用 c.CallAPI 发送请求
-	apiResp, err := c.CallAPI(action, req)
+	apiResp, err := c.CallAPIContext(ctx, action, req)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		l.Warn().Err(err).Int("retcode", apiErr.RetCode).Msgf("API %s 返回失败", action)
+		return nil, err
+	}
 	if err != nil {
 		l.Error().Err(err).Msgf("调用 API %s 失败", action)
 		return nil, fmt.Errorf("调用 API %s 失败: %w", action, err)
@@ -23,9 +36,6 @@ func Call[ReqType any, RespType any](c *Client, action string, req ReqType) (*Re
 		l.Error().Msgf("API %s 调用失败: 响应为空", action)
 		return nil, fmt.Errorf("API %s 调用失败: 响应为空", action)
 	}
-	// if apiResp.Status != "ok" {
-	// 	return nil, fmt.Errorf("API %s 调用失败: %s", action, apiResp.Status)
-	// }
 
 	// 3. 将通用的 API 响应转换为特定的响应结构体
 	specificResp, err := convertStruct[any, RespType](apiResp)
@@ -39,6 +49,11 @@ func Call[ReqType any, RespType any](c *Client, action string, req ReqType) (*Re
 
 // 发送消息
 func (c *Client) Send(userID int, GroupId int, message messages.Message) (*models.SendMessageResponse, error) {
+	return c.SendContext(context.Background(), userID, GroupId, message)
+}
+
+// 发送消息，经发送队列限速，优先级由 WithPriority 指定
+func (c *Client) SendContext(ctx context.Context, userID int, GroupId int, message messages.Message) (*models.SendMessageResponse, error) {
 	messageType := "private"
 	if GroupId != 0 {
 		messageType = "group"
@@ -50,11 +65,61 @@ func (c *Client) Send(userID int, GroupId int, message messages.Message) (*model
 		GroupID:     &GroupId,
 		Message:     message,
 	}
-	return Call[models.MessageRequest, models.SendMessageResponse](c, "send_msg", req)
+	return CallContext[models.MessageRequest, models.SendMessageResponse](ctx, c, "send_msg", req)
 
 }
 
 func (c *Client) CallAPI(action string, params any) (*models.Response[any], error) {
+	return c.CallAPIContext(context.Background(), action, params)
+}
+
+// CallAPIContext 调用 API，发送消息类 API 进入发送队列，按会话限速并在暂时性失败时重试
+//
+// ctx 未设置截止时间时使用配置中该 API 的默认超时；ctx 取消或连接断开时立即返回。
+// 响应 status 不为 ok 时同时返回响应与 *APIError。
+func (c *Client) CallAPIContext(ctx context.Context, action string, params any) (*models.Response[any], error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		resp *models.Response[any]
+		err  error
+	)
+	if sendActions[action] {
+		resp, err = c.sched.submit(ctx, parseSendTarget(params), func() (*models.Response[any], error) {
+			return c.callAPI(ctx, action, params)
+		})
+	} else {
+		resp, err = c.callAPI(ctx, action, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, newAPIError(action, resp)
+}
+
+func (c *Client) callAPI(ctx context.Context, action string, params any) (*models.Response[any], error) {
+	cfg := c.Config()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.TimeoutFor(action))
+		defer cancel()
+	}
+
+	params = formatParams(cfg.MessageFormat, action, params)
+	if cfg.Mode == ModeHTTP {
+		return c.callHTTP(ctx, cfg, action, params)
+	}
+
+	// 未连接时立即失败，不必等待超时
+	c.mu.RLock()
+	connCtx := c.connCtx
+	c.mu.RUnlock()
+	if connCtx == nil || !c.IsConnected() {
+		return nil, notSent(fmt.Errorf("API %s: %w", action, ErrDisconnected))
+	}
+
 	echo := fmt.Sprintf("%s-%d", action, time.Now().UnixNano())
 
 	request := models.APIRequest{
@@ -63,48 +128,74 @@ func (c *Client) CallAPI(action string, params any) (*models.Response[any], erro
 		Echo:   echo,
 	}
 
+	// 响应通道带缓冲且不关闭，迟到的响应不会阻塞或引发 panic
 	ch := make(chan *models.Response[any], 1)
 	c.pending.Store(echo, ch)
-
-	// 设置清理函数
-	cleanup := func() {
-		if chVal, exists := c.pending.Load(echo); exists {
-			c.pending.Delete(echo)
-			if c, ok := chVal.(chan *models.Response[any]); ok {
-				close(c) // 关闭通道避免 goroutine 泄露
-			}
-		}
-	}
+	defer c.pending.Delete(echo)
 
 	// 发送请求
 	select {
 	case c.sendCh <- request:
 		c.logger.Debug().Msgf("发送 API 请求: %s (echo: %s)", action, echo)
-	default:
-		cleanup()
-		c.logger.Error().Msg("发送队列已满，丢弃消息")
-		return nil, fmt.Errorf("发送队列已满")
+	case <-connCtx.Done():
+		return nil, notSent(fmt.Errorf("API %s: %w", action, ErrDisconnected))
+	case <-ctx.Done():
+		c.logger.Error().Str("API", action).Msg("发送队列已满，请求未能发出")
+		return nil, notSent(fmt.Errorf("API %s 请求未能发出: %w", action, ctx.Err()))
 	}
 
-	// 等待响应（带超时）
-	timeout := time.NewTimer(10 * time.Second)
-	defer timeout.Stop()
-
+	// 等待响应，连接断开时立即失败
 	select {
-	case resp, ok := <-ch:
-		cleanup()
-		if !ok {
-			c.logger.Error().Msg("通道已关闭")
-			return nil, fmt.Errorf("通道已关闭")
-		}
+	case resp := <-ch:
 		c.logger.Debug().Msgf("收到 API 响应: %s (echo: %s) %v", action, echo, resp.Data)
-
 		return resp, nil
-	case <-timeout.C:
-		cleanup()
-		c.logger.Error().Msg("等待响应超时")
-		return nil, fmt.Errorf("等待响应超时")
+	case <-connCtx.Done():
+		c.logger.Warn().Str("API", action).Msg("连接断开，放弃等待响应")
+		return nil, fmt.Errorf("API %s: %w", action, ErrDisconnected)
+	case <-ctx.Done():
+		c.logger.Error().Str("API", action).Err(ctx.Err()).Msg("等待响应超时或已取消")
+		return nil, fmt.Errorf("API %s 等待响应失败: %w", action, ctx.Err())
+	}
+}
+
+// 按配置的消息格式转换发送消息的参数：字符串格式时 message 字段与合并转发节点的 content 转为 CQ 码
+func formatParams(format, action string, params any) any {
+	if format != FormatString || !sendActions[action] || params == nil {
+		return params
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return params
+	}
+	var m map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // 保持ID等大整数的原样
+	if err := dec.Decode(&m); err != nil {
+		return params
+	}
+
+	if msg, ok := m["message"]; ok {
+		m["message"] = cqString(msg)
+	}
+	if nodes, ok := m["messages"].([]any); ok {
+		for _, node := range nodes {
+			seg, _ := node.(map[string]any)
+			data, _ := seg["data"].(map[string]any)
+			if content, ok := data["content"]; ok {
+				data["content"] = cqString(content)
+			}
+		}
+	}
+	return m
+}
+
+// 将 JSON 格式的消息转为 CQ 码，已是字符串时原样返回
+func cqString(v any) any {
+	if s, ok := v.(string); ok {
+		return s
 	}
+	return messages.New(v).CQString()
 }
 
 func convertStruct[A any, B any](input A) (B, error) {
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yora/adapters/onebot/messages"
+	"yora/adapters/onebot/models"
+	"yora/pkg/conf"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 启动正向 WebSocket 服务端，serve 处理每个 API 请求，返回 false 时断开连接
+func connectTestServer(t *testing.T, serve func(conn *websocket.Conn, req models.APIRequest) bool) *Client {
+	return connectTestServerWith(t, func([]byte) {}, serve)
+}
+
+// 同 connectTestServer，handle 处理收到的事件
+func connectTestServerWith(t *testing.T, handle func([]byte), serve func(conn *websocket.Conn, req models.APIRequest) bool) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req models.APIRequest
+			if err := conn.ReadJSON(&req); err != nil || !serve(conn, req) {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := newClient(ctx).SetConfig(Config{
+		Mode:              ModeForwardWS,
+		URL:               "ws" + strings.TrimPrefix(server.URL, "http"),
+		ReconnectInterval: time.Hour,
+	})
+	require.NoError(t, c.Connect(ctx, handle))
+	require.Eventually(t, c.IsConnected, 3*time.Second, 10*time.Millisecond)
+	return c
+}
+
+func TestCallAPIOverWebSocket(t *testing.T) {
+	c := connectTestServer(t, func(conn *websocket.Conn, req models.APIRequest) bool {
+		conn.WriteJSON(map[string]any{"status": "ok", "retcode": 0, "echo": req.Echo, "data": req.Action})
+		return true
+	})
+
+	resp, err := c.CallAPIContext(context.Background(), "get_status", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "get_status", resp.Data)
+}
+
+func TestCallAPIWhileEventHandlerBlocked(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	received := make(chan struct{}, 1)
+
+	c := connectTestServerWith(t, func([]byte) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release // 模拟分发队列已满
+	}, func(conn *websocket.Conn, req models.APIRequest) bool {
+		conn.WriteJSON(map[string]any{"post_type": "message", "message_type": "group"})
+		conn.WriteJSON(map[string]any{"post_type": "message", "message_type": "group"})
+		conn.WriteJSON(map[string]any{"status": "ok", "retcode": 0, "echo": req.Echo})
+		return true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := c.CallAPIContext(ctx, "get_status", nil)
+	require.NoError(t, err, "事件处理阻塞时仍应收到 API 响应")
+	<-received
+}
+
+func TestCallAPIHonoursContext(t *testing.T) {
+	c := connectTestServer(t, func(*websocket.Conn, models.APIRequest) bool { return true }) // 从不响应
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.CallAPIContext(ctx, "get_status", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = c.CallAPIContext(ctx, "get_status", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCallAPIFailsFastOnDisconnect(t *testing.T) {
+	c := connectTestServer(t, func(*websocket.Conn, models.APIRequest) bool { return false }) // 收到请求后断开
+
+	start := time.Now()
+	_, err := c.CallAPIContext(context.Background(), "upload_group_file", nil)
+	assert.ErrorIs(t, err, ErrDisconnected)
+	assert.Less(t, time.Since(start), 3*time.Second, "不应等待 5 分钟的上传超时")
+
+	_, err = c.CallAPI("get_status", nil)
+	assert.ErrorIs(t, err, ErrDisconnected, "断开后立即失败")
+}
+
+func TestCallAPINotConnected(t *testing.T) {
+	c := newClient(context.Background())
+	_, err := c.CallAPI("get_status", nil)
+	assert.ErrorIs(t, err, ErrDisconnected)
+}
+
+func TestTimeoutFor(t *testing.T) {
+	cfg := Config{Timeout: 3 * time.Second, ActionTimeouts: map[string]time.Duration{"get_status": time.Second}}.withDefaults()
+
+	assert.Equal(t, time.Second, cfg.TimeoutFor("get_status"))
+	assert.Equal(t, 3*time.Second, cfg.TimeoutFor("get_login_info"))
+	assert.Equal(t, 5*time.Minute, cfg.TimeoutFor("upload_group_file"), "保留默认的上传超时")
+}
+
+func TestConfigFrom(t *testing.T) {
+	cfg := ConfigFrom(conf.OneBotConfig{
+		Timeout:        3 * time.Second,
+		ActionTimeouts: map[string]time.Duration{"get_status": time.Second},
+		Send:           conf.OneBotSendConfig{GlobalRate: 1, UserBurst: 1, Jitter: -1, MaxRetries: -1},
+	})
+
+	assert.Equal(t, time.Second, cfg.TimeoutFor("get_status"))
+	assert.Equal(t, 3*time.Second, cfg.TimeoutFor("get_login_info"))
+	assert.Equal(t, 5*time.Minute, cfg.TimeoutFor("upload_group_file"), "保留默认的上传超时")
+
+	def := DefaultSendConfig()
+	assert.Equal(t, 1.0, cfg.Send.GlobalRate)
+	assert.Equal(t, 1, cfg.Send.UserBurst)
+	assert.Equal(t, time.Duration(-1), cfg.Send.Jitter, "负数表示不启用")
+	assert.Equal(t, -1, cfg.Send.MaxRetries)
+	assert.Equal(t, def.GroupRate, cfg.Send.GroupRate, "未填写的字段使用默认值")
+	assert.Equal(t, def.RetryRetcodes, cfg.Send.RetryRetcodes)
+}
+
+func TestCallAPIOverHTTPHonoursContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": "ok"})
+	}))
+	defer server.Close()
+
+	c := newClient(context.Background()).SetConfig(Config{Mode: ModeHTTP, URL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.CallAPIContext(ctx, "get_status", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCallAPIReturnsAPIError(t *testing.T) {
+	c := connectTestServer(t, func(conn *websocket.Conn, req models.APIRequest) bool {
+		conn.WriteJSON(map[string]any{"status": "failed", "retcode": 1404, "echo": req.Echo, "message": "API 不存在"})
+		return true
+	})
+
+	resp, err := c.CallAPIContext(context.Background(), "send_group_forward_msg", map[string]any{"group_id": 1})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1404, apiErr.RetCode)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, "failed", resp.Status)
+}
+
+func TestStringFormatAppliesToAllSendAPIs(t *testing.T) {
+	requests := make(chan []byte, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- body
+		json.NewEncoder(w).Encode(map[string]any{"status": "ok", "retcode": 0, "data": map[string]any{"message_id": 1}})
+	}))
+	defer server.Close()
+
+	cfg := Config{Mode: ModeHTTP, URL: server.URL, MessageFormat: FormatString}
+	cfg.Send = fastSendConfig()
+	c := newClient(context.Background()).SetConfig(cfg)
+	msg := messages.Message{messages.NewAtSegment("1"), messages.NewTextSegment("hi")}
+
+	gid := 123456789012
+	_, err := c.SendContext(context.Background(), 0, gid, msg)
+	require.NoError(t, err)
+	_, err = c.CallAPI("send_group_msg", map[string]any{"group_id": gid, "message": msg})
+	require.NoError(t, err)
+	_, err = c.CallAPI("send_group_forward_msg", map[string]any{
+		"group_id": gid,
+		"messages": []any{map[string]any{"type": "node", "data": map[string]any{"name": "n", "uin": "1", "content": msg}}},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		body := string(<-requests)
+		assert.Contains(t, body, `[CQ:at,qq=1]hi`)
+		assert.Contains(t, body, `"group_id":123456789012`)
+		assert.NotContains(t, body, `"type":"at"`)
+	}
+}
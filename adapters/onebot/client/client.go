@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -23,6 +24,12 @@ var upgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+// 每个连接缓冲的待分发事件数，缓冲满时丢弃新事件
+const eventBufferSize = 1024
+
+// ErrDisconnected 连接未建立或已断开，等待中的 API 请求会立即以此失败
+var ErrDisconnected = errors.New("OneBot 连接未建立或已断开")
+
 var (
 	clientOnce     sync.Once
 	clientInstance *Client
@@ -36,10 +43,13 @@ func GetClient(ctx context.Context) *Client {
 }
 
 type Client struct {
+	selfID  string // 连接所属账号，未知时为空
+	config  Config // 连接配置
 	pending sync.Map
 	logger  zerolog.Logger
 	conn    *websocket.Conn
 	sendCh  chan any
+	sched   *scheduler // 发送消息的限速队列
 	ctx     context.Context
 	mu      sync.RWMutex
 
@@ -54,23 +64,62 @@ type Client struct {
 		activeGoroutines int64 // 当前活跃的goroutine数量
 		messagesSent     int64 // 已发送的消息数量
 		messagesReceived int64 // 已接收的消息数量
+		eventsDropped    int64 // 事件缓冲已满时丢弃的事件数量
 		reconnectCount   int64 // 重连次数
 	}
 }
 
 func newClient(ctx context.Context) *Client {
 	log := log.NewAPI("api")
-	return &Client{
+	c := &Client{
 		logger:     log,
+		config:     DefaultConfig(),
 		sendCh:     make(chan any, 100),
+		sched:      newScheduler(DefaultSendConfig()),
 		ctx:        ctx,
 		pending:    sync.Map{},
 		connClosed: 1, // 初始状态为已关闭
 	}
+	// 客户端上下文结束时关闭发送队列，避免调度 goroutine 泄漏
+	if ctx != nil {
+		context.AfterFunc(ctx, c.sched.close)
+	}
+	return c
+}
+
+// 设置连接配置
+func (c *Client) SetConfig(cfg Config) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = cfg.withDefaults()
+	c.sched.setConfig(c.config.Send)
+	return c
+}
+
+// 连接所属账号
+func (c *Client) SelfID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.selfID
+}
+
+// 获取连接配置
+func (c *Client) Config() Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config
 }
 
 // HandleWebSocket 用于处理 OneBot 反向连接
 func (c *Client) HandleWebSocket(w http.ResponseWriter, r *http.Request, handleReceivedMessage func(message []byte)) {
+	if token := c.Config().AccessToken; token != "" && !checkAccessToken(r, token) {
+		c.logger.Warn().
+			Str("客户端IP", r.RemoteAddr).
+			Msg("WebSocket 连接 access_token 校验失败")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		c.logger.Error().Err(err).Msg("WebSocket升级失败")
@@ -121,6 +170,10 @@ func (c *Client) connectionManager(handleReceivedMessage func(message []byte)) {
 	atomic.AddInt64(&c.metrics.activeGoroutines, 1)
 	defer atomic.AddInt64(&c.metrics.activeGoroutines, -1)
 
+	c.mu.RLock()
+	cancel := c.connCancel
+	c.mu.RUnlock()
+
 	c.logger.Debug().Msg("连接管理器启动")
 
 	// 启动发送循环
@@ -147,8 +200,9 @@ func (c *Client) connectionManager(handleReceivedMessage func(message []byte)) {
 		c.logger.Info().Msg("连接context取消，连接管理器退出")
 	}
 
-	// 确保连接被关闭
+	// 确保连接被关闭，并通知发送循环退出
 	c.closeConnection()
+	cancel()
 
 	// 等待所有goroutine结束
 	<-sendDone
@@ -163,6 +217,11 @@ func (c *Client) receiveLoop(handleReceivedMessage func(message []byte)) {
 
 	c.logger.Debug().Msg("接收循环启动")
 
+	// 事件由单独的 goroutine 按顺序交给分发器，分发器阻塞时接收循环仍能读取 API 响应
+	events := make(chan []byte, eventBufferSize)
+	defer close(events)
+	go c.eventLoop(events, handleReceivedMessage)
+
 	for {
 		select {
 		case <-c.connCtx.Done():
@@ -216,12 +275,15 @@ func (c *Client) receiveLoop(handleReceivedMessage func(message []byte)) {
 			// API响应消息
 			c.handleAPIResponse(message, echo)
 		} else if _, ok := baseResp["post_type"]; ok {
-			// 事件消息，异步处理
-			go func(msg []byte) {
-				atomic.AddInt64(&c.metrics.activeGoroutines, 1)
-				defer atomic.AddInt64(&c.metrics.activeGoroutines, -1)
-				handleReceivedMessage(msg)
-			}(message)
+			// 事件消息，按接收顺序放入事件缓冲
+			select {
+			case events <- message:
+			default:
+				atomic.AddInt64(&c.metrics.eventsDropped, 1)
+				c.logger.Warn().
+					Int("缓冲大小", eventBufferSize).
+					Msg("事件缓冲已满，丢弃事件")
+			}
 		} else {
 			c.logger.Warn().
 				Str("message", string(message)).
@@ -230,6 +292,16 @@ func (c *Client) receiveLoop(handleReceivedMessage func(message []byte)) {
 	}
 }
 
+// 按顺序分发事件，直到 events 关闭
+func (c *Client) eventLoop(events <-chan []byte, handleReceivedMessage func(message []byte)) {
+	atomic.AddInt64(&c.metrics.activeGoroutines, 1)
+	defer atomic.AddInt64(&c.metrics.activeGoroutines, -1)
+
+	for message := range events {
+		handleReceivedMessage(message)
+	}
+}
+
 type Response struct {
 	Echo string `json:"echo"`
 }
@@ -374,47 +446,20 @@ func (c *Client) SendMessage(msg any) error {
 	}
 }
 
-// 【新增】发送API请求并等待响应的方法
-func (c *Client) SendAPIRequest(request any, echo string, timeout time.Duration) (*models.Response[any], error) {
-	if atomic.LoadInt64(&c.connClosed) == 1 {
-		return nil, fmt.Errorf("连接已关闭")
-	}
-
-	// 创建响应通道
-	respCh := make(chan *models.Response[any], 1)
-	c.pending.Store(echo, respCh)
-
-	// 确保清理pending
-	defer func() {
-		c.pending.Delete(echo)
-		close(respCh)
-	}()
-
-	// 发送请求
-	if err := c.SendMessage(request); err != nil {
-		return nil, fmt.Errorf("发送API请求失败: %w", err)
-	}
-
-	// 等待响应
-	select {
-	case resp := <-respCh:
-		return resp, nil
-	case <-time.After(timeout):
-		return nil, fmt.Errorf("API请求超时")
-	case <-c.connCtx.Done():
-		return nil, fmt.Errorf("连接已断开")
-	}
-}
-
-// 【新增】获取监控指标的方法
+// 【新增】获取监控指标的方法（含发送队列指标）
 func (c *Client) GetMetrics() map[string]int64 {
-	return map[string]int64{
+	metrics := map[string]int64{
 		"active_goroutines": atomic.LoadInt64(&c.metrics.activeGoroutines),
 		"messages_sent":     atomic.LoadInt64(&c.metrics.messagesSent),
 		"messages_received": atomic.LoadInt64(&c.metrics.messagesReceived),
+		"events_dropped":    atomic.LoadInt64(&c.metrics.eventsDropped),
 		"reconnect_count":   atomic.LoadInt64(&c.metrics.reconnectCount),
 		"connection_closed": atomic.LoadInt64(&c.connClosed),
 	}
+	for k, v := range c.sched.Metrics() {
+		metrics[k] = v
+	}
+	return metrics
 }
 
 // 【新增】检查连接状态的方法
@@ -431,8 +476,9 @@ func (c *Client) Close() error {
 		c.connCancel()
 	}
 
-	// 关闭连接
+	// 关闭连接与发送队列
 	c.closeConnection()
+	c.sched.close()
 
 	// 等待所有goroutine退出（最多等待5秒）
 	timeout := time.After(5 * time.Second)
@@ -466,6 +512,7 @@ func (c *Client) PrintStatus() {
 		Int64("messages_sent", metrics["messages_sent"]).
 		Int64("messages_received", metrics["messages_received"]).
 		Int64("reconnect_count", metrics["reconnect_count"]).
+		Int64("send_queue_depth", metrics["send_queue_depth"]).
 		Bool("is_connected", c.IsConnected()).
 		Msg("WebSocket客户端状态")
 }
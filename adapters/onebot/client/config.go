@@ -0,0 +1,206 @@
+package client
+
+import (
+	"time"
+	"yora/pkg/conf"
+)
+
+// 连接模式
+const (
+	ModeReverseWS = "ws-reverse" // 反向 WebSocket：由 OneBot 实现连接 /onebot/v11/ws
+	ModeForwardWS = "ws"         // 正向 WebSocket：主动连接 OneBot 实现
+	ModeHTTP      = "http"       // HTTP：API 通过 HTTP POST 调用，事件通过 /onebot/v11/http 上报
+)
+
+// 发送消息的格式
+const (
+	FormatArray  = "array"  // 消息段数组
+	FormatString = "string" // CQ 码字符串
+)
+
+// Config 连接配置
+type Config struct {
+	Mode        string        // 连接模式
+	URL         string        // 正向 WebSocket 地址或 HTTP API 地址，如 ws://127.0.0.1:3001、http://127.0.0.1:3000
+	AccessToken string        // 访问令牌：反向连接时校验，正向连接与 HTTP 调用时携带
+	Secret      string        // HTTP 上报签名密钥（X-Signature）
+	Timeout     time.Duration // API 调用默认超时（ctx 未设置截止时间时使用）
+
+	ActionTimeouts map[string]time.Duration // 按 API 名称设置的默认超时，覆盖 Timeout
+	MessageFormat  string                   // 发送消息的格式，默认 array
+
+	ReconnectInterval    time.Duration // 正向 WebSocket 初始重连间隔
+	MaxReconnectInterval time.Duration // 正向 WebSocket 最大重连间隔
+
+	Send SendConfig // 发送消息的限速与重试
+}
+
+// SendConfig 发送队列配置：频率为每秒条数，零值使用默认值，负数表示不限制/不启用
+type SendConfig struct {
+	GlobalRate  float64 // 全局发送频率
+	GlobalBurst int     // 全局突发条数
+	GroupRate   float64 // 每个群的发送频率
+	GroupBurst  int     // 每个群的突发条数
+	UserRate    float64 // 每个私聊用户的发送频率
+	UserBurst   int     // 每个私聊用户的突发条数
+
+	Jitter           time.Duration // 每条消息发送前的随机延迟上限
+	MaxRetries       int           // 暂时性失败的最大重试次数
+	RetryInterval    time.Duration // 初始重试间隔，之后按指数增长
+	MaxRetryInterval time.Duration // 最大重试间隔
+	RetryRetcodes    []int         // 视为暂时性失败、需要重试的 retcode
+}
+
+// DefaultConfig 默认配置（反向 WebSocket）
+func DefaultConfig() Config {
+	return Config{
+		Mode:                 ModeReverseWS,
+		Timeout:              10 * time.Second,
+		ActionTimeouts:       DefaultActionTimeouts(),
+		MessageFormat:        FormatArray,
+		ReconnectInterval:    time.Second,
+		MaxReconnectInterval: time.Minute,
+		Send:                 DefaultSendConfig(),
+	}
+}
+
+// DefaultActionTimeouts 默认的 API 超时：文件上传、下载等耗时操作使用更长的超时
+func DefaultActionTimeouts() map[string]time.Duration {
+	return map[string]time.Duration{
+		"upload_group_file":   5 * time.Minute,
+		"upload_private_file": 5 * time.Minute,
+		"upload_image":        time.Minute,
+		"download_file":       5 * time.Minute,
+		"get_file":            time.Minute,
+		"get_record":          time.Minute,
+		"get_image":           time.Minute,
+		"get_ai_record":       time.Minute,
+		"ocr_image":           30 * time.Second,
+	}
+}
+
+// ConfigFrom 从机器人配置文件中的 onebot 配置创建连接配置，未填写的字段使用默认值
+func ConfigFrom(c conf.OneBotConfig) Config {
+	cfg := DefaultConfig()
+	if c.Mode != "" {
+		cfg.Mode = c.Mode
+	}
+	if c.MessageFormat != "" {
+		cfg.MessageFormat = c.MessageFormat
+	}
+	cfg.URL = c.URL
+	cfg.AccessToken = c.AccessToken
+	cfg.Secret = c.Secret
+	if c.Timeout > 0 {
+		cfg.Timeout = c.Timeout
+	}
+	for action, d := range c.ActionTimeouts {
+		cfg.ActionTimeouts[action] = d
+	}
+	cfg.Send = SendConfig{
+		GlobalRate:       c.Send.GlobalRate,
+		GlobalBurst:      c.Send.GlobalBurst,
+		GroupRate:        c.Send.GroupRate,
+		GroupBurst:       c.Send.GroupBurst,
+		UserRate:         c.Send.UserRate,
+		UserBurst:        c.Send.UserBurst,
+		Jitter:           c.Send.Jitter,
+		MaxRetries:       c.Send.MaxRetries,
+		RetryInterval:    c.Send.RetryInterval,
+		MaxRetryInterval: c.Send.MaxRetryInterval,
+		RetryRetcodes:    c.Send.RetryRetcodes,
+	}.withDefaults()
+	return cfg
+}
+
+// TimeoutFor 获取 API 的默认超时
+func (cfg Config) TimeoutFor(action string) time.Duration {
+	if d, ok := cfg.ActionTimeouts[action]; ok && d > 0 {
+		return d
+	}
+	return cfg.Timeout
+}
+
+// DefaultSendConfig 默认发送配置：全局每秒 2 条，单个会话每 2 秒 1 条
+func DefaultSendConfig() SendConfig {
+	return SendConfig{
+		GlobalRate:       2,
+		GlobalBurst:      5,
+		GroupRate:        0.5,
+		GroupBurst:       3,
+		UserRate:         0.5,
+		UserBurst:        3,
+		Jitter:           300 * time.Millisecond,
+		MaxRetries:       3,
+		RetryInterval:    time.Second,
+		MaxRetryInterval: 30 * time.Second,
+		RetryRetcodes:    []int{102, 103, 201, 1200, RetcodeRateLimited},
+	}
+}
+
+// 补全未设置的字段
+func (cfg Config) withDefaults() Config {
+	def := DefaultConfig()
+	if cfg.Mode == "" {
+		cfg.Mode = def.Mode
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = def.Timeout
+	}
+	if cfg.MessageFormat == "" {
+		cfg.MessageFormat = def.MessageFormat
+	}
+	// 在默认超时基础上覆盖配置的超时
+	timeouts := def.ActionTimeouts
+	for action, d := range cfg.ActionTimeouts {
+		timeouts[action] = d
+	}
+	cfg.ActionTimeouts = timeouts
+	if cfg.ReconnectInterval <= 0 {
+		cfg.ReconnectInterval = def.ReconnectInterval
+	}
+	if cfg.MaxReconnectInterval < cfg.ReconnectInterval {
+		cfg.MaxReconnectInterval = max(def.MaxReconnectInterval, cfg.ReconnectInterval)
+	}
+	cfg.Send = cfg.Send.withDefaults()
+	return cfg
+}
+
+// 补全未设置的字段
+func (cfg SendConfig) withDefaults() SendConfig {
+	def := DefaultSendConfig()
+	if cfg.GlobalRate == 0 {
+		cfg.GlobalRate = def.GlobalRate
+	}
+	if cfg.GlobalBurst <= 0 {
+		cfg.GlobalBurst = def.GlobalBurst
+	}
+	if cfg.GroupRate == 0 {
+		cfg.GroupRate = def.GroupRate
+	}
+	if cfg.GroupBurst <= 0 {
+		cfg.GroupBurst = def.GroupBurst
+	}
+	if cfg.UserRate == 0 {
+		cfg.UserRate = def.UserRate
+	}
+	if cfg.UserBurst <= 0 {
+		cfg.UserBurst = def.UserBurst
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = def.Jitter
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = def.MaxRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = def.RetryInterval
+	}
+	if cfg.MaxRetryInterval < cfg.RetryInterval {
+		cfg.MaxRetryInterval = max(def.MaxRetryInterval, cfg.RetryInterval)
+	}
+	if cfg.RetryRetcodes == nil {
+		cfg.RetryRetcodes = def.RetryRetcodes
+	}
+	return cfg
+}
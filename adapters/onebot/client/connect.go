@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Connect 以正向 WebSocket 模式主动连接 OneBot 实现，断线后按指数退避自动重连，直到 ctx 结束
+func (c *Client) Connect(ctx context.Context, handleReceivedMessage func(message []byte)) error {
+	cfg := c.Config()
+	if cfg.Mode != ModeForwardWS {
+		return fmt.Errorf("当前连接模式为 %s，不支持主动连接", cfg.Mode)
+	}
+	if cfg.URL == "" {
+		return fmt.Errorf("正向 WebSocket 地址不能为空")
+	}
+
+	go c.connectLoop(ctx, cfg, handleReceivedMessage)
+	return nil
+}
+
+// 连接循环：连接成功后阻塞直到断开，然后等待退避时间重连
+func (c *Client) connectLoop(ctx context.Context, cfg Config, handleReceivedMessage func(message []byte)) {
+	stop := context.AfterFunc(ctx, c.closeConnection)
+	defer stop()
+
+	backoff := cfg.ReconnectInterval
+	for {
+		conn, err := c.dial(ctx, cfg)
+		if err == nil {
+			c.replaceConnection(conn)
+			c.logger.Info().Str("地址", cfg.URL).Msg("正向 WebSocket 连接已建立")
+
+			backoff = cfg.ReconnectInterval
+			c.connectionManager(handleReceivedMessage)
+		} else {
+			c.logger.Warn().Err(err).Str("地址", cfg.URL).Dur("重连间隔", backoff).Msg("正向 WebSocket 连接失败")
+		}
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info().Msg("正向 WebSocket 连接循环退出")
+			return
+		case <-time.After(backoff):
+		}
+
+		if err != nil {
+			backoff = min(backoff*2, cfg.MaxReconnectInterval)
+		}
+	}
+}
+
+func (c *Client) dial(ctx context.Context, cfg Config) (*websocket.Conn, error) {
+	header := http.Header{}
+	if cfg.AccessToken != "" {
+		header.Set("Authorization", "Bearer "+cfg.AccessToken)
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(dialCtx, cfg.URL, header)
+	return conn, err
+}
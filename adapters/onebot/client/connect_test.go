@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectForwardWebSocket(t *testing.T) {
+	event := `{"post_type":"meta_event","meta_event_type":"lifecycle"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(event))
+		conn.ReadMessage() // 保持连接直到客户端关闭
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := newClient(ctx).SetConfig(Config{
+		Mode:        ModeForwardWS,
+		URL:         "ws" + strings.TrimPrefix(server.URL, "http"),
+		AccessToken: "token",
+	})
+
+	received := make(chan string, 1)
+	require.NoError(t, c.Connect(ctx, func(message []byte) { received <- string(message) }))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, event, msg)
+	case <-time.After(3 * time.Second):
+		t.Fatal("未收到事件")
+	}
+}
+
+func TestConnectRequiresForwardMode(t *testing.T) {
+	c := newClient(context.Background())
+	assert.Error(t, c.Connect(context.Background(), func([]byte) {}))
+}
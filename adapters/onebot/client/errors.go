@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"yora/adapters/onebot/models"
+)
+
+// OneBot 约定的错误码（HTTP 状态码 + 1000）
+const (
+	RetcodeBadRequest   = 1400 // 请求不符合要求
+	RetcodeUnauthorized = 1401 // 未提供鉴权
+	RetcodeForbidden    = 1403 // 鉴权失败或无权限
+	RetcodeNotFound     = 1404 // API 不存在
+	RetcodeRateLimited  = 1429 // 请求过于频繁
+)
+
+// APIError API 调用失败（status 不为 ok）
+type APIError struct {
+	Action  string // API 名称
+	RetCode int    // 错误码
+	Status  string // 响应状态
+	Message string // 失败原因
+	Wording string // 面向用户的失败描述
+	Echo    string // 请求标识
+}
+
+func (e *APIError) Error() string {
+	msg := e.Wording
+	if msg == "" {
+		msg = e.Message
+	}
+	if msg == "" {
+		return fmt.Sprintf("API %s 调用失败: %s (retcode %d)", e.Action, e.Status, e.RetCode)
+	}
+	return fmt.Sprintf("API %s 调用失败: %s (retcode %d)", e.Action, msg, e.RetCode)
+}
+
+// 失败描述中是否包含任一关键字
+func (e *APIError) mentions(keywords ...string) bool {
+	text := strings.ToLower(e.Message + " " + e.Wording)
+	return slices.ContainsFunc(keywords, func(k string) bool {
+		return strings.Contains(text, k)
+	})
+}
+
+// 从响应创建错误，响应成功时返回 nil
+func newAPIError(action string, resp *models.Response[any]) error {
+	if resp == nil || resp.Status == "ok" || resp.Status == "async" {
+		return nil
+	}
+	return &APIError{
+		Action:  action,
+		RetCode: resp.Retcode,
+		Status:  resp.Status,
+		Message: resp.Message,
+		Wording: resp.Wording,
+		Echo:    resp.Echo,
+	}
+}
+
+// IsPermissionDenied 是否因权限不足失败（如机器人不是群管理员）
+func IsPermissionDenied(err error) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.RetCode == RetcodeUnauthorized || e.RetCode == RetcodeForbidden ||
+		e.mentions("权限", "管理员", "permission", "not admin", "forbidden")
+}
+
+// IsNotFound 是否因目标不存在失败（如 API、群、成员、消息不存在）
+func IsNotFound(err error) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.RetCode == RetcodeNotFound ||
+		e.mentions("不存在", "找不到", "未找到", "not found", "not exist")
+}
+
+// IsRateLimited 是否因请求过于频繁失败
+func IsRateLimited(err error) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.RetCode == RetcodeRateLimited ||
+		e.mentions("频繁", "频率", "rate limit", "too many", "too frequent")
+}
+
+// 请求未发出（连接未建立、发送前断开或连接失败），重试不会导致重复执行
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string { return e.err.Error() }
+func (e *notSentError) Unwrap() error { return e.err }
+
+func notSent(err error) error {
+	return &notSentError{err: err}
+}
+
+// 错误发生时请求是否确定未发出
+func isNotSent(err error) bool {
+	var e *notSentError
+	return errors.As(err, &e)
+}
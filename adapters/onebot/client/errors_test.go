@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yora/adapters/onebot/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/set_group_kick":
+			json.NewEncoder(w).Encode(map[string]any{
+				"status":  "failed",
+				"retcode": 200,
+				"message": "permission denied",
+				"wording": "机器人不是群管理员",
+			})
+		case "/get_group_info":
+			json.NewEncoder(w).Encode(map[string]any{"status": "ok", "retcode": 0, "data": map[string]any{"group_id": 1}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newClient(context.Background()).SetConfig(Config{Mode: ModeHTTP, URL: server.URL})
+
+	_, err := Call[any, models.Response[any]](c, "set_group_kick", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "set_group_kick", apiErr.Action)
+	assert.Equal(t, 200, apiErr.RetCode)
+	assert.Equal(t, "failed", apiErr.Status)
+	assert.Contains(t, err.Error(), "机器人不是群管理员")
+	assert.True(t, IsPermissionDenied(err))
+	assert.False(t, IsNotFound(err))
+
+	_, err = Call[any, models.Response[any]](c, "unknown_action", nil)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, RetcodeNotFound, apiErr.RetCode)
+	assert.True(t, IsNotFound(err))
+
+	_, err = Call[any, models.Response[any]](c, "get_group_info", nil)
+	assert.NoError(t, err)
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	wrapped := fmt.Errorf("禁言失败: %w", &APIError{Action: "set_group_ban", RetCode: RetcodeRateLimited, Status: "failed"})
+	assert.True(t, IsRateLimited(wrapped))
+	assert.False(t, IsPermissionDenied(wrapped))
+
+	assert.True(t, IsRateLimited(&APIError{Status: "failed", Message: "发送过于频繁"}))
+	assert.True(t, IsNotFound(&APIError{Status: "failed", Wording: "群成员不存在"}))
+	assert.False(t, IsNotFound(fmt.Errorf("not found")), "非 APIError 不判定")
+
+	assert.Nil(t, newAPIError("x", &models.Response[any]{Status: "ok"}))
+	assert.Nil(t, newAPIError("x", &models.Response[any]{Status: "async"}))
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"yora/adapters/onebot/models"
+)
+
+// 通过 HTTP POST 调用 API
+func (c *Client) callHTTP(ctx context.Context, cfg Config, action string, params any) (*models.Response[any], error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("HTTP API 地址不能为空")
+	}
+	if params == nil {
+		params = map[string]any{}
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("序列化 API 参数失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(cfg.URL, "/")+"/"+action, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.AccessToken)
+	}
+
+	c.logger.Debug().Msgf("发送 HTTP API 请求: %s", action)
+
+	resp, err := http.DefaultClient.Do(req) // 超时由 ctx 控制
+	if err != nil {
+		err = fmt.Errorf("HTTP 请求失败: %w", err)
+		// 连接未建立时请求未发出
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			err = notSent(err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 4xx 按 OneBot 约定转换为失败响应（retcode = 状态码 + 1000），由调用方返回 APIError
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return &models.Response[any]{
+			Status:  "failed",
+			Retcode: resp.StatusCode + 1000,
+			Message: http.StatusText(resp.StatusCode),
+		}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP 请求失败: 状态码 %d", resp.StatusCode)
+	}
+
+	var result models.Response[any]
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析 HTTP 响应失败: %w", err)
+	}
+	return &result, nil
+}
+
+// HandleHTTP 处理 OneBot 的 HTTP 事件上报，配置了 Secret 时校验 X-Signature
+func (c *Client) HandleHTTP(w http.ResponseWriter, r *http.Request, handleReceivedMessage func(message []byte)) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("读取 HTTP 上报失败")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if secret := c.Config().Secret; secret != "" && !VerifySignature(secret, body, r.Header.Get("X-Signature")) {
+		c.logger.Warn().Str("客户端IP", r.RemoteAddr).Msg("HTTP 上报签名校验失败")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	atomic.AddInt64(&c.metrics.messagesReceived, 1)
+
+	// 不使用快速操作，直接返回 204
+	w.WriteHeader(http.StatusNoContent)
+	handleReceivedMessage(body)
+}
+
+// VerifySignature 校验 HTTP 上报签名，签名格式为 sha1=<hex(HMAC-SHA1(secret, body))>
+func VerifySignature(secret string, body []byte, signature string) bool {
+	sig, ok := strings.CutPrefix(signature, "sha1=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
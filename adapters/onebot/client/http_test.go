@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"post_type":"message"}`)
+
+	assert.True(t, VerifySignature("secret", body, sign("secret", body)))
+	assert.False(t, VerifySignature("other", body, sign("secret", body)))
+	assert.False(t, VerifySignature("secret", body, strings.TrimPrefix(sign("secret", body), "sha1=")))
+}
+
+func TestHandleHTTPRejectsBadSignature(t *testing.T) {
+	c := newClient(context.Background()).SetConfig(Config{Mode: ModeHTTP, Secret: "secret"})
+	body := `{"post_type":"message"}`
+
+	var received []string
+	handle := func(message []byte) { received = append(received, string(message)) }
+
+	req := httptest.NewRequest(http.MethodPost, "/onebot/v11/http", strings.NewReader(body))
+	req.Header.Set("X-Signature", "sha1=00")
+	w := httptest.NewRecorder()
+	c.HandleHTTP(w, req, handle)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/onebot/v11/http", strings.NewReader(body))
+	req.Header.Set("X-Signature", sign("secret", []byte(body)))
+	w = httptest.NewRecorder()
+	c.HandleHTTP(w, req, handle)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{body}, received)
+}
+
+func TestCallAPIOverHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/get_login_info", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":  "ok",
+			"retcode": 0,
+			"data":    map[string]any{"user_id": 10001},
+		})
+	}))
+	defer server.Close()
+
+	c := newClient(context.Background()).SetConfig(Config{Mode: ModeHTTP, URL: server.URL, AccessToken: "token"})
+	resp, err := c.CallAPI("get_login_info", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, float64(10001), resp.Data.(map[string]any)["user_id"])
+}
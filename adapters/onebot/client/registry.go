@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"yora/pkg/log"
+
+	"github.com/rs/zerolog"
+)
+
+// Registry 按账号（self_id）管理连接，每个账号一个 Client
+//
+// 第一个接入的账号复用 GetClient 返回的默认连接，保证单账号部署及 api 包的行为不变。
+// 正向 WebSocket / HTTP 模式下无法预知账号，默认连接以空 self_id 注册，作为所有账号的回退。
+type Registry struct {
+	ctx         context.Context
+	config      Config
+	def         *Client
+	defaultUsed bool
+	clients     map[string]*Client
+	logger      zerolog.Logger
+	mu          sync.RWMutex
+}
+
+var (
+	registryMu      sync.RWMutex
+	defaultRegistry *Registry
+)
+
+// NewRegistry 创建连接管理器，第一个创建的管理器作为默认管理器，供 api.GetAPI 按账号选择连接
+func NewRegistry(ctx context.Context) *Registry {
+	r := &Registry{
+		ctx:     ctx,
+		config:  DefaultConfig(),
+		def:     GetClient(ctx),
+		clients: make(map[string]*Client),
+		logger:  log.NewAPI("connections"),
+	}
+
+	registryMu.Lock()
+	if defaultRegistry == nil {
+		defaultRegistry = r
+	}
+	registryMu.Unlock()
+	return r
+}
+
+// DefaultRegistry 获取默认连接管理器，尚未创建时返回 nil
+func DefaultRegistry() *Registry {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return defaultRegistry
+}
+
+// 设置连接配置，对已有连接同样生效
+func (r *Registry) SetConfig(cfg Config) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config = cfg.withDefaults()
+	r.def.SetConfig(r.config)
+	for _, c := range r.clients {
+		c.SetConfig(r.config)
+	}
+	return r
+}
+
+// 获取连接配置
+func (r *Registry) Config() Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.config
+}
+
+// 获取账号对应的连接
+func (r *Registry) Client(selfID string) (*Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clients[selfID]
+	return c, ok
+}
+
+// 已接入的账号（按ID排序）
+func (r *Registry) SelfIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.clients))
+	for id := range r.clients {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Pick 选择发送所用的连接：selfID 为空时使用默认连接；
+// 账号未接入时，若默认连接账号未知（正向 WebSocket / HTTP 模式）则回退到默认连接
+func (r *Registry) Pick(selfID string) (*Client, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if selfID == "" {
+		return r.def, nil
+	}
+	if c, ok := r.clients[selfID]; ok {
+		return c, nil
+	}
+	if c, ok := r.clients[""]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("账号 %s 未连接", selfID)
+}
+
+// 获取或创建账号对应的连接
+func (r *Registry) attach(selfID string) *Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.clients[selfID]; ok {
+		return c
+	}
+
+	var c *Client
+	if !r.defaultUsed {
+		c = r.def
+		r.defaultUsed = true
+	} else {
+		c = newClient(r.ctx).SetConfig(r.config)
+	}
+
+	c.mu.Lock()
+	c.selfID = selfID
+	c.mu.Unlock()
+
+	r.clients[selfID] = c
+	r.logger.Info().Str("账号", selfID).Int("连接数", len(r.clients)).Msg("新账号接入")
+	return c
+}
+
+// HandleWebSocket 处理反向 WebSocket 连接，按 X-Self-ID 分配连接，同一账号重连时替换旧连接
+func (r *Registry) HandleWebSocket(w http.ResponseWriter, req *http.Request, handleReceivedMessage func(message []byte)) {
+	if token := r.Config().AccessToken; token != "" && !checkAccessToken(req, token) {
+		r.logger.Warn().
+			Str("客户端IP", req.RemoteAddr).
+			Str("账号", req.Header.Get("X-Self-ID")).
+			Msg("WebSocket 连接 access_token 校验失败")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.attach(req.Header.Get("X-Self-ID")).HandleWebSocket(w, req, handleReceivedMessage)
+}
+
+// HandleHTTP 处理 HTTP 事件上报，签名校验通过后才分配连接
+func (r *Registry) HandleHTTP(w http.ResponseWriter, req *http.Request, handleReceivedMessage func(message []byte)) {
+	if secret := r.Config().Secret; secret != "" {
+		body, err := io.ReadAll(req.Body)
+		if err != nil || !VerifySignature(secret, body, req.Header.Get("X-Signature")) {
+			r.logger.Warn().Str("客户端IP", req.RemoteAddr).Msg("HTTP 上报签名校验失败")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	r.attach(req.Header.Get("X-Self-ID")).HandleHTTP(w, req, handleReceivedMessage)
+}
+
+// Connect 正向 WebSocket 模式下主动连接
+func (r *Registry) Connect(ctx context.Context, handleReceivedMessage func(message []byte)) error {
+	return r.attach("").Connect(ctx, handleReceivedMessage)
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryKeepsOneConnectionPerAccount(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRegistry(ctx).SetConfig(DefaultConfig())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.HandleWebSocket(w, req, func([]byte) {})
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	for _, id := range []string{"10001", "10002"} {
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"X-Self-ID": {id}})
+		require.NoError(t, err)
+		defer conn.Close()
+	}
+
+	assert.Eventually(t, func() bool {
+		a, okA := r.Client("10001")
+		b, okB := r.Client("10002")
+		return okA && okB && a.IsConnected() && b.IsConnected()
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"10001", "10002"}, r.SelfIDs())
+
+	c, err := r.Pick("10002")
+	require.NoError(t, err)
+	assert.Equal(t, "10002", c.SelfID())
+
+	_, err = r.Pick("10003")
+	assert.Error(t, err)
+}
+
+func TestRegistryRejectsUnauthorizedAccount(t *testing.T) {
+	r := NewRegistry(context.Background()).SetConfig(Config{AccessToken: "token"})
+
+	req := httptest.NewRequest(http.MethodGet, "/onebot/v11/ws", nil)
+	req.Header.Set("X-Self-ID", "10001")
+	w := httptest.NewRecorder()
+	r.HandleWebSocket(w, req, func([]byte) {})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, r.SelfIDs())
+}
@@ -0,0 +1,471 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+	"yora/adapters/onebot/models"
+	"yora/pkg/log"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrQueueClosed 发送队列已关闭（连接已关闭），排队中的消息以此失败
+var ErrQueueClosed = errors.New("发送队列已关闭")
+
+// Priority 发送优先级，高优先级的消息先发送
+type Priority int
+
+const (
+	PriorityLow    Priority = iota // 低：批量通知等
+	PriorityNormal                 // 普通：默认
+	PriorityHigh                   // 高：管理通知等
+)
+
+type priorityKey struct{}
+
+// WithPriority 指定上下文中发送消息的优先级
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext 获取上下文中的发送优先级，未指定时为普通
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityNormal
+	}
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return max(PriorityLow, min(p, PriorityHigh))
+	}
+	return PriorityNormal
+}
+
+// 发送消息的 API
+var sendActions = map[string]bool{
+	"send_msg":                 true,
+	"send_group_msg":           true,
+	"send_private_msg":         true,
+	"send_forward_msg":         true,
+	"send_group_forward_msg":   true,
+	"send_private_forward_msg": true,
+}
+
+// 发送目标：群聊时 group 不为空，否则为私聊用户
+type sendTarget struct {
+	group string
+	user  string
+}
+
+// 从请求参数中解析发送目标
+func parseSendTarget(params any) sendTarget {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return sendTarget{}
+	}
+	var ids struct {
+		GroupID any `json:"group_id"`
+		UserID  any `json:"user_id"`
+	}
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return sendTarget{}
+	}
+
+	id := func(v any) string {
+		if v == nil {
+			return ""
+		}
+		s := fmt.Sprint(v)
+		if s == "0" {
+			return ""
+		}
+		return s
+	}
+	if group := id(ids.GroupID); group != "" {
+		return sendTarget{group: group}
+	}
+	return sendTarget{user: id(ids.UserID)}
+}
+
+type sendResult struct {
+	resp *models.Response[any]
+	err  error
+}
+
+// 待发送任务
+type sendJob struct {
+	priority  Priority
+	target    sendTarget
+	call      func() (*models.Response[any], error)
+	attempt   int
+	notBefore time.Time // 重试退避期间不发送
+	canceled  atomic.Bool
+	done      chan sendResult
+}
+
+// 令牌桶，rate 为每秒补充的令牌数
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil // 不限制
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// 距离有可用令牌还需等待的时间
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.refill(now)
+	b.tokens--
+}
+
+// 桶已满且长时间未使用，可以回收
+func (b *tokenBucket) idle(now time.Time, ttl time.Duration) bool {
+	if b == nil {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= b.burst && now.Sub(b.last) >= ttl
+}
+
+// 发送调度器：按优先级排队，按全局、群、用户令牌桶限速，暂时性失败时指数退避重试
+//
+// 不同会话的消息并发发送，同一会话被限速或正在发送时不阻塞其他会话的消息；
+// 同一会话同时只有一条消息在发送，保证会话内的消息顺序。
+type scheduler struct {
+	cfg      SendConfig
+	lanes    [PriorityHigh + 1][]*sendJob
+	global   *tokenBucket
+	groups   map[string]*tokenBucket
+	users    map[string]*tokenBucket
+	inflight map[sendTarget]bool // 正在发送的会话
+	wake     chan struct{}
+	start    sync.Once
+	closed   chan struct{} // 关闭后调度循环退出
+	stopped  bool          // 已关闭，不再接受任务
+	logger   zerolog.Logger
+	mu       sync.Mutex
+
+	metrics struct {
+		sent    int64 // 已发送
+		retried int64 // 重试次数
+		failed  int64 // 最终失败
+		dropped int64 // 等待期间被取消
+	}
+}
+
+func newScheduler(cfg SendConfig) *scheduler {
+	s := &scheduler{
+		inflight: make(map[sendTarget]bool),
+		wake:     make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+		logger:   log.NewAPI("send_queue"),
+	}
+	s.setConfig(cfg)
+	return s
+}
+
+// 更新配置，重置所有令牌桶
+func (s *scheduler) setConfig(cfg SendConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.cfg = cfg.withDefaults()
+	s.global = newTokenBucket(s.cfg.GlobalRate, s.cfg.GlobalBurst, now)
+	s.groups = make(map[string]*tokenBucket)
+	s.users = make(map[string]*tokenBucket)
+}
+
+// 提交发送任务并等待结果，ctx 取消时放弃排队
+func (s *scheduler) submit(ctx context.Context, target sendTarget, call func() (*models.Response[any], error)) (*models.Response[any], error) {
+	s.start.Do(func() { go s.run() })
+
+	job := &sendJob{
+		priority: PriorityFromContext(ctx),
+		target:   target,
+		call:     call,
+		done:     make(chan sendResult, 1),
+	}
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil, ErrQueueClosed
+	}
+	s.lanes[job.priority] = append(s.lanes[job.priority], job)
+	s.mu.Unlock()
+	s.notify()
+
+	select {
+	case r := <-job.done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		job.canceled.Store(true)
+		atomic.AddInt64(&s.metrics.dropped, 1)
+		return nil, fmt.Errorf("消息排队期间被取消: %w", ctx.Err())
+	}
+}
+
+// 关闭队列：调度循环退出，排队中的任务以 ErrQueueClosed 失败，正在发送的任务照常完成
+func (s *scheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	close(s.closed)
+
+	for p := range s.lanes {
+		for _, job := range s.lanes[p] {
+			job.done <- sendResult{err: ErrQueueClosed}
+		}
+		s.lanes[p] = nil
+	}
+}
+
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// 调度循环：取出可发送的任务交给独立的 goroutine 发送，没有可发送任务时等待到最近的可发送时间
+func (s *scheduler) run() {
+	prune := time.NewTicker(time.Minute)
+	defer prune.Stop()
+
+	for {
+		job, wait := s.next(time.Now())
+		if job != nil {
+			go s.execute(job)
+			continue
+		}
+
+		var (
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+
+		select {
+		case <-s.wake:
+		case <-fire:
+		case <-prune.C:
+			s.prune(time.Now())
+		case <-s.closed:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-s.closed:
+			return
+		default:
+		}
+	}
+}
+
+// 按优先级查找第一个可以立即发送的任务并扣除令牌；没有时返回最短等待时间（0 表示队列为空）
+func (s *scheduler) next(now time.Time) (*sendJob, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var wait time.Duration
+	for p := PriorityHigh; p >= PriorityLow; p-- {
+		lane := s.lanes[p]
+		for i := 0; i < len(lane); i++ {
+			job := lane[i]
+			if job.canceled.Load() {
+				lane = slices.Delete(lane, i, i+1)
+				i--
+				continue
+			}
+			if s.inflight[job.target] {
+				continue // 发送完成后会重新唤醒
+			}
+
+			d := job.notBefore.Sub(now)
+			chat := s.bucket(job.target, now)
+			d = max(d, s.global.delay(now), chat.delay(now))
+			if d > 0 {
+				if wait == 0 || d < wait {
+					wait = d
+				}
+				continue
+			}
+
+			s.global.take(now)
+			chat.take(now)
+			s.inflight[job.target] = true
+			s.lanes[p] = slices.Delete(lane, i, i+1)
+			return job, 0
+		}
+		s.lanes[p] = lane
+	}
+	return nil, wait
+}
+
+// 获取会话的令牌桶
+func (s *scheduler) bucket(t sendTarget, now time.Time) *tokenBucket {
+	buckets, id, rate, burst := s.users, t.user, s.cfg.UserRate, s.cfg.UserBurst
+	if t.group != "" {
+		buckets, id, rate, burst = s.groups, t.group, s.cfg.GroupRate, s.cfg.GroupBurst
+	}
+	if id == "" {
+		return nil
+	}
+
+	b, ok := buckets[id]
+	if !ok {
+		b = newTokenBucket(rate, burst, now)
+		buckets[id] = b
+	}
+	return b
+}
+
+// 发送任务，暂时性失败时重新排队
+func (s *scheduler) execute(job *sendJob) {
+	defer s.notify()
+
+	s.mu.Lock()
+	cfg := s.cfg
+	s.mu.Unlock()
+
+	if cfg.Jitter > 0 {
+		time.Sleep(rand.N(cfg.Jitter))
+	}
+	if job.canceled.Load() {
+		s.finish(job, nil)
+		return
+	}
+
+	resp, err := job.call()
+	if s.transient(resp, err) && job.attempt < cfg.MaxRetries {
+		job.attempt++
+		backoff := min(cfg.RetryInterval<<(job.attempt-1), cfg.MaxRetryInterval)
+		job.notBefore = time.Now().Add(backoff)
+		atomic.AddInt64(&s.metrics.retried, 1)
+
+		s.logger.Warn().
+			Err(err).
+			Int("重试次数", job.attempt).
+			Dur("退避", backoff).
+			Str("群ID", job.target.group).
+			Str("用户ID", job.target.user).
+			Msg("消息发送失败，稍后重试")
+
+		// 放回队首，保证会话内的顺序；队列已关闭时直接失败
+		s.finish(job, func() {
+			if s.stopped {
+				job.done <- sendResult{err: ErrQueueClosed}
+				return
+			}
+			s.lanes[job.priority] = append([]*sendJob{job}, s.lanes[job.priority]...)
+		})
+		return
+	}
+
+	if err != nil || (resp != nil && resp.Status == "failed") {
+		atomic.AddInt64(&s.metrics.failed, 1)
+	} else {
+		atomic.AddInt64(&s.metrics.sent, 1)
+	}
+	s.finish(job, nil)
+	job.done <- sendResult{resp: resp, err: err}
+}
+
+// 结束会话的发送状态，requeue 在同一临界区内重新排队
+func (s *scheduler) finish(job *sendJob, requeue func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, job.target)
+	if requeue != nil {
+		requeue()
+	}
+}
+
+// 是否为可重试的暂时性失败：请求确定未发出（如连接断开），或配置中的 retcode
+//
+// 请求发出后的超时、断开无法确定对方是否已执行，不重试，避免重复发送。
+func (s *scheduler) transient(resp *models.Response[any], err error) bool {
+	if err != nil {
+		return isNotSent(err)
+	}
+	if resp == nil || resp.Status == "ok" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.cfg.RetryRetcodes, resp.Retcode)
+}
+
+// 回收长时间未使用的会话令牌桶
+func (s *scheduler) prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, buckets := range []map[string]*tokenBucket{s.groups, s.users} {
+		for id, b := range buckets {
+			if b.idle(now, 10*time.Minute) {
+				delete(buckets, id)
+			}
+		}
+	}
+}
+
+// 队列监控指标
+func (s *scheduler) Metrics() map[string]int64 {
+	s.mu.Lock()
+	high, normal, low := len(s.lanes[PriorityHigh]), len(s.lanes[PriorityNormal]), len(s.lanes[PriorityLow])
+	s.mu.Unlock()
+
+	return map[string]int64{
+		"send_queue_high":   int64(high),
+		"send_queue_normal": int64(normal),
+		"send_queue_low":    int64(low),
+		"send_queue_depth":  int64(high + normal + low),
+		"send_sent":         atomic.LoadInt64(&s.metrics.sent),
+		"send_retried":      atomic.LoadInt64(&s.metrics.retried),
+		"send_failed":       atomic.LoadInt64(&s.metrics.failed),
+		"send_dropped":      atomic.LoadInt64(&s.metrics.dropped),
+	}
+}
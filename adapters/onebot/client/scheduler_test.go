@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"yora/adapters/onebot/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 测试用配置：不限速、无随机延迟、快速重试
+func fastSendConfig() SendConfig {
+	return SendConfig{
+		GlobalRate:    -1,
+		GroupRate:     -1,
+		UserRate:      -1,
+		Jitter:        -1,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	}
+}
+
+func ok() (*models.Response[any], error) {
+	return &models.Response[any]{Status: "ok"}, nil
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(2, 2, now)
+	assert.Zero(t, b.delay(now))
+	b.take(now)
+	b.take(now)
+	assert.Equal(t, 500*time.Millisecond, b.delay(now))
+	assert.Zero(t, b.delay(now.Add(500*time.Millisecond)))
+
+	assert.Nil(t, newTokenBucket(-1, 1, now))
+	assert.Zero(t, (*tokenBucket)(nil).delay(now))
+}
+
+func TestParseSendTarget(t *testing.T) {
+	uid, gid := 1001, 0
+	assert.Equal(t, sendTarget{user: "1001"}, parseSendTarget(models.MessageRequest{UserID: &uid, GroupID: &gid}))
+	assert.Equal(t, sendTarget{group: "2002"}, parseSendTarget(map[string]any{"group_id": 2002}))
+}
+
+func TestSchedulerRetriesTransientRetcode(t *testing.T) {
+	s := newScheduler(fastSendConfig())
+
+	calls := 0
+	resp, err := s.submit(context.Background(), sendTarget{group: "1"}, func() (*models.Response[any], error) {
+		calls++
+		if calls < 3 {
+			return &models.Response[any]{Status: "failed", Retcode: 1200}, nil
+		}
+		return ok()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, int64(2), s.Metrics()["send_retried"])
+
+	// 非暂时性错误不重试
+	calls = 0
+	resp, err = s.submit(context.Background(), sendTarget{group: "1"}, func() (*models.Response[any], error) {
+		calls++
+		return &models.Response[any]{Status: "failed", Retcode: 100}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100, resp.Retcode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSchedulerRetriesOnlyUnsentRequests(t *testing.T) {
+	s := newScheduler(fastSendConfig())
+
+	// 请求未发出：重试
+	calls := 0
+	_, err := s.submit(context.Background(), sendTarget{group: "1"}, func() (*models.Response[any], error) {
+		calls++
+		if calls < 2 {
+			return nil, notSent(ErrDisconnected)
+		}
+		return ok()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// 请求已发出后超时或断开：不重试，避免重复发送
+	for _, sentErr := range []error{context.DeadlineExceeded, ErrDisconnected} {
+		calls = 0
+		_, err = s.submit(context.Background(), sendTarget{group: "1"}, func() (*models.Response[any], error) {
+			calls++
+			return nil, sentErr
+		})
+		assert.ErrorIs(t, err, sentErr)
+		assert.Equal(t, 1, calls)
+	}
+}
+
+func TestSchedulerSlowSendDoesNotStallOtherChats(t *testing.T) {
+	s := newScheduler(fastSendConfig())
+
+	release := make(chan struct{})
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		s.submit(context.Background(), sendTarget{group: "slow"}, func() (*models.Response[any], error) {
+			<-release
+			return ok()
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	_, err := s.submit(context.Background(), sendTarget{group: "fast"}, ok)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	close(release)
+	<-slowDone
+}
+
+func TestSchedulerLimitsPerChatWithoutBlockingOthers(t *testing.T) {
+	cfg := fastSendConfig()
+	cfg.GroupRate, cfg.GroupBurst = 1, 1 // 每个群每秒 1 条
+	s := newScheduler(cfg)
+
+	_, err := s.submit(context.Background(), sendTarget{group: "busy"}, ok)
+	require.NoError(t, err)
+
+	// busy 群的下一条需等待约 1 秒，期间其他群的消息立即发送
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	send := func(group string) {
+		defer wg.Done()
+		s.submit(context.Background(), sendTarget{group: group}, func() (*models.Response[any], error) {
+			mu.Lock()
+			order = append(order, group)
+			mu.Unlock()
+			return ok()
+		})
+	}
+
+	wg.Add(1)
+	go send("busy")
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	wg.Add(1)
+	send("idle")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	wg.Wait()
+
+	assert.Equal(t, []string{"idle", "busy"}, order)
+}
+
+func TestSchedulerCloseFailsPendingAndStopsLoop(t *testing.T) {
+	cfg := fastSendConfig()
+	cfg.GroupRate, cfg.GroupBurst = 0.001, 1 // 第二条消息长时间等待令牌
+	s := newScheduler(cfg)
+
+	stopped := make(chan struct{})
+	s.start.Do(func() {
+		go func() {
+			s.run()
+			close(stopped)
+		}()
+	})
+
+	_, err := s.submit(context.Background(), sendTarget{group: "1"}, ok)
+	require.NoError(t, err)
+
+	pending := make(chan error, 1)
+	go func() {
+		_, err := s.submit(context.Background(), sendTarget{group: "1"}, ok)
+		pending <- err
+	}()
+	require.Eventually(t, func() bool { return s.Metrics()["send_queue_depth"] == 1 }, time.Second, time.Millisecond)
+
+	s.close()
+	assert.ErrorIs(t, <-pending, ErrQueueClosed)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("关闭后调度循环未退出")
+	}
+
+	_, err = s.submit(context.Background(), sendTarget{group: "2"}, ok)
+	assert.ErrorIs(t, err, ErrQueueClosed)
+	s.close() // 重复关闭无效果
+}
+
+func TestSchedulerPriorityAndCancel(t *testing.T) {
+	s := newScheduler(fastSendConfig())
+	now := time.Now()
+
+	low := &sendJob{priority: PriorityLow, target: sendTarget{user: "1"}, done: make(chan sendResult, 1)}
+	high := &sendJob{priority: PriorityHigh, target: sendTarget{group: "1"}, done: make(chan sendResult, 1)}
+	canceled := &sendJob{priority: PriorityHigh, done: make(chan sendResult, 1)}
+	canceled.canceled.Store(true)
+	s.lanes[PriorityLow] = []*sendJob{low}
+	s.lanes[PriorityHigh] = []*sendJob{canceled, high}
+
+	job, _ := s.next(now)
+	assert.Same(t, high, job)
+	job, _ = s.next(now)
+	assert.Same(t, low, job)
+	job, wait := s.next(now)
+	assert.Nil(t, job)
+	assert.Zero(t, wait)
+
+	// 同一会话正在发送时，后续消息等待
+	next := &sendJob{priority: PriorityHigh, target: sendTarget{group: "1"}, done: make(chan sendResult, 1)}
+	s.lanes[PriorityHigh] = []*sendJob{next}
+	job, _ = s.next(now)
+	assert.Nil(t, job)
+	s.finish(high, nil)
+	job, _ = s.next(now)
+	assert.Same(t, next, job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.submit(WithPriority(ctx, PriorityHigh), sendTarget{}, ok)
+	assert.Error(t, err)
+	assert.Equal(t, PriorityHigh, PriorityFromContext(WithPriority(context.Background(), 5)))
+}
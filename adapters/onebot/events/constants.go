@@ -19,3 +19,57 @@ const (
 	SubTypeNormal  SubType = "normal"
 	SubTypeConnect SubType = "connect"
 )
+
+type NoticeType string
+
+// 通知类型，notify 的具体类型见 sub_type
+const (
+	NoticeTypeGroupUpload   NoticeType = "group_upload"         // 群文件上传
+	NoticeTypeGroupAdmin    NoticeType = "group_admin"          // 群管理员变动
+	NoticeTypeGroupDecrease NoticeType = "group_decrease"       // 群成员减少
+	NoticeTypeGroupIncrease NoticeType = "group_increase"       // 群成员增加
+	NoticeTypeGroupBan      NoticeType = "group_ban"            // 群禁言
+	NoticeTypeFriendAdd     NoticeType = "friend_add"           // 好友添加
+	NoticeTypeGroupRecall   NoticeType = "group_recall"         // 群消息撤回
+	NoticeTypeFriendRecall  NoticeType = "friend_recall"        // 好友消息撤回
+	NoticeTypeNotify        NoticeType = "notify"               // 戳一戳、运气王、群荣誉等
+	NoticeTypeGroupCard     NoticeType = "group_card"           // 群名片变更（扩展）
+	NoticeTypeEssence       NoticeType = "essence"              // 精华消息（扩展）
+	NoticeTypeReaction      NoticeType = "reaction"             // 表情回应（扩展）
+	NoticeTypeEmojiLike     NoticeType = "group_msg_emoji_like" // 表情回应（NapCat 扩展）
+)
+
+// notify 通知的子类型
+const (
+	SubTypePoke      SubType = "poke"       // 戳一戳
+	SubTypeLuckyKing SubType = "lucky_king" // 红包运气王
+	SubTypeHonor     SubType = "honor"      // 群荣誉变更
+)
+
+type RequestType string
+
+// 请求类型
+const (
+	RequestTypeFriend RequestType = "friend" // 加好友请求
+	RequestTypeGroup  RequestType = "group"  // 加群请求（add）或邀请机器人入群（invite）
+)
+
+// 加群请求的子类型
+const (
+	SubTypeAdd    SubType = "add"    // 申请加群
+	SubTypeInvite SubType = "invite" // 邀请机器人入群
+)
+
+type MetaEventType string
+
+// 元事件类型
+const (
+	MetaEventTypeLifecycle MetaEventType = "lifecycle" // 生命周期，sub_type 为 connect、enable、disable
+	MetaEventTypeHeartbeat MetaEventType = "heartbeat" // 心跳
+)
+
+// 生命周期子类型（connect 见 SubTypeConnect）
+const (
+	SubTypeEnable  SubType = "enable"  // OneBot 启用
+	SubTypeDisable SubType = "disable" // OneBot 停用
+)
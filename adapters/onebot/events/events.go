@@ -22,6 +22,7 @@ type Event struct {
 	MessageIDInt    int              `json:"message_id"`
 	GroupIDInt      int              `json:"group_id"`
 	MessageValue    any              `json:"message"`
+	MessageFormat   string           `json:"message_format"` // array 或 string（CQ 码）
 	SenderValue     *messages.Sender `json:"sender"`
 	MessageStyle    MessageStyle     `json:"message_style"`
 	TimeStamp       int              `json:"time"`
@@ -13,8 +13,10 @@ var _ event.MessageEvent = (*MessageEvent)(nil)
 
 type MessageEvent struct {
 	*Event
-	messageCache message.Message
-	once         sync.Once
+	messageCache  message.Message
+	once          sync.Once
+	standardCache message.Message
+	standardOnce  sync.Once
 }
 
 func (e *Event) UserID() string {
@@ -43,6 +45,14 @@ func (m *MessageEvent) Message() message.Message {
 	return m.messageCache
 }
 
+// StandardMessage implements event.MessageEvent.
+func (m *MessageEvent) StandardMessage() message.Message {
+	m.standardOnce.Do(func() {
+		m.standardCache = messages.ToStandard(m.Message())
+	})
+	return m.standardCache
+}
+
 func (m *MessageEvent) MessageID() string {
 	return strconv.Itoa(m.MessageIDInt)
 }
@@ -1,21 +1,109 @@
 package events
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
 	"yora/pkg/event"
 )
 
-var _ event.MetaEvent = (*MetaEvent)(nil)
+var (
+	_ event.MetaEvent = (*MetaEvent)(nil)
+	_ event.MetaEvent = (*LifecycleEvent)(nil)
+	_ event.MetaEvent = (*HeartbeatEvent)(nil)
+)
 
+// MetaEvent 元事件，未知类型的元事件解析为此类型
 type MetaEvent struct {
 	Event
+	MetaEventTypeValue MetaEventType `json:"meta_event_type"`
+
+	extra map[string]any // 原始字段
+}
+
+// MetaEventType 元事件类型，lifecycle 或 heartbeat
+func (m *MetaEvent) MetaEventType() string {
+	return string(m.MetaEventTypeValue)
 }
 
-// Extra implements event.MetaEvent.
+// Extra 元事件的原始字段
 func (m *MetaEvent) Extra() map[string]any {
-	panic("unimplemented")
+	return m.extra
 }
 
-// Status implements event.MetaEvent.
+// Status 协议端状态（心跳事件的 status 字段），没有时为空
 func (m *MetaEvent) Status() map[string]any {
-	panic("unimplemented")
+	status, _ := m.extra["status"].(map[string]any)
+	return status
+}
+
+func (m *MetaEvent) meta() *MetaEvent {
+	return m
+}
+
+// LifecycleEvent 生命周期事件
+type LifecycleEvent struct {
+	MetaEvent
+}
+
+// HeartbeatEvent 心跳事件
+type HeartbeatEvent struct {
+	MetaEvent
+	IntervalMs  int64           `json:"interval"` // 到下次心跳的间隔（毫秒）
+	StatusValue HeartbeatStatus `json:"status"`
+}
+
+// HeartbeatStatus 心跳中的协议端状态
+type HeartbeatStatus struct {
+	Online *bool `json:"online"` // 账号是否在线，未报告时为空
+	Good   bool  `json:"good"`   // 协议端运行状态是否正常
+}
+
+// Interval 到下次心跳的间隔
+func (h *HeartbeatEvent) Interval() time.Duration {
+	return time.Duration(h.IntervalMs) * time.Millisecond
+}
+
+// Online 账号是否在线，未报告时以 good 为准
+func (h *HeartbeatEvent) Online() bool {
+	if h.StatusValue.Online == nil {
+		return h.StatusValue.Good
+	}
+	return *h.StatusValue.Online
+}
+
+// Good 协议端运行状态是否正常
+func (h *HeartbeatEvent) Good() bool {
+	return h.StatusValue.Good
+}
+
+// ParseMeta 按 meta_event_type 解析为具体的元事件类型，未知类型解析为 *MetaEvent
+func ParseMeta(data []byte) (event.MetaEvent, error) {
+	var head struct {
+		MetaEventType MetaEventType `json:"meta_event_type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("解析元事件类型失败: %w", err)
+	}
+
+	var m interface {
+		event.MetaEvent
+		meta() *MetaEvent
+	}
+	switch head.MetaEventType {
+	case MetaEventTypeLifecycle:
+		m = &LifecycleEvent{}
+	case MetaEventTypeHeartbeat:
+		m = &HeartbeatEvent{}
+	default:
+		m = &MetaEvent{}
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("解析 %s 元事件失败: %w", head.MetaEventType, err)
+	}
+	if err := json.Unmarshal(data, &m.meta().extra); err != nil {
+		return nil, fmt.Errorf("解析 %s 元事件失败: %w", head.MetaEventType, err)
+	}
+	return m, nil
 }
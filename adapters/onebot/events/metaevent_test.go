@@ -0,0 +1,36 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMeta(t *testing.T) {
+	m, err := ParseMeta([]byte(`{"post_type":"meta_event","meta_event_type":"heartbeat","self_id":10,"interval":5000,"status":{"online":false,"good":true}}`))
+	require.NoError(t, err)
+	hb := m.(*HeartbeatEvent)
+	assert.Equal(t, "10", hb.SelfID())
+	assert.Equal(t, 5*time.Second, hb.Interval())
+	assert.False(t, hb.Online())
+	assert.True(t, hb.Good())
+	assert.Equal(t, false, hb.Status()["online"])
+	assert.Contains(t, hb.Extra(), "interval")
+
+	// 未报告 online 时以 good 为准
+	m, err = ParseMeta([]byte(`{"meta_event_type":"heartbeat","interval":5000,"status":{"good":true}}`))
+	require.NoError(t, err)
+	assert.True(t, m.(*HeartbeatEvent).Online())
+
+	m, err = ParseMeta([]byte(`{"post_type":"meta_event","meta_event_type":"lifecycle","sub_type":"connect"}`))
+	require.NoError(t, err)
+	assert.IsType(t, &LifecycleEvent{}, m)
+	assert.Equal(t, "connect", m.SubType())
+	assert.Nil(t, m.Status())
+
+	m, err = ParseMeta([]byte(`{"meta_event_type":"unknown"}`))
+	require.NoError(t, err)
+	assert.IsType(t, &MetaEvent{}, m)
+}
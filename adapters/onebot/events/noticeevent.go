@@ -1,28 +1,59 @@
 package events
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"yora/pkg/event"
 )
 
-var _ event.NoticeEvent = (*NoticeEvent)(nil)
+var (
+	_ event.NoticeEvent = (*NoticeEvent)(nil)
+	_ event.NoticeEvent = (*GroupUploadNotice)(nil)
+	_ event.NoticeEvent = (*GroupAdminNotice)(nil)
+	_ event.NoticeEvent = (*GroupDecreaseNotice)(nil)
+	_ event.NoticeEvent = (*GroupIncreaseNotice)(nil)
+	_ event.NoticeEvent = (*GroupBanNotice)(nil)
+	_ event.NoticeEvent = (*FriendAddNotice)(nil)
+	_ event.NoticeEvent = (*GroupRecallNotice)(nil)
+	_ event.NoticeEvent = (*FriendRecallNotice)(nil)
+	_ event.NoticeEvent = (*PokeNotice)(nil)
+	_ event.NoticeEvent = (*LuckyKingNotice)(nil)
+	_ event.NoticeEvent = (*HonorNotice)(nil)
+	_ event.NoticeEvent = (*GroupCardNotice)(nil)
+	_ event.NoticeEvent = (*EssenceNotice)(nil)
+	_ event.NoticeEvent = (*ReactionNotice)(nil)
+)
 
+// NoticeEvent 通知事件，未知类型的通知解析为此类型
 type NoticeEvent struct {
 	Event
+	NoticeTypeValue NoticeType `json:"notice_type"`
+	OperatorIDInt   int        `json:"operator_id"`
+
+	extra map[string]any // 原始字段
 }
 
 func (n *NoticeEvent) ChatID() string {
 	return strconv.Itoa(n.GroupIDInt)
 }
 
-// todo
+// NoticeType 通知类型，如 group_increase、notify
+func (n *NoticeEvent) NoticeType() string {
+	return string(n.NoticeTypeValue)
+}
+
+// Extra 通知的原始字段
 func (n *NoticeEvent) Extra() map[string]any {
-	return nil
+	return n.extra
 }
 
-// todo
+// OperatorID 操作者ID，没有操作者时为空
 func (n *NoticeEvent) OperatorID() string {
-	return ""
+	if n.OperatorIDInt == 0 {
+		return ""
+	}
+	return strconv.Itoa(n.OperatorIDInt)
 }
 
 // UserID implements event.NoticeEvent.
@@ -30,3 +61,199 @@ func (n *NoticeEvent) OperatorID() string {
 func (n *NoticeEvent) UserID() string {
 	return strconv.Itoa(n.UserIDInt)
 }
+
+func (n *NoticeEvent) notice() *NoticeEvent {
+	return n
+}
+
+// GroupUploadNotice 群文件上传
+type GroupUploadNotice struct {
+	NoticeEvent
+	File struct {
+		ID    string `json:"id"`    // 文件ID
+		Name  string `json:"name"`  // 文件名
+		Size  int64  `json:"size"`  // 文件大小（字节）
+		Busid int64  `json:"busid"` // 用途未知
+	} `json:"file"`
+}
+
+// GroupAdminNotice 群管理员变动，sub_type 为 set 或 unset
+type GroupAdminNotice struct {
+	NoticeEvent
+}
+
+// IsSet 是否为设置管理员
+func (n *GroupAdminNotice) IsSet() bool {
+	return n.SubTypeValue == "set"
+}
+
+// GroupDecreaseNotice 群成员减少，sub_type 为 leave（主动退群）、kick（被踢）、kick_me（机器人被踢）
+type GroupDecreaseNotice struct {
+	NoticeEvent
+}
+
+// GroupIncreaseNotice 群成员增加，sub_type 为 approve（管理员同意）或 invite（邀请）
+type GroupIncreaseNotice struct {
+	NoticeEvent
+}
+
+// GroupBanNotice 群禁言，sub_type 为 ban 或 lift_ban，user_id 为 0 时表示全员禁言
+type GroupBanNotice struct {
+	NoticeEvent
+	Duration int64 `json:"duration"` // 禁言时长（秒）
+}
+
+// IsBan 是否为禁言（否则为解除禁言）
+func (n *GroupBanNotice) IsBan() bool {
+	return n.SubTypeValue == "ban"
+}
+
+// FriendAddNotice 好友添加
+type FriendAddNotice struct {
+	NoticeEvent
+}
+
+// GroupRecallNotice 群消息撤回，MessageID 为被撤回的消息
+type GroupRecallNotice struct {
+	NoticeEvent
+}
+
+func (n *GroupRecallNotice) MessageID() string {
+	return strconv.Itoa(n.MessageIDInt)
+}
+
+// FriendRecallNotice 好友消息撤回，MessageID 为被撤回的消息
+type FriendRecallNotice struct {
+	NoticeEvent
+}
+
+func (n *FriendRecallNotice) MessageID() string {
+	return strconv.Itoa(n.MessageIDInt)
+}
+
+// PokeNotice 戳一戳，user_id 为发起者，私聊时群ID为 0
+type PokeNotice struct {
+	NoticeEvent
+	TargetIDInt int `json:"target_id"` // 被戳者
+}
+
+func (n *PokeNotice) TargetID() string {
+	return strconv.Itoa(n.TargetIDInt)
+}
+
+// IsToMe 是否戳的是机器人
+func (n *PokeNotice) IsToMe() bool {
+	return n.TargetIDInt != 0 && n.TargetIDInt == n.SelfIDInt
+}
+
+// LuckyKingNotice 红包运气王，user_id 为发红包者
+type LuckyKingNotice struct {
+	NoticeEvent
+	TargetIDInt int `json:"target_id"` // 运气王
+}
+
+func (n *LuckyKingNotice) TargetID() string {
+	return strconv.Itoa(n.TargetIDInt)
+}
+
+// HonorNotice 群荣誉变更
+type HonorNotice struct {
+	NoticeEvent
+	HonorType string `json:"honor_type"` // talkative（龙王）、performer（群聊之火）、emotion（快乐源泉）
+}
+
+// GroupCardNotice 群名片变更
+type GroupCardNotice struct {
+	NoticeEvent
+	CardNew string `json:"card_new"` // 新名片
+	CardOld string `json:"card_old"` // 旧名片
+}
+
+// EssenceNotice 精华消息，sub_type 为 add 或 delete
+type EssenceNotice struct {
+	NoticeEvent
+	SenderIDInt int `json:"sender_id"` // 消息发送者
+}
+
+func (n *EssenceNotice) SenderID() string {
+	return strconv.Itoa(n.SenderIDInt)
+}
+
+func (n *EssenceNotice) MessageID() string {
+	return strconv.Itoa(n.MessageIDInt)
+}
+
+// ReactionNotice 表情回应，兼容 reaction（sub_type 为 add 或 remove）与 NapCat 的 group_msg_emoji_like
+type ReactionNotice struct {
+	NoticeEvent
+	Code  string `json:"code"`  // 表情ID（reaction）
+	Count int    `json:"count"` // 回应数量（reaction）
+	Likes []struct {
+		EmojiID string `json:"emoji_id"`
+		Count   int    `json:"count"`
+	} `json:"likes"` // 表情列表（group_msg_emoji_like）
+}
+
+func (n *ReactionNotice) MessageID() string {
+	return strconv.Itoa(n.MessageIDInt)
+}
+
+// ParseNotice 按 notice_type 与 sub_type 解析为具体的通知类型，未知类型解析为 *NoticeEvent
+func ParseNotice(data []byte) (event.NoticeEvent, error) {
+	var head struct {
+		NoticeType NoticeType `json:"notice_type"`
+		SubType    SubType    `json:"sub_type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("解析通知类型失败: %w", err)
+	}
+
+	var n interface {
+		event.NoticeEvent
+		notice() *NoticeEvent
+	}
+	switch head.NoticeType {
+	case NoticeTypeGroupUpload:
+		n = &GroupUploadNotice{}
+	case NoticeTypeGroupAdmin:
+		n = &GroupAdminNotice{}
+	case NoticeTypeGroupDecrease:
+		n = &GroupDecreaseNotice{}
+	case NoticeTypeGroupIncrease:
+		n = &GroupIncreaseNotice{}
+	case NoticeTypeGroupBan:
+		n = &GroupBanNotice{}
+	case NoticeTypeFriendAdd:
+		n = &FriendAddNotice{}
+	case NoticeTypeGroupRecall:
+		n = &GroupRecallNotice{}
+	case NoticeTypeFriendRecall:
+		n = &FriendRecallNotice{}
+	case NoticeTypeGroupCard:
+		n = &GroupCardNotice{}
+	case NoticeTypeEssence:
+		n = &EssenceNotice{}
+	case NoticeTypeReaction, NoticeTypeEmojiLike:
+		n = &ReactionNotice{}
+	case NoticeTypeNotify:
+		switch head.SubType {
+		case SubTypePoke:
+			n = &PokeNotice{}
+		case SubTypeLuckyKing:
+			n = &LuckyKingNotice{}
+		case SubTypeHonor:
+			n = &HonorNotice{}
+		}
+	}
+	if n == nil {
+		n = &NoticeEvent{}
+	}
+
+	if err := json.Unmarshal(data, n); err != nil {
+		return nil, fmt.Errorf("解析 %s 通知失败: %w", head.NoticeType, err)
+	}
+	if err := json.Unmarshal(data, &n.notice().extra); err != nil {
+		return nil, fmt.Errorf("解析 %s 通知失败: %w", head.NoticeType, err)
+	}
+	return n, nil
+}
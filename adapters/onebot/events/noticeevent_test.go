@@ -0,0 +1,66 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNoticeTypes(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want any
+	}{
+		{`{"notice_type":"group_upload","file":{"id":"f","name":"a.txt","size":3}}`, &GroupUploadNotice{}},
+		{`{"notice_type":"group_admin","sub_type":"set"}`, &GroupAdminNotice{}},
+		{`{"notice_type":"group_decrease","sub_type":"kick"}`, &GroupDecreaseNotice{}},
+		{`{"notice_type":"group_increase","sub_type":"approve"}`, &GroupIncreaseNotice{}},
+		{`{"notice_type":"group_ban","sub_type":"ban","duration":60}`, &GroupBanNotice{}},
+		{`{"notice_type":"friend_add"}`, &FriendAddNotice{}},
+		{`{"notice_type":"group_recall","message_id":1}`, &GroupRecallNotice{}},
+		{`{"notice_type":"friend_recall","message_id":1}`, &FriendRecallNotice{}},
+		{`{"notice_type":"notify","sub_type":"poke","target_id":1}`, &PokeNotice{}},
+		{`{"notice_type":"notify","sub_type":"lucky_king","target_id":1}`, &LuckyKingNotice{}},
+		{`{"notice_type":"notify","sub_type":"honor","honor_type":"talkative"}`, &HonorNotice{}},
+		{`{"notice_type":"group_card","card_new":"a","card_old":"b"}`, &GroupCardNotice{}},
+		{`{"notice_type":"essence","sub_type":"add","sender_id":1}`, &EssenceNotice{}},
+		{`{"notice_type":"reaction","sub_type":"add","code":"76"}`, &ReactionNotice{}},
+		{`{"notice_type":"group_msg_emoji_like","likes":[{"emoji_id":"76","count":1}]}`, &ReactionNotice{}},
+		{`{"notice_type":"notify","sub_type":"title"}`, &NoticeEvent{}},
+		{`{"notice_type":"unknown"}`, &NoticeEvent{}},
+	}
+	for _, c := range cases {
+		n, err := ParseNotice([]byte(c.raw))
+		require.NoError(t, err, c.raw)
+		assert.IsType(t, c.want, n, c.raw)
+	}
+}
+
+func TestParseNoticeFields(t *testing.T) {
+	n, err := ParseNotice([]byte(`{"post_type":"notice","notice_type":"notify","sub_type":"poke","self_id":10,"user_id":1,"target_id":10,"group_id":100,"raw_info":[1]}`))
+	require.NoError(t, err)
+	poke := n.(*PokeNotice)
+	assert.Equal(t, "notify", poke.NoticeType())
+	assert.Equal(t, "poke", poke.SubType())
+	assert.Equal(t, "1", poke.UserID())
+	assert.Equal(t, "100", poke.ChatID())
+	assert.True(t, poke.IsToMe())
+	assert.Equal(t, "", poke.OperatorID())
+	assert.Contains(t, poke.Extra(), "raw_info")
+
+	n, err = ParseNotice([]byte(`{"notice_type":"group_decrease","sub_type":"kick","user_id":1,"operator_id":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, "2", n.OperatorID())
+
+	n, err = ParseNotice([]byte(`{"notice_type":"group_ban","sub_type":"lift_ban","duration":0}`))
+	require.NoError(t, err)
+	assert.False(t, n.(*GroupBanNotice).IsBan())
+
+	n, err = ParseNotice([]byte(`{"notice_type":"group_upload","file":{"id":"f","name":"a.txt","size":3}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", n.(*GroupUploadNotice).File.Name)
+
+	_, err = ParseNotice([]byte(`{`))
+	assert.Error(t, err)
+}
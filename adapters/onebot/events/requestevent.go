@@ -1,39 +1,164 @@
 package events
 
 import (
-	"time"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"yora/adapters/onebot/api"
 	"yora/pkg/event"
 )
 
-var _ event.RequestEvent = (*RequestEvent)(nil)
+var (
+	_ event.RequestEvent = (*RequestEvent)(nil)
+	_ event.RequestEvent = (*FriendRequest)(nil)
+	_ event.RequestEvent = (*GroupRequest)(nil)
+)
+
+// RequestAPI 处理请求所需的 API，*api.API 实现了此接口
+type RequestAPI interface {
+	SetFriendAdd(ctx context.Context, flag string, approve bool, remark string) error
+	SetGroupAdd(ctx context.Context, flag string, subType string, approve bool, reason string) error
+}
 
+// RequestEvent 请求事件，未知类型的请求解析为此类型
 type RequestEvent struct {
 	Event
+	RequestTypeValue RequestType `json:"request_type"`
+	CommentValue     string      `json:"comment"`
+	FlagValue        string      `json:"flag"`
+
+	extra map[string]any // 原始字段
+	api   RequestAPI     // 为空时使用默认连接
 }
 
-// Comment implements event.RequestEvent.
-func (r *RequestEvent) Comment() string {
-	panic("unimplemented")
+// RequestType 请求类型，friend 或 group
+func (r *RequestEvent) RequestType() string {
+	return string(r.RequestTypeValue)
 }
 
-// Extra implements event.RequestEvent.
-func (r *RequestEvent) Extra() map[string]any {
-	panic("unimplemented")
+// Comment 验证信息，加群请求时一般为 "问题：...\n答案：..."
+func (r *RequestEvent) Comment() string {
+	return r.CommentValue
 }
 
-// Flag implements event.RequestEvent.
+// Flag 请求标识，用于同意或拒绝请求
 func (r *RequestEvent) Flag() string {
-	panic("unimplemented")
+	return r.FlagValue
+}
+
+// Extra 请求的原始字段
+func (r *RequestEvent) Extra() map[string]any {
+	return r.extra
 }
 
-// Time implements event.RequestEvent.
-// Subtle: this method shadows the method (Event).Time of RequestEvent.Event.
-func (r *RequestEvent) Time() time.Time {
-	panic("unimplemented")
+func (r *RequestEvent) ChatID() string {
+	return strconv.Itoa(r.GroupIDInt)
 }
 
 // UserID implements event.RequestEvent.
 // Subtle: this method shadows the method (Event).UserID of RequestEvent.Event.
 func (r *RequestEvent) UserID() string {
-	panic("unimplemented")
+	return strconv.Itoa(r.UserIDInt)
+}
+
+// SetAPI 设置处理请求使用的 API（多账号时为接收请求的账号）
+func (r *RequestEvent) SetAPI(a RequestAPI) {
+	r.api = a
+}
+
+func (r *RequestEvent) requestAPI() RequestAPI {
+	if r.api == nil {
+		return api.GetAPI()
+	}
+	return r.api
+}
+
+// Approve 未知类型的请求无法处理
+func (r *RequestEvent) Approve(ctx context.Context, remark string) error {
+	return fmt.Errorf("不支持处理 %s 类型的请求", r.RequestTypeValue)
+}
+
+// Reject 未知类型的请求无法处理
+func (r *RequestEvent) Reject(ctx context.Context, reason string) error {
+	return fmt.Errorf("不支持处理 %s 类型的请求", r.RequestTypeValue)
+}
+
+func (r *RequestEvent) request() *RequestEvent {
+	return r
+}
+
+// FriendRequest 加好友请求
+type FriendRequest struct {
+	RequestEvent
+}
+
+// Approve 同意加好友，remark 为好友备注
+func (r *FriendRequest) Approve(ctx context.Context, remark string) error {
+	return r.requestAPI().SetFriendAdd(ctx, r.FlagValue, true, remark)
+}
+
+// Reject 拒绝加好友（OneBot 不支持拒绝理由，reason 被忽略）
+func (r *FriendRequest) Reject(ctx context.Context, reason string) error {
+	return r.requestAPI().SetFriendAdd(ctx, r.FlagValue, false, "")
+}
+
+// GroupRequest 加群请求，sub_type 为 add（申请加群）或 invite（邀请机器人入群）
+type GroupRequest struct {
+	RequestEvent
+	InvitorIDInt int `json:"invitor_id"` // 邀请者（扩展字段，申请加群时可能存在）
+}
+
+// IsInvite 是否为邀请机器人入群
+func (r *GroupRequest) IsInvite() bool {
+	return r.SubTypeValue == SubTypeInvite
+}
+
+// InvitorID 邀请者ID，没有时为空
+func (r *GroupRequest) InvitorID() string {
+	if r.InvitorIDInt == 0 {
+		return ""
+	}
+	return strconv.Itoa(r.InvitorIDInt)
+}
+
+// Approve 同意加群请求或邀请（remark 被忽略）
+func (r *GroupRequest) Approve(ctx context.Context, remark string) error {
+	return r.requestAPI().SetGroupAdd(ctx, r.FlagValue, string(r.SubTypeValue), true, "")
+}
+
+// Reject 拒绝加群请求或邀请
+func (r *GroupRequest) Reject(ctx context.Context, reason string) error {
+	return r.requestAPI().SetGroupAdd(ctx, r.FlagValue, string(r.SubTypeValue), false, reason)
+}
+
+// ParseRequest 按 request_type 解析为具体的请求类型，未知类型解析为 *RequestEvent
+func ParseRequest(data []byte) (event.RequestEvent, error) {
+	var head struct {
+		RequestType RequestType `json:"request_type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("解析请求类型失败: %w", err)
+	}
+
+	var r interface {
+		event.RequestEvent
+		request() *RequestEvent
+	}
+	switch head.RequestType {
+	case RequestTypeFriend:
+		r = &FriendRequest{}
+	case RequestTypeGroup:
+		r = &GroupRequest{}
+	default:
+		r = &RequestEvent{}
+	}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("解析 %s 请求失败: %w", head.RequestType, err)
+	}
+	if err := json.Unmarshal(data, &r.request().extra); err != nil {
+		return nil, fmt.Errorf("解析 %s 请求失败: %w", head.RequestType, err)
+	}
+	return r, nil
 }
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	flag, subType, text string
+	approve             bool
+}
+
+type fakeRequestAPI struct {
+	calls []call
+}
+
+func (f *fakeRequestAPI) SetFriendAdd(ctx context.Context, flag string, approve bool, remark string) error {
+	f.calls = append(f.calls, call{flag: flag, approve: approve, text: remark})
+	return nil
+}
+
+func (f *fakeRequestAPI) SetGroupAdd(ctx context.Context, flag string, subType string, approve bool, reason string) error {
+	f.calls = append(f.calls, call{flag: flag, subType: subType, approve: approve, text: reason})
+	return nil
+}
+
+func TestParseRequest(t *testing.T) {
+	fake := &fakeRequestAPI{}
+	ctx := context.Background()
+
+	r, err := ParseRequest([]byte(`{"post_type":"request","request_type":"friend","user_id":1,"comment":"hi","flag":"f1","time":100}`))
+	require.NoError(t, err)
+	friend := r.(*FriendRequest)
+	friend.SetAPI(fake)
+	assert.Equal(t, "friend", friend.RequestType())
+	assert.Equal(t, "1", friend.UserID())
+	assert.Equal(t, "hi", friend.Comment())
+	assert.Equal(t, int64(100), friend.Time().Unix())
+	assert.Equal(t, "f1", friend.Extra()["flag"])
+	require.NoError(t, friend.Approve(ctx, "备注"))
+
+	r, err = ParseRequest([]byte(`{"post_type":"request","request_type":"group","sub_type":"add","group_id":2,"user_id":1,"flag":"f2"}`))
+	require.NoError(t, err)
+	group := r.(*GroupRequest)
+	group.SetAPI(fake)
+	assert.False(t, group.IsInvite())
+	assert.Equal(t, "2", group.ChatID())
+	require.NoError(t, group.Reject(ctx, "答案错误"))
+
+	assert.Equal(t, []call{
+		{flag: "f1", approve: true, text: "备注"},
+		{flag: "f2", subType: "add", approve: false, text: "答案错误"},
+	}, fake.calls)
+
+	r, err = ParseRequest([]byte(`{"post_type":"request","request_type":"unknown"}`))
+	require.NoError(t, err)
+	assert.Error(t, r.Approve(ctx, ""))
+}
@@ -0,0 +1,141 @@
+package messages
+
+import (
+	"strings"
+	basemsg "yora/pkg/message"
+)
+
+// 标准消息段类型与 OneBot 消息段类型不同的对应关系
+var (
+	nativeTypes = map[string]string{
+		basemsg.TypeAudio: "record",
+		basemsg.TypeEmoji: "face",
+		basemsg.TypeLink:  "share",
+	}
+	standardTypes = map[string]string{
+		"record": basemsg.TypeAudio,
+		"face":   basemsg.TypeEmoji,
+		"share":  basemsg.TypeLink,
+	}
+)
+
+// FromMessage 将任意实现了 message.Message 的消息转换为 OneBot 消息，
+// 标准消息段转换为对应的 OneBot 消息段，OneBot 消息段原样保留
+func FromMessage(msg basemsg.Message) Message {
+	if m, ok := msg.(Message); ok {
+		return m
+	}
+
+	segments := msg.Segments()
+	result := make(Message, 0, len(segments))
+	for _, seg := range segments {
+		switch s := seg.(type) {
+		case *Segment:
+			result = append(result, s)
+		case Segment:
+			result = append(result, &s)
+		default:
+			result = append(result, ToNativeSegment(seg))
+		}
+	}
+	return result
+}
+
+// ToNativeSegment 将标准消息段转换为 OneBot 消息段，非标准类型原样转换
+func ToNativeSegment(seg basemsg.Segment) *Segment {
+	data := copyData(seg.Data())
+
+	switch seg.Type() {
+	case basemsg.TypeAt:
+		// 标准消息段使用 user_id，OneBot 使用 qq；已使用 qq 字段的消息段原样保留
+		if id, ok := data["user_id"]; ok {
+			delete(data, "user_id")
+			data["qq"] = id
+		}
+	case basemsg.TypeFile:
+		if _, ok := data["name"]; !ok {
+			data["name"] = basemsg.StringData(seg, "file")
+		}
+	case basemsg.TypeContact:
+		if basemsg.StringData(seg, "type") == basemsg.ContactUser {
+			data["type"] = "qq"
+		}
+	case basemsg.TypeCode:
+		return NewSegment("text", map[string]any{"text": basemsg.StringData(seg, "code")})
+	case basemsg.TypeQuote:
+		lines := strings.Split(basemsg.StringData(seg, "text"), "\n")
+		for i, line := range lines {
+			lines[i] = "> " + line
+		}
+		return NewSegment("text", map[string]any{"text": strings.Join(lines, "\n")})
+	}
+
+	typ := seg.Type()
+	if native, ok := nativeTypes[typ]; ok {
+		typ = native
+	}
+	return NewSegment(typ, data)
+}
+
+// ToStandard 将 OneBot 消息转换为标准消息，没有对应标准类型的消息段原样保留
+func ToStandard(msg basemsg.Message) basemsg.Segments {
+	segments := msg.Segments()
+	result := make(basemsg.Segments, 0, len(segments))
+	for _, seg := range segments {
+		result = append(result, ToStandardSegment(seg))
+	}
+	return result
+}
+
+// ToStandardSegment 将 OneBot 消息段转换为标准消息段
+func ToStandardSegment(seg basemsg.Segment) basemsg.Segment {
+	switch seg.Type() {
+	case "text":
+		return basemsg.NewTextSegment(basemsg.StringData(seg, "text"))
+	case "at":
+		return basemsg.NewAtSegment(basemsg.StringData(seg, "qq"))
+	case "image", "record", "video", "file":
+		typ := seg.Type()
+		if std, ok := standardTypes[typ]; ok {
+			typ = std
+		}
+		// 接收到的媒体优先使用可下载的 url
+		file := basemsg.StringData(seg, "url")
+		if file == "" {
+			file = basemsg.StringData(seg, "file")
+		}
+		name := basemsg.StringData(seg, "name")
+		if name == "" {
+			name = basemsg.StringData(seg, "file")
+		}
+		return basemsg.NewSegment(typ, map[string]any{"file": file, "name": name})
+	case "face":
+		return basemsg.NewEmojiSegment(basemsg.StringData(seg, "id"))
+	case "reply":
+		return basemsg.NewReplySegment(basemsg.StringData(seg, "id"))
+	case "forward":
+		return basemsg.NewForwardSegment(basemsg.StringData(seg, "id"))
+	case "contact":
+		contactType := basemsg.StringData(seg, "type")
+		if contactType == "qq" {
+			contactType = basemsg.ContactUser
+		}
+		return basemsg.NewContactSegment(contactType, basemsg.StringData(seg, "id"))
+	case "share":
+		return basemsg.NewLinkSegment(
+			basemsg.StringData(seg, "url"),
+			basemsg.StringData(seg, "title"),
+			basemsg.StringData(seg, "content"),
+			basemsg.StringData(seg, "image"),
+		)
+	}
+	return seg
+}
+
+func copyData(data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	return result
+}
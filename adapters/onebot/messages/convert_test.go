@@ -0,0 +1,71 @@
+package messages
+
+import (
+	"testing"
+	basemsg "yora/pkg/message"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMessageStandard(t *testing.T) {
+	msg := basemsg.NewBuilder().
+		At("1").
+		Audio("base64://AAAA").
+		Emoji("14").
+		Link("https://example.com", "标题", "", "").
+		Contact(basemsg.ContactUser, "2").
+		Quote("a\nb").
+		Segment(NewPokeSegment("1", "", "3")).
+		Build()
+
+	native := FromMessage(msg)
+	require.Len(t, native, 7)
+
+	cases := []struct {
+		typ  string
+		key  string
+		want any
+	}{
+		{"at", "qq", "1"},
+		{"record", "file", "base64://AAAA"},
+		{"face", "id", "14"},
+		{"share", "url", "https://example.com"},
+		{"contact", "type", "qq"},
+		{"text", "text", "> a\n> b"},
+		{"poke", "id", "3"},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.typ, native[i].Type())
+		v, _ := native[i].GetData(c.key)
+		assert.Equal(t, c.want, v, c.typ)
+	}
+
+	// 不修改原消息
+	_, ok := msg.Segments()[0].GetData("qq")
+	assert.False(t, ok)
+}
+
+func TestToStandard(t *testing.T) {
+	msg := New([]any{
+		map[string]any{"type": "at", "data": map[string]any{"qq": "all"}},
+		map[string]any{"type": "image", "data": map[string]any{"file": "a.image", "url": "https://example.com/a"}},
+		map[string]any{"type": "face", "data": map[string]any{"id": "14"}},
+		map[string]any{"type": "text", "data": map[string]any{"text": "hi"}},
+		map[string]any{"type": "dice", "data": map[string]any{"result": "3"}},
+	})
+
+	std := ToStandard(msg)
+	require.Len(t, std, 5)
+	assert.Equal(t, basemsg.AtAll, basemsg.StringData(std[0], "user_id"))
+	assert.Equal(t, "https://example.com/a", basemsg.StringData(std[1], "file"))
+	assert.Equal(t, "a.image", basemsg.StringData(std[1], "name"))
+	assert.Equal(t, basemsg.TypeEmoji, std[2].Type())
+	assert.Equal(t, "hi", std.PlainText())
+	assert.Equal(t, "dice", std[4].Type())
+
+	// 往返转换
+	back := FromMessage(std)
+	assert.Equal(t, "all", back[0].Data()["qq"])
+	assert.Equal(t, "face", back[2].Type())
+}
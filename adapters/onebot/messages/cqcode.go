@@ -0,0 +1,147 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	basemsg "yora/pkg/message"
+)
+
+// CQ 码转义：文本中转义 & [ ]，参数值中额外转义 ,
+var (
+	cqTextEscaper  = strings.NewReplacer("&", "&amp;", "[", "&#91;", "]", "&#93;")
+	cqParamEscaper = strings.NewReplacer("&", "&amp;", "[", "&#91;", "]", "&#93;", ",", "&#44;")
+	cqUnescaper    = strings.NewReplacer("&#91;", "[", "&#93;", "]", "&#44;", ",", "&amp;", "&")
+)
+
+// ErrCQSyntax CQ 码格式错误
+var ErrCQSyntax = errors.New("CQ 码格式错误")
+
+// EscapeCQ 转义文本，param 为 true 时按参数值转义（额外转义逗号）
+func EscapeCQ(s string, param bool) string {
+	if param {
+		return cqParamEscaper.Replace(s)
+	}
+	return cqTextEscaper.Replace(s)
+}
+
+// UnescapeCQ 反转义 CQ 码文本或参数值
+func UnescapeCQ(s string) string {
+	return cqUnescaper.Replace(s)
+}
+
+// ParseCQ 解析字符串格式（CQ 码）的消息，如 "[CQ:at,qq=123]hello"
+//
+// 参数值均解析为字符串；CQ 码外的文本解析为 text 片段。
+func ParseCQ(raw string) (Message, error) {
+	msg := Message{}
+	for raw != "" {
+		start := strings.Index(raw, "[CQ:")
+		if start < 0 {
+			msg = append(msg, NewTextSegment(UnescapeCQ(raw)))
+			break
+		}
+		if start > 0 {
+			msg = append(msg, NewTextSegment(UnescapeCQ(raw[:start])))
+		}
+
+		end := strings.IndexByte(raw[start:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: 未闭合的 CQ 码 %q", ErrCQSyntax, raw[start:])
+		}
+		seg, err := parseCQCode(raw[start+len("[CQ:") : start+end])
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, seg)
+		raw = raw[start+end+1:]
+	}
+	return msg, nil
+}
+
+// 解析 CQ 码内容，如 "at,qq=123"
+func parseCQCode(code string) (*Segment, error) {
+	parts := strings.Split(code, ",")
+	typ := parts[0]
+	if typ == "" {
+		return nil, fmt.Errorf("%w: CQ 码类型为空", ErrCQSyntax)
+	}
+
+	data := make(map[string]any, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%w: 参数 %q 缺少键或 =", ErrCQSyntax, p)
+		}
+		data[k] = UnescapeCQ(v)
+	}
+	return NewSegment(typ, data), nil
+}
+
+// CQString 将消息序列化为字符串格式（CQ 码）
+func (m Message) CQString() string {
+	var sb strings.Builder
+	for _, seg := range m {
+		writeCQ(&sb, seg)
+	}
+	return sb.String()
+}
+
+// 写入单个片段，参数按键排序，忽略 nil 与空字符串
+func writeCQ(sb *strings.Builder, seg basemsg.Segment) {
+	data := seg.Data()
+	if seg.Type() == "text" {
+		if text, ok := data["text"]; ok {
+			sb.WriteString(EscapeCQ(fmt.Sprint(text), false))
+		}
+		return
+	}
+
+	sb.WriteString("[CQ:")
+	sb.WriteString(seg.Type())
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		v := cqValue(data[k])
+		if v == "" {
+			continue
+		}
+		sb.WriteString(",")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(EscapeCQ(v, true))
+	}
+	sb.WriteString("]")
+}
+
+// 参数值转为字符串，复合类型按 JSON 序列化
+func cqValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]any, []any:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// CQMessage 以字符串格式（CQ 码）序列化为 JSON 的消息，用于偏好字符串格式的 OneBot 实现
+type CQMessage struct {
+	Message
+}
+
+func (m CQMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Message.CQString())
+}
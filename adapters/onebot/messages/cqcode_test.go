@@ -0,0 +1,64 @@
+package messages
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCQ(t *testing.T) {
+	msg, err := ParseCQ("[CQ:reply,id=42][CQ:at,qq=123] hello &#91;x&#93; &amp; more[CQ:image,file=a.png,url=https://x.com/?a=1&#44;2]")
+	require.NoError(t, err)
+	require.Len(t, msg, 4)
+
+	assert.Equal(t, "reply", msg[0].Type())
+	assert.Equal(t, map[string]any{"id": "42"}, msg[0].Data())
+	assert.Equal(t, map[string]any{"qq": "123"}, msg[1].Data())
+	assert.Equal(t, " hello [x] & more", msg[2].String())
+	assert.Equal(t, "image", msg[3].Type())
+	assert.Equal(t, "https://x.com/?a=1,2", msg[3].Data()["url"])
+
+	msg, err = ParseCQ("纯文本")
+	require.NoError(t, err)
+	assert.Equal(t, "纯文本", msg.PlainText())
+
+	msg, err = ParseCQ("")
+	require.NoError(t, err)
+	assert.True(t, msg.IsEmpty())
+
+	for _, bad := range []string{"[CQ:at,qq=1", "[CQ:]", "[CQ:at,qq]"} {
+		_, err := ParseCQ(bad)
+		assert.ErrorIs(t, err, ErrCQSyntax, bad)
+	}
+}
+
+func TestCQStringRoundTrip(t *testing.T) {
+	msg := NewHelper(Message{}).
+		Reply("42").
+		At("123").
+		Text(" a&b [c], d").
+		Message
+	msg = append(msg, NewSegment("image", map[string]any{"file": "x,y.png", "url": "", "sub_type": 0}))
+
+	raw := msg.CQString()
+	assert.Equal(t, "[CQ:reply,id=42][CQ:at,qq=123] a&amp;b &#91;c&#93;, d[CQ:image,file=x&#44;y.png,sub_type=0]", raw)
+
+	parsed, err := ParseCQ(raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, parsed.CQString())
+	assert.Equal(t, " a&b [c], d", parsed.PlainText())
+}
+
+func TestCQMessageMarshalsAsString(t *testing.T) {
+	raw, err := json.Marshal(map[string]any{"message": CQMessage{Message{NewAtSegment("1"), NewTextSegment("hi")}}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"message":"[CQ:at,qq=1]hi"}`, string(raw))
+}
+
+func TestEscapeCQ(t *testing.T) {
+	assert.Equal(t, "&amp;&#91;&#93;,", EscapeCQ("&[],", false))
+	assert.Equal(t, "&amp;&#91;&#93;&#44;", EscapeCQ("&[],", true))
+	assert.Equal(t, "&#91;", UnescapeCQ("&amp;#91;"), "仅反转义一次")
+}
@@ -79,6 +79,17 @@ type NodeBuilder struct {
 	builder *ForwardMessageBuilder
 }
 
+// 添加消息段
+func (nb *NodeBuilder) Segment(segs ...messages.Segment) *NodeBuilder {
+	nb.node.Data.Content = append(nb.node.Data.Content, segs...)
+	return nb
+}
+
+// 添加文本
+func (nb *NodeBuilder) Text(text string) *NodeBuilder {
+	return nb.Segment(messages.NewTextSegment(text))
+}
+
 // 结束当前节点构造，返回消息构造器
 func (nb *NodeBuilder) Done() *ForwardMessageBuilder {
 	return nb.builder
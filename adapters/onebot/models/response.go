@@ -4,6 +4,8 @@ package models
 type Response[T any] struct {
 	Status  string `json:"status"`
 	Retcode int    `json:"retcode"`
+	Message string `json:"message,omitempty"` // 失败原因
+	Wording string `json:"wording,omitempty"` // 面向用户的失败描述
 	Data    T      `json:"data,omitempty"`
 	Echo    string `json:"echo,omitempty"`
 }
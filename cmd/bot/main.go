@@ -6,21 +6,40 @@ import (
 	"syscall"
 	"time"
 	"yora/adapters/onebot/adapter"
+	"yora/adapters/onebot/client"
 	"yora/middleware"
 	"yora/pkg/bot"
 	"yora/pkg/conf"
+	"yora/pkg/log"
 	"yora/plugins/builtin/echo"
 	"yora/plugins/builtin/help"
+	"yora/plugins/builtin/manager"
+	"yora/plugins/builtin/request"
 
 	"github.com/rs/zerolog"
 )
 
+// 配置文件路径
+const configFile = "yora.yaml"
+
 func main() {
 	// 设置日志
 	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	logger := log.NewBot("main")
+
+	// 加载配置文件，连接模式等通过 onebot 配置项设置，如：
+	// onebot:
+	//   mode: ws
+	//   url: ws://127.0.0.1:3001
+	cfg := conf.NewBotConfig()
+	if _, err := os.Stat(configFile); err == nil {
+		if cfg, err = conf.LoadBotConfig(configFile); err != nil {
+			panic(err)
+		}
+	}
 
-	qqAdapter := adapter.NewAdapter()
-	bot := bot.NewBot(conf.NewBotConfig())
+	qqAdapter := adapter.NewAdapter().SetConfig(client.ConfigFrom(cfg.OneBot))
+	bot := bot.NewBot(cfg)
 
 	// 添加中间件
 	bot.RegisterMiddlewares(
@@ -29,11 +48,21 @@ func main() {
 		middleware.RecoveryMiddleware(),
 	)
 
+	// 出站中间件（发送消息前执行），如：
+	// bot.RegisterOutboundMiddlewares(middleware.CensorMiddleware([]string{"敏感词"}, "**"), middleware.SplitMiddleware(500, time.Second))
+
 	// 注册适配器
 	bot.RegisterAdapters(qqAdapter)
 
 	// 注册插件
-	bot.RegisterPlugins(echo.New(), help.New())
+	bot.RegisterPlugins(echo.New(), help.New(), request.New(request.DefaultConfig(), cfg.SuperUsers...))
+
+	// 插件管理依赖超级用户，未配置时不注册
+	if len(cfg.SuperUsers) > 0 {
+		bot.RegisterPlugins(manager.New(cfg.SuperUsers...))
+	} else {
+		logger.Warn().Msgf("未配置超级用户，插件管理不可用，可在 %s 中设置 super_users", configFile)
+	}
 	// bot.RegisterPlugins(funny.Plugins...)
 
 	// 启动机器人
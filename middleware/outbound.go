@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+	"yora/pkg/message"
+	"yora/pkg/middleware"
+)
+
+// CensorMiddleware 敏感词过滤：将文本中的敏感词替换为 mask
+func CensorMiddleware(words []string, mask string) middleware.OutboundMiddleware {
+	pairs := make([]string, 0, len(words)*2)
+	for _, w := range words {
+		if w != "" {
+			pairs = append(pairs, w, mask)
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return middleware.OutboundFunc("敏感词过滤", func(ctx context.Context, out *middleware.Outgoing, next middleware.SendFunc) (string, error) {
+		out.Message = mapText(out.Message, replacer.Replace)
+		if out.IsForward() {
+			nodes := make([]message.ForwardNode, len(out.Forward))
+			for i, node := range out.Forward {
+				node.Content = mapText(node.Content, replacer.Replace)
+				nodes[i] = node
+			}
+			out.Forward = nodes
+		}
+		return next(ctx, out)
+	})
+}
+
+// SignatureMiddleware 在消息末尾追加签名，合并转发消息不追加
+func SignatureMiddleware(signature string) middleware.OutboundMiddleware {
+	return middleware.OutboundFunc("消息签名", func(ctx context.Context, out *middleware.Outgoing, next middleware.SendFunc) (string, error) {
+		if out.IsForward() {
+			return next(ctx, out)
+		}
+		segs := append(out.Message.Segments(), message.Text(signature)...)
+		out.Message = message.New(segs...)
+		return next(ctx, out)
+	})
+}
+
+// SplitMiddleware 将超过 maxLen 个字符的纯文本消息拆分为多条发送，每条间隔 interval，返回最后一条的消息ID
+//
+// 含图片等非文本消息段的消息与合并转发消息不拆分。
+func SplitMiddleware(maxLen int, interval time.Duration) middleware.OutboundMiddleware {
+	return middleware.OutboundFunc("长消息拆分", func(ctx context.Context, out *middleware.Outgoing, next middleware.SendFunc) (string, error) {
+		if maxLen <= 0 || out.IsForward() || len(out.Message.GetSegmentsByType("text")) != len(out.Message.Segments()) {
+			return next(ctx, out)
+		}
+
+		parts := splitText(out.Message.PlainText(), maxLen)
+		if len(parts) <= 1 {
+			return next(ctx, out)
+		}
+
+		var id string
+		for i, part := range parts {
+			if i > 0 {
+				select {
+				case <-time.After(interval):
+				case <-ctx.Done():
+					return id, ctx.Err()
+				}
+			}
+
+			piece := *out
+			piece.Message = message.Text(part)
+			var err error
+			if id, err = next(ctx, &piece); err != nil {
+				return id, err
+			}
+		}
+		return id, nil
+	})
+}
+
+// 替换消息中所有文本段的内容
+func mapText(msg message.Message, fn func(string) string) message.Message {
+	segs := msg.Segments()
+	for i, seg := range segs {
+		if seg.IsType("text") {
+			segs[i] = message.NewSegment("text", map[string]any{"text": fn(seg.String())})
+		}
+	}
+	return message.New(segs...)
+}
+
+// 按字符数拆分文本，优先在换行处断开
+func splitText(text string, maxLen int) []string {
+	var parts []string
+	runes := []rune(text)
+	for len(runes) > maxLen {
+		cut := maxLen
+		for i := maxLen; i > maxLen/2; i-- {
+			if runes[i-1] == '\n' {
+				cut = i
+				break
+			}
+		}
+		parts = append(parts, strings.TrimRight(string(runes[:cut]), "\n"))
+		runes = runes[cut:]
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+	return parts
+}
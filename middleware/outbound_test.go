@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/message"
+	"yora/pkg/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboundMiddlewares(t *testing.T) {
+	var sent []string
+	final := func(ctx context.Context, out *middleware.Outgoing) (string, error) {
+		sent = append(sent, out.Message.PlainText())
+		return "", nil
+	}
+
+	send := middleware.ChainOutbound([]middleware.OutboundMiddleware{
+		CensorMiddleware([]string{"坏词"}, "**"),
+		SignatureMiddleware("\n—— yora"),
+		SplitMiddleware(8, 0),
+	}, final)
+
+	_, err := send(context.Background(), &middleware.Outgoing{Message: message.Text("这是坏词")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"这是**", "—— yora"}, sent)
+}
+
+func TestOutboundMiddlewaresForward(t *testing.T) {
+	var sent []message.ForwardNode
+	final := func(ctx context.Context, out *middleware.Outgoing) (string, error) {
+		sent = out.Forward
+		return "", nil
+	}
+
+	send := middleware.ChainOutbound([]middleware.OutboundMiddleware{
+		CensorMiddleware([]string{"坏词"}, "**"),
+		SignatureMiddleware("\n—— yora"),
+		SplitMiddleware(2, 0),
+	}, final)
+
+	nodes := []message.ForwardNode{message.NewForwardNode("10000", "bot", message.Text("这是坏词"))}
+	_, err := send(context.Background(), &middleware.Outgoing{Message: message.New(), Forward: nodes})
+	assert.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "这是**", sent[0].Content.PlainText())
+	assert.Equal(t, "这是坏词", nodes[0].Content.PlainText())
+}
+
+func TestSplitText(t *testing.T) {
+	assert.Equal(t, []string{"abc"}, splitText("abc", 5))
+	assert.Equal(t, []string{"abcde", "fg"}, splitText("abcdefg", 5))
+	assert.Equal(t, []string{"abcd", "efghi"}, splitText("abcd\nefghi", 5))
+}
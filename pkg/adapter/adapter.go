@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"context"
 	"net/http"
 	"yora/pkg/event"
 	"yora/pkg/message"
@@ -23,8 +24,8 @@ type Adapter interface {
 	// 获取协议能力集
 	GetCapabilities() Capabilities
 
-	// 调用协议API
-	CallAPI(action string, params any) (any, error)
+	// 调用协议API，ctx 中的 self_id 决定使用的账号
+	CallAPI(ctx context.Context, action string, params any) (any, error)
 
 	// // 处理HTTP请求
 	// HandleHTTP(w http.ResponseWriter, r *http.Request) error
@@ -32,8 +33,41 @@ type Adapter interface {
 	// 处理WebSocket请求
 	HandleWebSocket(w http.ResponseWriter, r *http.Request, f func(message []byte)) error
 
-	// 发送消息
-	Send(userId string, groupId string, message message.Message) (any, error)
+	// 发送消息并返回消息ID，ctx 中的 self_id 决定使用的账号
+	Send(ctx context.Context, userId string, groupId string, message message.Message) (string, error)
+}
+
+// Connector 可主动连接协议端的适配器（如正向 WebSocket），由 Bot 启动时调用
+type Connector interface {
+	Connect(ctx context.Context, f func(message []byte)) error
+}
+
+// HTTPHandler 支持 HTTP 事件上报的适配器
+type HTTPHandler interface {
+	HandleHTTP(w http.ResponseWriter, r *http.Request, f func(message []byte)) error
+}
+
+// MessageConverter 可在标准消息与协议消息之间转换的适配器
+type MessageConverter interface {
+	// ToNative 将标准消息转换为协议消息
+	ToNative(msg message.Message) message.Message
+
+	// ToStandard 将协议消息转换为标准消息
+	ToStandard(msg message.Message) message.Message
+}
+
+// ToStandard 使用适配器将消息转换为标准消息，适配器不支持转换时原样返回
+func ToStandard(a Adapter, msg message.Message) message.Message {
+	if c, ok := a.(MessageConverter); ok && msg != nil {
+		return c.ToStandard(msg)
+	}
+	return msg
+}
+
+// ForwardSender 支持发送合并转发消息的适配器
+type ForwardSender interface {
+	// SendForward 发送合并转发消息并返回消息ID，ctx 中的 self_id 决定使用的账号
+	SendForward(ctx context.Context, userId string, groupId string, nodes []message.ForwardNode) (string, error)
 }
 
 type Registry interface {
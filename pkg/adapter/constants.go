@@ -1,5 +1,7 @@
 package adapter
 
+import "yora/pkg/message"
+
 // Protocol 协议类型
 type Protocol string
 
@@ -12,22 +14,22 @@ const (
 	ProtocolFeishu   Protocol = "feishu"
 )
 
-// 标准消息段类型常量
+// 标准消息段类型常量，与 message.Type* 一致
 const (
-	SegmentTypeText     = "text"
-	SegmentTypeImage    = "image"
-	SegmentTypeAudio    = "audio"
-	SegmentTypeVideo    = "video"
-	SegmentTypeFile     = "file"
-	SegmentTypeAt       = "at"
-	SegmentTypeEmoji    = "emoji"
-	SegmentTypeReply    = "reply"
-	SegmentTypeForward  = "forward"
-	SegmentTypeLocation = "location"
-	SegmentTypeContact  = "contact"
-	SegmentTypeLink     = "link"
-	SegmentTypeCode     = "code"
-	SegmentTypeQuote    = "quote"
+	SegmentTypeText     = message.TypeText
+	SegmentTypeImage    = message.TypeImage
+	SegmentTypeAudio    = message.TypeAudio
+	SegmentTypeVideo    = message.TypeVideo
+	SegmentTypeFile     = message.TypeFile
+	SegmentTypeAt       = message.TypeAt
+	SegmentTypeEmoji    = message.TypeEmoji
+	SegmentTypeReply    = message.TypeReply
+	SegmentTypeForward  = message.TypeForward
+	SegmentTypeLocation = message.TypeLocation
+	SegmentTypeContact  = message.TypeContact
+	SegmentTypeLink     = message.TypeLink
+	SegmentTypeCode     = message.TypeCode
+	SegmentTypeQuote    = message.TypeQuote
 )
 
 // 标准事件类型常量
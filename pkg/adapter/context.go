@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"context"
+	"yora/pkg/middleware"
+)
+
+type contextKey string
+
+const (
+	adapterKey  contextKey = "adapter"
+	protocolKey contextKey = "protocol"
+	selfIDKey   contextKey = "self_id"
+	outboundKey contextKey = "outbound"
+)
+
+// 将事件来源适配器写入上下文
+func WithAdapter(ctx context.Context, a Adapter) context.Context {
+	ctx = context.WithValue(ctx, adapterKey, a)
+	return context.WithValue(ctx, protocolKey, a.Protocol())
+}
+
+// 获取事件来源适配器
+func FromContext(ctx context.Context) (Adapter, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	a, ok := ctx.Value(adapterKey).(Adapter)
+	return a, ok
+}
+
+// 获取事件来源协议
+func ProtocolFromContext(ctx context.Context) (Protocol, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	p, ok := ctx.Value(protocolKey).(Protocol)
+	return p, ok
+}
+
+// 指定发送消息、调用API使用的账号
+func WithSelfID(ctx context.Context, selfID string) context.Context {
+	return context.WithValue(ctx, selfIDKey, selfID)
+}
+
+// 获取上下文指定的账号，未指定时返回空字符串
+func SelfIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(selfIDKey).(string)
+	return id
+}
+
+// 指定发送消息时经过的出站中间件
+func WithOutbound(ctx context.Context, middlewares []middleware.OutboundMiddleware) context.Context {
+	return context.WithValue(ctx, outboundKey, middlewares)
+}
+
+// 获取上下文中的出站中间件
+func OutboundFromContext(ctx context.Context) []middleware.OutboundMiddleware {
+	if ctx == nil {
+		return nil
+	}
+	ms, _ := ctx.Value(outboundKey).([]middleware.OutboundMiddleware)
+	return ms
+}
@@ -0,0 +1,24 @@
+package adapter
+
+import "time"
+
+// Health 账号连接的健康状态
+type Health struct {
+	SelfID        string        `json:"self_id"`
+	Connected     bool          `json:"connected"`      // 连接是否建立
+	Online        bool          `json:"online"`         // 协议端报告的账号在线状态，未收到心跳时为 true
+	Good          bool          `json:"good"`           // 协议端报告的运行状态，未收到心跳时为 true
+	Enabled       bool          `json:"enabled"`        // 协议端是否启用（生命周期事件）
+	LastHeartbeat time.Time     `json:"last_heartbeat"` // 最近一次心跳时间，零值表示未收到心跳
+	Interval      time.Duration `json:"interval"`       // 协议端声明的心跳间隔
+	Healthy       bool          `json:"healthy"`        // 已连接、已启用、心跳未超时，且在线、状态良好
+}
+
+// HealthReporter 可报告账号健康状态的适配器
+type HealthReporter interface {
+	// Health 所有账号的健康状态
+	Health() []Health
+
+	// OnConnectionLost 设置心跳超时（连接失活）时的回调
+	OnConnectionLost(f func(h Health))
+}
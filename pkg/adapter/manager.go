@@ -10,7 +10,7 @@ import (
 type AdapterRegistry struct {
 	mu          sync.RWMutex
 	adapters    map[Protocol]Adapter
-	middlewares []middleware.Middleware
+	middlewares []middleware.OutboundMiddleware // 出站中间件
 }
 
 // Adapters implements Registry.
@@ -18,15 +18,20 @@ func (r *AdapterRegistry) Adapters() map[Protocol]Adapter {
 	return r.adapters
 }
 
-// Middlewares implements Registry.
-// func (r *AdapterRegistry) Middlewares() []middleware.Middleware {
-// 	return r.middlewares
-// }
+// 出站中间件副本（按注册顺序）
+func (r *AdapterRegistry) Middlewares() []middleware.OutboundMiddleware {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	middlewares := make([]middleware.OutboundMiddleware, len(r.middlewares))
+	copy(middlewares, r.middlewares)
+	return middlewares
+}
 
 func NewAdapterRegistry() *AdapterRegistry {
 	return &AdapterRegistry{
 		adapters:    make(map[Protocol]Adapter),
-		middlewares: make([]middleware.Middleware, 0),
+		middlewares: make([]middleware.OutboundMiddleware, 0),
 	}
 }
 
@@ -56,6 +61,9 @@ func (r *AdapterRegistry) Unregister(protocol Protocol) error {
 	return nil
 }
 
-func (a *AdapterRegistry) RegisterMiddleware(middleware middleware.Middleware) {
-	a.middlewares = append(a.middlewares, middleware)
+// 注册出站中间件，所有适配器发送的消息依次经过这些中间件
+func (r *AdapterRegistry) RegisterMiddleware(middlewares ...middleware.OutboundMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, middlewares...)
 }
@@ -0,0 +1,66 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yora/pkg/handler"
+	"yora/pkg/hook"
+	"yora/pkg/message"
+	"yora/pkg/middleware"
+)
+
+// ErrForwardUnsupported 适配器不支持合并转发
+var ErrForwardUnsupported = errors.New("适配器不支持合并转发消息")
+
+// 通过适配器发送消息
+//
+// 消息先依次经过上下文中的出站中间件（可改写、延迟或丢弃），
+// 到达适配器前触发 MessageOnSend Hook，Hook 返回错误时取消发送。
+// Bot.Send 与回复器均经由此函数发送。
+func Send(ctx context.Context, a Adapter, userID, groupID string, msg message.Message) (string, error) {
+	return send(ctx, a, &middleware.Outgoing{UserID: userID, GroupID: groupID, Message: msg})
+}
+
+// SendForward 通过适配器发送合并转发消息，节点内容为标准消息
+//
+// 与 Send 一样经过出站中间件与 MessageOnSend Hook（Hook 中的消息为转发节点）。
+// 适配器未实现 ForwardSender 时返回 ErrForwardUnsupported，调用方可改为逐条或合并为一条发送。
+func SendForward(ctx context.Context, a Adapter, userID, groupID string, nodes []message.ForwardNode) (string, error) {
+	if _, ok := a.(ForwardSender); !ok {
+		return "", ErrForwardUnsupported
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("合并转发消息不能为空")
+	}
+	return send(ctx, a, &middleware.Outgoing{UserID: userID, GroupID: groupID, Message: message.New(), Forward: nodes})
+}
+
+func send(ctx context.Context, a Adapter, out *middleware.Outgoing) (string, error) {
+	if scope, ok := handler.ScopeFromContext(ctx); ok {
+		out.Event = scope.Event()
+	}
+
+	send := middleware.ChainOutbound(OutboundFromContext(ctx), func(ctx context.Context, out *middleware.Outgoing) (string, error) {
+		var content any = out.Message
+		if out.IsForward() {
+			content = out.Forward
+		} else if out.Message == nil || out.Message.IsEmpty() {
+			return "", fmt.Errorf("消息不能为空")
+		}
+
+		hc := hook.NewMessageHookContext(ctx, hook.MessageOnSend, content)
+		hc.UserID, hc.GroupID, hc.Event = out.UserID, out.GroupID, out.Event
+		hc.Set("protocol", string(a.Protocol()))
+		hc.Set("self_id", SelfIDFromContext(ctx))
+
+		if err := hook.TriggerGlobalHook(hook.MessageOnSend, hc.HookContext); err != nil {
+			return "", fmt.Errorf("消息发送被取消: %w", err)
+		}
+		if out.IsForward() {
+			return a.(ForwardSender).SendForward(ctx, out.UserID, out.GroupID, out.Forward)
+		}
+		return a.Send(ctx, out.UserID, out.GroupID, out.Message)
+	})
+	return send(ctx, out)
+}
@@ -16,18 +16,24 @@ type Bot interface {
 	// 所属平台名，如 "onebot"
 	Platform() string
 
-	// 发送消息（通用格式）
-	Send(userId string, groupId string, message message.Message) (any, error)
+	// 发送消息（通用格式），返回消息ID
+	Send(userId string, groupId string, message message.Message) (string, error)
 
 	// 调用 API（通用格式）
 	CallAPI(params ...any) (any, error)
 
+	// 返回使用指定账号发送消息、调用 API 的 Bot
+	Account(selfID string) Bot
+
 	// 注册适配器
 	RegisterAdapters(adapters ...adapter.Adapter) error
 
 	// 注册中间件
 	RegisterMiddlewares(middlewares ...middleware.Middleware) error
 
+	// 注册出站中间件（发送消息前执行）
+	RegisterOutboundMiddlewares(middlewares ...middleware.OutboundMiddleware) error
+
 	// 注册插件
 	RegisterPlugins(plugins ...plugin.Plugin) error
 
@@ -40,6 +46,9 @@ type Bot interface {
 	// 检查机器人是否正在运行
 	IsRunning() bool
 
+	// 各适配器账号的连接健康状态
+	Health() []adapter.Health
+
 	// 关闭Bot
 	ShutDown() error
 
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"yora/pkg/adapter"
+	"yora/pkg/message"
+)
+
+var _ Bot = (*boundBot)(nil)
+
+// 绑定上下文的 Bot：发送消息与调用API使用上下文中的适配器和账号
+type boundBot struct {
+	*botImpl
+	ctx context.Context
+}
+
+func (b *botImpl) bind(ctx context.Context) Bot {
+	return &boundBot{botImpl: b, ctx: ctx}
+}
+
+// 当前账号ID
+func (b *boundBot) SelfID() string {
+	if id := adapter.SelfIDFromContext(b.ctx); id != "" {
+		return id
+	}
+	return b.botImpl.SelfID()
+}
+
+func (b *boundBot) Send(userId string, groupId string, msg message.Message) (string, error) {
+	return b.send(b.ctx, userId, groupId, msg)
+}
+
+func (b *boundBot) CallAPI(params ...any) (any, error) {
+	return b.callAPI(b.ctx, params...)
+}
+
+func (b *boundBot) Account(selfID string) Bot {
+	return b.bind(adapter.WithSelfID(b.ctx, selfID))
+}
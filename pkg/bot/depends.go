@@ -13,9 +13,12 @@ func Config() provider.Provider {
 	})
 }
 
-// 获取Bot
+// 获取Bot，发送消息与调用API默认使用触发事件的适配器和账号
 func BotProvider() provider.Provider {
-	return provider.StaticProvider(func(ctx context.Context, e event.Event) any {
+	return provider.DynamicProvider(func(ctx context.Context, e event.Event) any {
+		if b, ok := GetBot().(*botImpl); ok {
+			return b.bind(ctx)
+		}
 		return GetBot()
 	})
 }
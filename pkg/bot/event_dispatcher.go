@@ -2,32 +2,47 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"net/http"
 	"sync"
 	"time"
 	"yora/pkg/adapter"
+	"yora/pkg/conf"
 	"yora/pkg/event"
 	"yora/pkg/handler"
+	"yora/pkg/hook"
 	"yora/pkg/log"
 	"yora/pkg/middleware"
 	"yora/pkg/plugin"
+	"yora/pkg/policy"
 	"yora/pkg/provider"
+	"yora/pkg/replier"
 
 	"github.com/rs/zerolog"
 )
 
+// ErrMatcherVetoed 匹配器被 MatcherBeforeHandle Hook 取消，处理器未执行
+var ErrMatcherVetoed = errors.New("处理器被 Hook 取消")
+
 // 事件分发器
 type EventDispatcher struct {
 	middlewares []middleware.Middleware
+	outbound    func() []middleware.OutboundMiddleware // 出站中间件（来自适配器注册中心）
 	logger      zerolog.Logger
 	mr          *plugin.MatcherRegistry
+	pr          *plugin.PluginRegistry // 触发匹配器Hook
+	switches    *policy.Policy         // 插件开关策略
 	mu          sync.RWMutex
 	stats       EventStats
 
-	// 事件队列
+	// 事件队列（按会话分片，每个 worker 一个队列）
+	queues     []chan EventWrapper
+	policy     string
 	shutdownCh chan struct{}
-	eventQueue chan EventWrapper
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
 }
 
 // 事件包装器
@@ -48,18 +63,39 @@ type EventStats struct {
 	mu            sync.RWMutex
 }
 
-func NewEventDispatcher() *EventDispatcher {
+func NewEventDispatcher(cfg *conf.BotConfig) *EventDispatcher {
+	workers := cfg.WorkerCount
+	if workers <= 0 {
+		workers = 1
+	}
+	size := cfg.QueueSize
+	if size <= 0 {
+		size = 100
+	}
 
 	ed := &EventDispatcher{
-		eventQueue:  make(chan EventWrapper, 100),
+		queues:      make([]chan EventWrapper, workers),
+		policy:      cfg.OverflowPolicy,
 		mr:          plugin.GetMatcherRegistry(),
+		pr:          plugin.GetPluginRegistry(),
+		switches:    policy.GetPolicy(),
 		logger:      log.NewMatcher("event_dispatcher"),
 		middlewares: make([]middleware.Middleware, 0),
 		stats:       EventStats{},
 		shutdownCh:  make(chan struct{}),
 	}
 
-	go ed.startEventLoop()
+	for i := range ed.queues {
+		ed.queues[i] = make(chan EventWrapper, size)
+		ed.wg.Add(1)
+		go ed.startEventLoop(i)
+	}
+
+	ed.logger.Info().
+		Int("worker数量", workers).
+		Int("队列容量", size).
+		Str("溢出策略", ed.policy).
+		Msg("事件分发器已创建")
 
 	return ed
 
@@ -80,16 +116,38 @@ func (ed *EventDispatcher) RegisterMiddlewares(middlewares ...middleware.Middlew
 
 // 为每个适配器处理连接
 func (ed *EventDispatcher) HandleAdapterConnection(w http.ResponseWriter, r *http.Request, a adapter.Adapter, p adapter.Protocol) {
-	a.HandleWebSocket(w, r, func(message []byte) {
-		go func() {
-			if err := ed.processRawMessage(message, a, p); err != nil {
-				ed.logger.Error().
-					Err(err).
-					Str("协议", string(p)).
-					Msg("处理 WebSocket 消息失败")
-			}
-		}()
-	})
+	// 同步入队，保证同一连接上的事件顺序
+	a.HandleWebSocket(w, r, ed.rawHandler(a, p))
+}
+
+// 处理适配器的 HTTP 事件上报，适配器需实现 adapter.HTTPHandler
+func (ed *EventDispatcher) HandleAdapterHTTP(w http.ResponseWriter, r *http.Request, a adapter.Adapter, p adapter.Protocol) error {
+	h, ok := a.(adapter.HTTPHandler)
+	if !ok {
+		return fmt.Errorf("适配器不支持 HTTP 上报")
+	}
+	return h.HandleHTTP(w, r, ed.rawHandler(a, p))
+}
+
+// 适配器主动连接协议端，未实现 adapter.Connector 的适配器直接忽略
+func (ed *EventDispatcher) ConnectAdapter(ctx context.Context, a adapter.Adapter, p adapter.Protocol) error {
+	c, ok := a.(adapter.Connector)
+	if !ok {
+		return nil
+	}
+	return c.Connect(ctx, ed.rawHandler(a, p))
+}
+
+// 原始消息处理函数
+func (ed *EventDispatcher) rawHandler(a adapter.Adapter, p adapter.Protocol) func(message []byte) {
+	return func(message []byte) {
+		if err := ed.processRawMessage(message, a, p); err != nil {
+			ed.logger.Error().
+				Err(err).
+				Str("协议", string(p)).
+				Msg("处理原始消息失败")
+		}
+	}
 }
 
 // 处理原始消息
@@ -113,11 +171,6 @@ func (ed *EventDispatcher) processRawMessage(raw []byte, a adapter.Adapter, prot
 		return fmt.Errorf("事件解析失败: %w", err)
 	}
 
-	// 忽略元事件
-	if evt.Type() == "meta_event" {
-		return nil
-	}
-
 	// 验证事件
 	if err := a.ValidateEvent(evt); err != nil {
 		ed.logger.Error().
@@ -128,6 +181,11 @@ func (ed *EventDispatcher) processRawMessage(raw []byte, a adapter.Adapter, prot
 		return fmt.Errorf("事件验证失败: %w", err)
 	}
 
+	// 心跳已由适配器记录，不再分发给中间件与匹配器
+	if isHeartbeat(evt) {
+		return nil
+	}
+
 	// 包装事件并放入队列
 	wrapper := EventWrapper{
 		Event:    evt,
@@ -137,22 +195,107 @@ func (ed *EventDispatcher) processRawMessage(raw []byte, a adapter.Adapter, prot
 		RecvTime: time.Now(),
 	}
 
-	select {
-	case ed.eventQueue <- wrapper:
-		ed.logger.Debug().
-			Str("协议", string(protocol)).
-			Str("事件类型", fmt.Sprintf("%T", evt)).
-			Msg("事件已加入处理队列")
-	case <-time.After(time.Second): // 避免无限阻塞
+	if ed.dispatchTemporary(wrapper) {
+		return nil
+	}
+
+	if err := ed.enqueue(wrapper); err != nil {
 		ed.logger.Warn().
+			Err(err).
 			Str("事件类型", fmt.Sprintf("%T", evt)).
-			Msg("事件处理队列已满，丢弃事件")
-		return fmt.Errorf("事件队列已满")
+			Msg("事件入队失败，丢弃事件")
+		return err
 	}
 
+	ed.logger.Debug().
+		Str("协议", string(protocol)).
+		Str("事件类型", fmt.Sprintf("%T", evt)).
+		Msg("事件已加入处理队列")
+
 	return nil
 }
 
+// 是否为心跳元事件
+func isHeartbeat(e event.Event) bool {
+	meta, ok := e.(interface{ MetaEventType() string })
+	return ok && meta.MetaEventType() == "heartbeat"
+}
+
+// 将事件放入所属会话的 worker 队列，队列满时按溢出策略处理
+func (ed *EventDispatcher) enqueue(wrapper EventWrapper) error {
+	queue := ed.queues[ed.shard(wrapper.Event)]
+
+	switch ed.policy {
+	case conf.OverflowDropNewest:
+		select {
+		case queue <- wrapper:
+			return nil
+		default:
+			return fmt.Errorf("事件队列已满")
+		}
+
+	case conf.OverflowDropOldest:
+		for {
+			select {
+			case queue <- wrapper:
+				return nil
+			case <-ed.shutdownCh:
+				return fmt.Errorf("事件分发器已关闭")
+			default:
+			}
+
+			// 腾出空位：丢弃最旧的事件
+			select {
+			case old := <-queue:
+				ed.logger.Warn().
+					Str("事件类型", fmt.Sprintf("%T", old.Event)).
+					Msg("事件处理队列已满，丢弃最旧事件")
+			default:
+			}
+		}
+
+	default:
+		select {
+		case queue <- wrapper:
+			return nil
+		case <-ed.shutdownCh:
+			return fmt.Errorf("事件分发器已关闭")
+		}
+	}
+}
+
+// 根据会话计算事件所属的 worker，同一会话的事件总是由同一个 worker 顺序处理
+func (ed *EventDispatcher) shard(e event.Event) int {
+	if len(ed.queues) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(SessionKey(e)))
+	return int(h.Sum32() % uint32(len(ed.queues)))
+}
+
+// 会话标识：群聊为群ID，私聊为用户ID
+func SessionKey(e event.Event) string {
+	switch v := e.(type) {
+	case event.MessageEvent:
+		if v.IsGroup() {
+			return e.SelfID() + ":group:" + v.ChatID()
+		}
+		return e.SelfID() + ":private:" + v.UserID()
+	case event.NoticeEvent:
+		if v.ChatID() != "" && v.ChatID() != "0" {
+			return e.SelfID() + ":group:" + v.ChatID()
+		}
+		return e.SelfID() + ":private:" + v.UserID()
+	case event.RequestEvent:
+		if v.ChatID() != "" && v.ChatID() != "0" {
+			return e.SelfID() + ":group:" + v.ChatID()
+		}
+		return e.SelfID() + ":private:" + v.UserID()
+	}
+	return e.SelfID() + ":" + e.Type()
+}
+
 // 处理事件包装器
 func (ed *EventDispatcher) handleEventWrapper(wrapper EventWrapper) {
 	startTime := time.Now()
@@ -162,8 +305,42 @@ func (ed *EventDispatcher) handleEventWrapper(wrapper EventWrapper) {
 		Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
 		Msg("开始处理事件")
 
-	// 设置动态依赖注入
-	provs := []provider.Provider{
+	// 处理器调用 handler.Detach（如会话等待回复）后，worker 不再等待，继续处理后续事件
+	done := make(chan struct{})
+	detached := make(chan struct{})
+	var detachOnce sync.Once
+	ctx := handler.WithDetach(ed.newEventContext(wrapper), func() {
+		detachOnce.Do(func() { close(detached) })
+	})
+
+	go func() {
+		defer close(done)
+		if err := ed.DispatchEvent(ctx, wrapper.Event); err != nil {
+			ed.logger.Error().
+				Err(err).
+				Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
+				Msg("事件分发失败")
+		}
+
+		ed.logger.Debug().
+			Str("协议", string(wrapper.Protocol)).
+			Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
+			Dur("处理时长", time.Since(startTime)).
+			Msg("事件处理完成")
+	}()
+
+	select {
+	case <-done:
+	case <-detached:
+		ed.logger.Debug().
+			Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
+			Msg("事件处理器进入等待，worker 继续处理后续事件")
+	}
+}
+
+// 为事件创建上下文：来源适配器 + 独立的依赖注入作用域
+func (ed *EventDispatcher) newEventContext(wrapper EventWrapper) context.Context {
+	scope := handler.NewScope(wrapper.Event,
 		provider.Ctx(),
 		provider.Event(),
 		provider.MessageEvent(),
@@ -171,92 +348,258 @@ func (ed *EventDispatcher) handleEventWrapper(wrapper EventWrapper) {
 		provider.RequestEvent(),
 		provider.NoticeEvent(),
 		BotProvider(),
+		replier.Provider(),
+	)
+
+	ctx := adapter.WithAdapter(context.Background(), wrapper.Adapter)
+	ctx = adapter.WithSelfID(ctx, wrapper.Event.SelfID())
+	if ed.outbound != nil {
+		ctx = adapter.WithOutbound(ctx, ed.outbound())
 	}
+	return handler.WithScope(ctx, scope)
+}
 
-	handler.GetHandlerRegistry().ResetCache()
-	handler.GetHandlerRegistry().RegisterProviders(provs...)
+// 优先交给等待中的会话（临时匹配器），被消费的事件不再进入队列
+//
+// 回复事件在入队前交给会话，不必排在同一会话的后续事件之后；
+// 回复同样经过入站中间件，被中间件拦截（如频率限制）的回复不会交给会话。
+func (ed *EventDispatcher) dispatchTemporary(wrapper EventWrapper) bool {
+	ctx := ed.newEventContext(wrapper)
 
-	// 通过分发器处理事件
-	ctx := context.WithValue(context.Background(), "adapter", wrapper.Adapter)
-	ctx = context.WithValue(ctx, "protocol", wrapper.Protocol)
+	matched := ed.mr.MatchTemporary(ctx, wrapper.Event)
+	if len(matched) == 0 {
+		return false
+	}
 
-	if err := ed.DispatchEvent(ctx, wrapper.Event); err != nil {
+	deliver := ed.buildMiddlewareChain(func(ctx context.Context, e event.Event) error {
+		var errs []error
+		for _, m := range matched {
+			errs = append(errs, m.Call(ctx, e))
+		}
+		return errors.Join(errs...)
+	})
+	if err := deliver(ctx, wrapper.Event); err != nil {
 		ed.logger.Error().
 			Err(err).
 			Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
-			Msg("事件分发失败")
+			Msg("会话处理器执行失败")
 	}
 
-	duration := time.Since(startTime)
 	ed.logger.Debug().
-		Str("协议", string(wrapper.Protocol)).
+		Int("匹配数量", len(matched)).
 		Str("事件类型", fmt.Sprintf("%T", wrapper.Event)).
-		Dur("处理时长", duration).
-		Msg("事件处理完成")
+		Msg("事件已由会话消费")
+	return true
 }
 
-// 启动事件处理循环
-func (ed *EventDispatcher) startEventLoop() {
-	ed.logger.Info().Msg("启动事件处理器")
+// 启动事件处理循环（单个 worker）
+func (ed *EventDispatcher) startEventLoop(index int) {
+	defer ed.wg.Done()
+	ed.logger.Debug().Int("worker", index).Msg("启动事件处理器")
 
+	queue := ed.queues[index]
 	for {
 		select {
-		case wrapper := <-ed.eventQueue:
+		case wrapper := <-queue:
 			ed.handleEventWrapper(wrapper)
 		case <-ed.shutdownCh:
-			ed.logger.Debug().Msg("事件处理worker关闭")
+			ed.logger.Debug().Int("worker", index).Msg("事件处理worker关闭")
 			return
 		}
 	}
 }
 
-// 构建中间件链
-func (ed *EventDispatcher) buildMiddlewareChain() func(ctx context.Context, event event.Event) error {
+// 停止所有 worker，等待正在处理的事件完成
+func (ed *EventDispatcher) Stop() {
+	ed.stopOnce.Do(func() {
+		close(ed.shutdownCh)
+	})
+	ed.wg.Wait()
+}
+
+// 构建以 final 结尾的中间件链
+func (ed *EventDispatcher) buildMiddlewareChain(final func(ctx context.Context, event event.Event) error) func(ctx context.Context, event event.Event) error {
 	ed.mu.RLock()
 	middlewares := make([]middleware.Middleware, len(ed.middlewares))
 	copy(middlewares, ed.middlewares)
 	ed.mu.RUnlock()
 
-	return middleware.Chain(middlewares, func(ctx context.Context, e event.Event) error {
-		go ed.dispatchToMatchers(ctx, e)
-		return nil
-	})
+	// 在 worker 内同步执行，保证同一会话内的处理顺序
+	return middleware.Chain(middlewares, final)
 }
 
 // 分发到匹配器
+//
+// 按优先级从高到低逐层匹配（先经插件开关策略过滤，再要求规则与权限均满足），同一层命中的匹配器并发执行；
+// 某层中有阻断匹配器命中并执行后，不再继续匹配更低优先级的层。
 func (ed *EventDispatcher) dispatchToMatchers(ctx context.Context, e event.Event) error {
-	matched := ed.mr.MatchedMatchers(ctx, e)
+	var (
+		errs         []error
+		total        int
+		successCount int
+	)
+
+	if err := ed.triggerMessageHook(ctx, hook.MessageOnReceive, e); err != nil {
+		ed.logger.Debug().Err(err).Msg("消息被 Hook 拦截，不再分发")
+		return nil
+	}
 
-	ed.logger.Debug().
-		Int("匹配数量", len(matched)).
-		Str("事件类型", fmt.Sprintf("%T", e)).
-		Msg("找到匹配的处理器")
+	for _, tier := range ed.mr.PriorityTiers() {
+		matched := plugin.MatchTier(ctx, e, ed.switches.Filter(e, tier))
+		if len(matched) == 0 {
+			continue
+		}
 
-	var lastErr error
-	successCount := 0
+		ed.logger.Debug().
+			Int("优先级", matched[0].Priority).
+			Int("匹配数量", len(matched)).
+			Str("事件类型", fmt.Sprintf("%T", e)).
+			Msg("找到匹配的处理器")
 
-	for _, matcher := range matched {
-		if err := matcher.Call(ctx, e); err != nil {
-			ed.logger.Error().
-				Err(err).
-				Str("匹配器", fmt.Sprintf("%T", matcher)).
-				Msg("处理器执行失败")
-			lastErr = err
-		} else {
-			successCount++
+		// 被 Hook 取消的匹配器不计入匹配结果，也不触发阻断
+		blocked := false
+		for i, err := range ed.runTier(ctx, e, matched) {
+			if errors.Is(err, ErrMatcherVetoed) {
+				continue
+			}
+			total++
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				successCount++
+			}
+			blocked = blocked || matched[i].Block
+		}
+
+		if blocked {
 			ed.logger.Debug().
-				Str("匹配器", fmt.Sprintf("%T", matcher)).
-				Msg("处理器执行成功")
+				Int("优先级", matched[0].Priority).
+				Msg("事件被阻断，停止匹配低优先级处理器")
+			break
 		}
 	}
 
-	ed.logger.Info().
-		Int("匹配总数", len(matched)).
+	hookType := hook.MessageOnMatch
+	if total == 0 {
+		hookType = hook.MessageOnNoMatch
+	}
+	if err := ed.triggerMessageHook(ctx, hookType, e); err != nil {
+		ed.logger.Warn().Err(err).Str("Hook", string(hookType)).Msg("消息Hook执行失败")
+	}
+
+	// 元事件（如生命周期事件）较频繁，只在调试时记录
+	logEvent := ed.logger.Info
+	if _, ok := e.(event.MetaEvent); ok {
+		logEvent = ed.logger.Debug
+	}
+	logEvent().
+		Int("匹配总数", total).
 		Int("成功数量", successCount).
-		Int("失败数量", len(matched)-successCount).
+		Int("失败数量", total-successCount).
 		Msg("事件分发完成")
 
-	return lastErr
+	return errors.Join(errs...)
+}
+
+// 并发执行同一优先级层命中的匹配器，每个匹配器使用独立的子作用域，返回与 matched 一一对应的执行结果
+func (ed *EventDispatcher) runTier(ctx context.Context, e event.Event, matched []*plugin.Matcher) []error {
+	scope, hasScope := handler.ScopeFromContext(ctx)
+	matcherCtx := func() context.Context {
+		if hasScope {
+			return handler.WithScope(ctx, scope.Fork())
+		}
+		return ctx
+	}
+
+	results := make([]error, len(matched))
+	if len(matched) == 1 {
+		results[0] = ed.callMatcher(matcherCtx(), e, matched[0])
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, m := range matched {
+		mctx := matcherCtx()
+
+		wg.Add(1)
+		go func(i int, m *plugin.Matcher) {
+			defer wg.Done()
+			results[i] = ed.callMatcher(mctx, e, m)
+		}(i, m)
+	}
+	wg.Wait()
+	return results
+}
+
+// 执行单个匹配器，处理器 panic 时转为错误
+//
+// 执行前触发 MatcherBeforeHandle（返回错误时跳过该匹配器并返回 ErrMatcherVetoed），
+// 成功后触发 MatcherAfterHandle，失败时触发 MatcherOnError。
+func (ed *EventDispatcher) callMatcher(ctx context.Context, e event.Event, m *plugin.Matcher) (err error) {
+	name := matcherName(m)
+	if herr := ed.triggerMatcherHook(ctx, hook.MatcherBeforeHandle, e, m, nil); herr != nil {
+		ed.logger.Debug().
+			Err(herr).
+			Str("匹配器", name).
+			Msg("处理器被 Hook 取消")
+		return ErrMatcherVetoed
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("处理器 panic: %v", r)
+		}
+
+		hookType := hook.MatcherAfterHandle
+		if err != nil {
+			hookType = hook.MatcherOnError
+			ed.logger.Error().
+				Err(err).
+				Str("匹配器", name).
+				Msg("处理器执行失败")
+		}
+		if herr := ed.triggerMatcherHook(ctx, hookType, e, m, err); herr != nil {
+			ed.logger.Warn().Err(herr).Str("匹配器", name).Str("Hook", string(hookType)).Msg("匹配器Hook执行失败")
+		}
+	}()
+
+	if err = m.Call(ctx, e); err == nil {
+		ed.logger.Debug().
+			Str("匹配器", name).
+			Msg("处理器执行成功")
+	}
+	return err
+}
+
+// 触发匹配器Hook：全局Hook与匹配器所属插件的Hook
+func (ed *EventDispatcher) triggerMatcherHook(ctx context.Context, hookType hook.HookType, e event.Event, m *plugin.Matcher, handleErr error) error {
+	hc := hook.NewMatcherHookContext(ctx, hookType, m, e)
+	hc.Err = handleErr
+	hc.Set("matcher", matcherName(m))
+	return ed.pr.TriggerHook(hookType, m.Plugin(), hc.HookContext)
+}
+
+// 触发消息Hook（仅消息事件）
+func (ed *EventDispatcher) triggerMessageHook(ctx context.Context, hookType hook.HookType, e event.Event) error {
+	me, ok := e.(event.MessageEvent)
+	if !ok {
+		return nil
+	}
+	hc := hook.NewMessageHookContext(ctx, hookType, me.Message())
+	hc.Event = e
+	hc.UserID = me.UserID()
+	if me.IsGroup() {
+		hc.GroupID = me.ChatID()
+	}
+	return hook.TriggerGlobalHook(hookType, hc.HookContext)
+}
+
+// 匹配器名称（所属插件ID），用于日志
+func matcherName(m *plugin.Matcher) string {
+	if p := m.Plugin(); p != nil && p.PluginInfo() != nil {
+		return p.PluginInfo().ID
+	}
+	return fmt.Sprintf("%p", m)
 }
 
 // 更新统计信息
@@ -290,7 +633,7 @@ func (ed *EventDispatcher) DispatchEvent(ctx context.Context, e event.Event) err
 		Msg("开始分发事件")
 
 	// 构建中间件链
-	handler := ed.buildMiddlewareChain()
+	handler := ed.buildMiddlewareChain(ed.dispatchToMatchers)
 
 	err := handler(ctx, e)
 	if err != nil {
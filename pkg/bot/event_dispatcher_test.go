@@ -0,0 +1,335 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+	"yora/pkg/adapter"
+	"yora/pkg/conf"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/hook"
+	"yora/pkg/log"
+	"yora/pkg/middleware"
+	"yora/pkg/plugin"
+	"yora/pkg/policy"
+	"yora/pkg/provider"
+	"yora/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queueEvent struct {
+	n int
+}
+
+func (e *queueEvent) Type() string    { return "notice" }
+func (e *queueEvent) SubType() string { return "" }
+func (e *queueEvent) Time() time.Time { return time.Time{} }
+func (e *queueEvent) SelfID() string  { return "10000" }
+func (e *queueEvent) Raw() any        { return e }
+
+// 只提供协议名的适配器
+type stubAdapter struct{ adapter.Adapter }
+
+func (stubAdapter) Protocol() adapter.Protocol { return "test" }
+
+// 不启动 worker，仅验证入队策略
+func newQueueOnlyDispatcher(policy string) *EventDispatcher {
+	return &EventDispatcher{
+		queues:     []chan EventWrapper{make(chan EventWrapper, 2)},
+		policy:     policy,
+		logger:     log.NewMatcher("test"),
+		shutdownCh: make(chan struct{}),
+	}
+}
+
+func drain(q chan EventWrapper) []int {
+	var ns []int
+	for len(q) > 0 {
+		ns = append(ns, (<-q).Event.(*queueEvent).n)
+	}
+	return ns
+}
+
+func TestEnqueueOverflowPolicies(t *testing.T) {
+	push := func(ed *EventDispatcher, n int) error {
+		return ed.enqueue(EventWrapper{Event: &queueEvent{n: n}})
+	}
+
+	ed := newQueueOnlyDispatcher(conf.OverflowDropNewest)
+	assert.NoError(t, push(ed, 1))
+	assert.NoError(t, push(ed, 2))
+	assert.Error(t, push(ed, 3))
+	assert.Equal(t, []int{1, 2}, drain(ed.queues[0]))
+
+	ed = newQueueOnlyDispatcher(conf.OverflowDropOldest)
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, push(ed, i))
+	}
+	assert.Equal(t, []int{2, 3}, drain(ed.queues[0]))
+
+	ed = newQueueOnlyDispatcher(conf.OverflowBlock)
+	assert.NoError(t, push(ed, 1))
+	assert.NoError(t, push(ed, 2))
+	close(ed.shutdownCh)
+	assert.Error(t, push(ed, 3))
+}
+
+func TestShardIsStablePerSession(t *testing.T) {
+	ed := &EventDispatcher{queues: make([]chan EventWrapper, 8)}
+	var e event.Event = &queueEvent{}
+	first := ed.shard(e)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, ed.shard(e))
+	}
+}
+
+func TestDispatchRunsSameTierConcurrently(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.mr = plugin.GetMatcherRegistry()
+	ed.pr = plugin.GetPluginRegistry()
+	ed.switches = policy.New()
+
+	evt := &queueEvent{n: 42}
+	only := rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == evt })
+
+	// 两个同优先级的处理器互相等待，只有并发执行才能完成
+	a, b := make(chan struct{}), make(chan struct{})
+	wait := func(self, other chan struct{}) *handler.Handler {
+		return handler.NewHandler(func() error {
+			close(self)
+			select {
+			case <-other:
+				return nil
+			case <-time.After(time.Second):
+				return errors.New("timeout")
+			}
+		})
+	}
+
+	var lowCalled atomic.Bool
+	ms := []*plugin.Matcher{
+		plugin.NewMatcher(only, wait(a, b)).SetPriority(100).SetBlock(true),
+		plugin.NewMatcher(only, wait(b, a)).SetPriority(100),
+		plugin.NewMatcher(only, handler.NewHandler(func() { lowCalled.Store(true) })).SetPriority(1),
+	}
+	ed.mr.RegisterMatchers(ms...)
+	defer func() {
+		for _, m := range ms {
+			ed.mr.UnregisterMatchers(m)
+		}
+	}()
+
+	ctx := handler.WithScope(context.Background(), handler.NewScope(evt))
+	assert.NoError(t, ed.dispatchToMatchers(ctx, evt))
+	assert.False(t, lowCalled.Load(), "阻断后不应执行低优先级处理器")
+}
+
+// 匹配器放入作用域的值
+type tierMarker struct{}
+
+func TestSingleMatcherUsesOwnScope(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.mr = plugin.GetMatcherRegistry()
+	ed.pr = plugin.GetPluginRegistry()
+	ed.switches = policy.New()
+
+	evt := &queueEvent{n: 43}
+	only := rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == evt })
+
+	// 每层只命中一个匹配器，高优先级写入的值不应泄漏给低优先级
+	var leaked atomic.Bool
+	ms := []*plugin.Matcher{
+		plugin.NewMatcher(only, handler.NewHandler(func(ctx context.Context) {
+			scope, _ := handler.ScopeFromContext(ctx)
+			scope.Set(&tierMarker{})
+		})).SetPriority(100),
+		plugin.NewMatcher(only, handler.NewHandler(func(ctx context.Context) {
+			scope, _ := handler.ScopeFromContext(ctx)
+			_, err := scope.Resolve(ctx, reflect.TypeOf(&tierMarker{}))
+			leaked.Store(err == nil)
+		})).SetPriority(1),
+	}
+	ed.mr.RegisterMatchers(ms...)
+	defer func() {
+		for _, m := range ms {
+			ed.mr.UnregisterMatchers(m)
+		}
+	}()
+
+	root := handler.NewScope(evt, provider.Ctx())
+	ctx := handler.WithScope(context.Background(), root)
+	assert.NoError(t, ed.dispatchToMatchers(ctx, evt))
+	assert.False(t, leaked.Load(), "匹配器写入的值泄漏到了其他匹配器")
+}
+
+func TestMatcherHooksVetoAndReportErrors(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.pr = plugin.GetPluginRegistry()
+	evt := &queueEvent{n: 7}
+
+	var called atomic.Bool
+	veto := plugin.NewMatcher(nil, handler.NewHandler(func() { called.Store(true) }))
+	failing := plugin.NewMatcher(nil, handler.NewHandler(func() error { return errors.New("boom") }))
+
+	before := hook.RegisterGlobalHook(hook.MatcherBeforeHandle, func(hc *hook.HookContext) error {
+		if mhc, _ := hook.As[*hook.MatcherHookContext](hc); mhc.Matcher == veto {
+			return errors.New("veto")
+		}
+		return nil
+	})
+	var reported error
+	onError := hook.RegisterGlobalHook(hook.MatcherOnError, func(hc *hook.HookContext) error {
+		mhc, ok := hook.As[*hook.MatcherHookContext](hc)
+		require.True(t, ok)
+		assert.Same(t, evt, mhc.Event)
+		reported = mhc.Err
+		return nil
+	})
+	defer hook.GlobalHookManager().RemoveHook(hook.MatcherBeforeHandle, before)
+	defer hook.GlobalHookManager().RemoveHook(hook.MatcherOnError, onError)
+
+	assert.ErrorIs(t, ed.callMatcher(context.Background(), evt, veto), ErrMatcherVetoed)
+	assert.False(t, called.Load(), "Hook 返回错误时不执行处理器")
+
+	assert.EqualError(t, ed.callMatcher(context.Background(), evt, failing), "boom")
+	assert.EqualError(t, reported, "boom")
+}
+
+func TestVetoedMatcherDoesNotBlock(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.mr = plugin.GetMatcherRegistry()
+	ed.pr = plugin.GetPluginRegistry()
+	ed.switches = policy.New()
+
+	evt := &queueEvent{n: 8}
+	only := rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == evt })
+
+	var lowCalled atomic.Bool
+	veto := plugin.NewMatcher(only, handler.NewHandler(func() {})).SetPriority(100).SetBlock(true)
+	low := plugin.NewMatcher(only, handler.NewHandler(func() { lowCalled.Store(true) })).SetPriority(1)
+	ed.mr.RegisterMatchers(veto, low)
+	defer ed.mr.UnregisterMatchers(veto)
+	defer ed.mr.UnregisterMatchers(low)
+
+	before := hook.RegisterGlobalHook(hook.MatcherBeforeHandle, func(hc *hook.HookContext) error {
+		if mhc, _ := hook.As[*hook.MatcherHookContext](hc); mhc.Matcher == veto {
+			return errors.New("veto")
+		}
+		return nil
+	})
+	defer hook.GlobalHookManager().RemoveHook(hook.MatcherBeforeHandle, before)
+
+	assert.NoError(t, ed.dispatchToMatchers(context.Background(), evt))
+	assert.True(t, lowCalled.Load(), "被 Hook 取消的阻断匹配器不应阻断低优先级处理器")
+}
+
+func TestDetachedHandlerReleasesWorker(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.mr = plugin.GetMatcherRegistry()
+	ed.pr = plugin.GetPluginRegistry()
+	ed.switches = policy.New()
+	ed.wg.Add(1)
+	go ed.startEventLoop(0)
+	defer ed.Stop()
+
+	waiting, next := &queueEvent{n: 1}, &queueEvent{n: 2}
+	release := make(chan struct{})
+	defer close(release)
+	handled := make(chan struct{})
+
+	ms := []*plugin.Matcher{
+		plugin.NewMatcher(
+			rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == waiting }),
+			handler.NewHandler(func(ctx context.Context) {
+				handler.Detach(ctx) // 模拟会话等待回复
+				<-release
+			}),
+		),
+		plugin.NewMatcher(
+			rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == next }),
+			handler.NewHandler(func() { close(handled) }),
+		),
+	}
+	ed.mr.RegisterMatchers(ms...)
+	defer func() {
+		for _, m := range ms {
+			ed.mr.UnregisterMatchers(m)
+		}
+	}()
+
+	require.NoError(t, ed.enqueue(EventWrapper{Event: waiting, Adapter: stubAdapter{}}))
+	require.NoError(t, ed.enqueue(EventWrapper{Event: next, Adapter: stubAdapter{}}))
+
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待中的处理器阻塞了 worker")
+	}
+}
+
+func TestTemporaryMatcherRunsInboundMiddlewares(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowBlock)
+	ed.mr = plugin.GetMatcherRegistry()
+
+	var allow atomic.Bool
+	ed.middlewares = []middleware.Middleware{
+		middleware.MiddlewareFunc("gate", func(ctx context.Context, e event.Event, next middleware.HandlerFunc) error {
+			if !allow.Load() {
+				return nil // 模拟频率限制丢弃事件
+			}
+			return next(ctx, e)
+		}),
+	}
+
+	reply := &queueEvent{n: 1}
+	var got atomic.Int32
+	m := plugin.NewMatcher(
+		rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return e == reply }),
+		handler.NewHandler(func() { got.Add(1) }),
+	).SetTemporary(true)
+	ed.mr.RegisterMatchers(m)
+	defer ed.mr.UnregisterMatchers(m)
+
+	assert.True(t, ed.dispatchTemporary(EventWrapper{Event: reply, Adapter: stubAdapter{}}))
+	assert.Zero(t, got.Load(), "被中间件拦截的回复不应交给会话")
+
+	allow.Store(true)
+	assert.True(t, ed.dispatchTemporary(EventWrapper{Event: reply, Adapter: stubAdapter{}}))
+	assert.EqualValues(t, 1, got.Load())
+}
+
+type metaEvent struct {
+	queueEvent
+	metaType string
+}
+
+func (e *metaEvent) Type() string          { return "meta_event" }
+func (e *metaEvent) MetaEventType() string { return e.metaType }
+
+// 解析结果固定的适配器
+type parseAdapter struct {
+	stubAdapter
+	evt event.Event
+}
+
+func (a parseAdapter) ParseEvent(raw any) (event.Event, error) { return a.evt, nil }
+func (a parseAdapter) ValidateEvent(e event.Event) error       { return nil }
+
+func TestHeartbeatIsNotQueued(t *testing.T) {
+	ed := newQueueOnlyDispatcher(conf.OverflowDropNewest)
+	ed.mr = plugin.GetMatcherRegistry()
+
+	heartbeat := parseAdapter{evt: &metaEvent{metaType: "heartbeat"}}
+	require.NoError(t, ed.processRawMessage([]byte("{}"), heartbeat, "test"))
+	assert.Empty(t, ed.queues[0])
+
+	lifecycle := parseAdapter{evt: &metaEvent{metaType: "lifecycle"}}
+	require.NoError(t, ed.processRawMessage([]byte("{}"), lifecycle, "test"))
+	assert.Len(t, ed.queues[0], 1)
+}
@@ -2,16 +2,19 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 	"yora/pkg/adapter"
 	"yora/pkg/conf"
+	"yora/pkg/hook"
 	"yora/pkg/log"
 	"yora/pkg/message"
 	"yora/pkg/middleware"
 	"yora/pkg/plugin"
+	"yora/pkg/policy"
 
 	"github.com/rs/zerolog"
 )
@@ -48,6 +51,7 @@ type botImpl struct {
 	server          *http.Server             // HTTP 服务器
 	mu              sync.RWMutex             // 读写锁
 	running         bool                     // 运行状态
+	cancel          context.CancelFunc       // 取消主动连接
 
 }
 
@@ -59,9 +63,14 @@ func newBot(conf *conf.BotConfig) *botImpl {
 		pluginManager:   plugin.GetPluginRegistry(),
 		pending:         sync.Map{},
 		server:          &http.Server{},
-		dispatcher:      NewEventDispatcher(),
+		dispatcher:      NewEventDispatcher(conf),
 		running:         false,
 	}
+	b.dispatcher.outbound = b.adapterRegistry.Middlewares
+
+	if err := policy.GetPolicy().SetFile(conf.PolicyFile); err != nil {
+		b.logger.Error().Err(err).Str("文件", conf.PolicyFile).Msg("加载插件开关策略失败")
+	}
 
 	b.logger.Info().Msg("创建机器人")
 	return b
@@ -76,8 +85,22 @@ func (b *botImpl) RegisterMiddlewares(middlewares ...middleware.Middleware) erro
 	return b.dispatcher.RegisterMiddlewares(middlewares...)
 }
 
+// 注册出站中间件
+func (b *botImpl) RegisterOutboundMiddlewares(middlewares ...middleware.OutboundMiddleware) error {
+	b.adapterRegistry.RegisterMiddleware(middlewares...)
+	for _, m := range middlewares {
+		b.logger.Info().Str("中间件", m.Name()).Msg("添加出站中间件")
+	}
+	return nil
+}
+
 // 调用协议API
 func (b *botImpl) CallAPI(params ...any) (any, error) {
+	return b.callAPI(context.Background(), params...)
+}
+
+// 调用协议API，ctx 中携带来源适配器时只使用该适配器
+func (b *botImpl) callAPI(ctx context.Context, params ...any) (any, error) {
 	var (
 		lastErr error
 		result  any
@@ -112,9 +135,9 @@ func (b *botImpl) CallAPI(params ...any) (any, error) {
 		Interface("参数", apiParams).
 		Msg("调用API")
 
-	for p, a := range b.adapterRegistry.Adapters() {
+	for p, a := range b.targetAdapters(ctx) {
 		b.logger.Debug().Str("协议", string(p)).Msg("使用 Bot 适配器调用API")
-		r, err := a.CallAPI(action, apiParams)
+		r, err := a.CallAPI(ctx, action, apiParams)
 		if err != nil {
 			b.logger.Error().
 				Err(err).
@@ -136,6 +159,19 @@ func (b *botImpl) CallAPI(params ...any) (any, error) {
 
 }
 
+// 发送目标适配器：事件来源适配器，未指定时为全部适配器
+func (b *botImpl) targetAdapters(ctx context.Context) map[adapter.Protocol]adapter.Adapter {
+	if a, ok := adapter.FromContext(ctx); ok {
+		return map[adapter.Protocol]adapter.Adapter{a.Protocol(): a}
+	}
+	return b.adapterRegistry.Adapters()
+}
+
+// 指定账号
+func (b *botImpl) Account(selfID string) Bot {
+	return b.bind(adapter.WithSelfID(context.Background(), selfID))
+}
+
 func (b *botImpl) Platform() string {
 	panic("unimplemented")
 }
@@ -164,6 +200,10 @@ func (b *botImpl) RegisterAdapters(adapters ...adapter.Adapter) error {
 			return fmt.Errorf("适配器注册失败: %w", err)
 		}
 
+		if reporter, ok := a.(adapter.HealthReporter); ok {
+			reporter.OnConnectionLost(b.onConnectionLost)
+		}
+
 		b.logger.Info().
 			Str("协议", string(a.Protocol())).
 			Msg("适配器注册成功")
@@ -171,6 +211,33 @@ func (b *botImpl) RegisterAdapters(adapters ...adapter.Adapter) error {
 	return nil
 }
 
+// onConnectionLost 账号心跳超时，广播连接失活 Hook
+func (b *botImpl) onConnectionLost(h adapter.Health) {
+	b.logger.Warn().
+		Str("账号", h.SelfID).
+		Time("最后心跳", h.LastHeartbeat).
+		Dur("心跳间隔", h.Interval).
+		Msg("心跳超时，连接可能已断开")
+
+	hc := hook.NewBotHookContext(context.Background(), hook.BotOnConnectionLost, b.Account(h.SelfID))
+	hc.Set("health", h)
+	hc.Set("self_id", h.SelfID)
+	if err := b.pluginManager.BroadcastHook(hook.BotOnConnectionLost, hc.HookContext); err != nil {
+		b.logger.Warn().Err(err).Msg("连接失活Hook执行失败")
+	}
+}
+
+// Health 各适配器账号的连接健康状态
+func (b *botImpl) Health() []adapter.Health {
+	var result []adapter.Health
+	for _, a := range b.adapterRegistry.Adapters() {
+		if reporter, ok := a.(adapter.HealthReporter); ok {
+			result = append(result, reporter.Health()...)
+		}
+	}
+	return result
+}
+
 func (b *botImpl) RegisterPlugins(plugins ...plugin.Plugin) error {
 	b.pluginManager.RegisterPlugins(plugins...)
 	return nil
@@ -193,23 +260,33 @@ func (b *botImpl) SelfID() string {
 }
 
 // 发送消息
-func (b *botImpl) Send(userId string, groupId string, msg message.Message) (any, error) {
+func (b *botImpl) Send(userId string, groupId string, msg message.Message) (string, error) {
+	return b.send(context.Background(), userId, groupId, msg)
+}
+
+// 发送消息，ctx 中携带来源适配器时只使用该适配器
+func (b *botImpl) send(ctx context.Context, userId string, groupId string, msg message.Message) (string, error) {
 	if msg == nil {
 		b.logger.Error().Msg("发送消息失败：消息内容为空")
-		return nil, fmt.Errorf("消息内容不能为空")
+		return "", fmt.Errorf("消息内容不能为空")
 	}
 
-	var lastErr error
+	var (
+		lastErr   error
+		messageID string
+	)
 
 	b.logger.Debug().
 		Str("用户ID", userId).
 		Str("群组ID", groupId).
 		Msg("发送消息")
 
-	for p, a := range b.adapterRegistry.Adapters() {
+	ctx = adapter.WithOutbound(ctx, b.adapterRegistry.Middlewares())
+
+	for p, a := range b.targetAdapters(ctx) {
 		b.logger.Debug().Msg("使用 Bot 适配器发送消息")
 
-		_, err := a.Send(userId, groupId, msg)
+		id, err := adapter.Send(ctx, a, userId, groupId, msg)
 		if err != nil {
 			b.logger.Error().
 				Err(err).
@@ -218,15 +295,18 @@ func (b *botImpl) Send(userId string, groupId string, msg message.Message) (any,
 				Str("协议", string(p)).
 				Msg("消息发送失败")
 			lastErr = fmt.Errorf("消息发送失败: %w", err)
+			continue
 		}
+		messageID = id
 	}
 
 	b.logger.Info().
 		Str("用户ID", userId).
 		Str("群组ID", groupId).
+		Str("消息ID", messageID).
 		Msg("消息发送成功")
 
-	return nil, lastErr
+	return messageID, lastErr
 
 }
 
@@ -242,12 +322,10 @@ func (b *botImpl) Run() error {
 
 	b.logger.Info().Msg("启动机器人服务")
 
-	// 设置路由
-	b.setupRoutes()
-
 	// 创建HTTP服务器
 	b.server = &http.Server{
-		Addr:         ":12001",
+		Addr:         ":" + b.port(),
+		Handler:      b.setupRoutes(),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -262,18 +340,59 @@ func (b *botImpl) Run() error {
 		}
 	}()
 
+	// 主动连接协议端（正向 WebSocket 等）
+	ctx, cancel := context.WithCancel(context.Background())
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	for protocol, a := range b.adapterRegistry.Adapters() {
+		if err := b.dispatcher.ConnectAdapter(ctx, a, protocol); err != nil {
+			b.logger.Error().Err(err).Str("协议", string(protocol)).Msg("适配器连接失败")
+		}
+	}
+
+	hc := hook.NewBotHookContext(ctx, hook.BotOnStart, b)
+	if err := b.pluginManager.BroadcastHook(hook.BotOnStart, hc.HookContext); err != nil {
+		b.logger.Warn().Err(err).Msg("机器人启动Hook执行失败")
+	}
+
 	b.logger.Info().Msg("机器人服务启动完成")
 	return nil
 }
 
+// 监听端口，未配置时使用 12001
+func (b *botImpl) port() string {
+	if b.config.Port == "" {
+		return "12001"
+	}
+	return b.config.Port
+}
+
 // setupRoutes 设置HTTP路由
-func (b *botImpl) setupRoutes() {
+func (b *botImpl) setupRoutes() http.Handler {
+	mux := http.NewServeMux()
 	// 健康检查端点
-	http.HandleFunc("/", b.handleHealthCheck)
-	// WebSocket 端点
-	http.HandleFunc("/onebot/v11/ws", b.handleWebSocket)
+	mux.HandleFunc("/", b.handleHealthCheck)
+	// OneBot v11 约定的端点：反向 WebSocket 与 HTTP 上报
+	mux.HandleFunc("/onebot/v11/ws", b.handleWebSocket(adapter.ProtocolOneBot))
+	mux.HandleFunc("/onebot/v11/http", b.handleHTTP(adapter.ProtocolOneBot))
+	// 其他协议按路径中的协议名路由到对应适配器
+	mux.HandleFunc("/{protocol}/ws", func(w http.ResponseWriter, r *http.Request) {
+		b.handleWebSocket(adapter.Protocol(r.PathValue("protocol")))(w, r)
+	})
+	mux.HandleFunc("/{protocol}/http", func(w http.ResponseWriter, r *http.Request) {
+		b.handleHTTP(adapter.Protocol(r.PathValue("protocol")))(w, r)
+	})
 
 	b.logger.Debug().Msg("HTTP 路由设置完成")
+	return mux
+}
+
+// 按协议查找已注册的适配器
+func (b *botImpl) adapterFor(protocol adapter.Protocol) (adapter.Adapter, bool) {
+	a, ok := b.adapterRegistry.Adapters()[protocol]
+	return a, ok
 }
 
 // handleHealthCheck 健康检查处理器
@@ -281,22 +400,72 @@ func (b *botImpl) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	b.logger.Debug().
 		Str("方法", r.Method).Str("路径", r.URL.Path).Str("客户端IP", r.RemoteAddr).Msg("收到健康检查请求")
 
+	response := struct {
+		Status   string           `json:"status"`
+		Message  string           `json:"message"`
+		Platform string           `json:"platform"`
+		Accounts []adapter.Health `json:"accounts"`
+	}{
+		Status:   "ok",
+		Message:  "月灵Bot 运行正常",
+		Platform: "onebot",
+		Accounts: b.Health(),
+	}
+
+	code := http.StatusOK
+	if len(response.Accounts) == 0 {
+		code = http.StatusServiceUnavailable
+		response.Status = "unhealthy"
+		response.Message = "没有已连接的账号"
+	}
+	for _, h := range response.Accounts {
+		if !h.Healthy {
+			code = http.StatusServiceUnavailable
+			response.Status = "unhealthy"
+			response.Message = "账号 " + h.SelfID + " 连接异常"
+			break
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	response := `{"status":"ok","message":"月灵Bot 运行正常","platform":"onebot"}`
-	w.Write([]byte(response))
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
 }
 
-// handleWebSocket WebSocket连接处理器
-func (b *botImpl) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	b.logger.Info().
-		Str("客户端IP", r.RemoteAddr).
-		Str("User-Agent", r.Header.Get("User-Agent")).
-		Msg("收到 WebSocket 连接请求")
+// handleWebSocket 将 WebSocket 连接交给对应协议的适配器
+func (b *botImpl) handleWebSocket(protocol adapter.Protocol) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b.logger.Info().
+			Str("协议", string(protocol)).
+			Str("客户端IP", r.RemoteAddr).
+			Str("User-Agent", r.Header.Get("User-Agent")).
+			Msg("收到 WebSocket 连接请求")
+
+		a, ok := b.adapterFor(protocol)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		b.dispatcher.HandleAdapterConnection(w, r, a, protocol)
+	}
+}
 
-	// 为每个适配器创建单独的处理逻辑
-	for protocol, adapter := range b.adapterRegistry.Adapters() {
-		b.dispatcher.HandleAdapterConnection(w, r, adapter, protocol)
+// handleHTTP 将 HTTP 事件上报交给对应协议的适配器
+func (b *botImpl) handleHTTP(protocol adapter.Protocol) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b.logger.Debug().
+			Str("协议", string(protocol)).
+			Str("客户端IP", r.RemoteAddr).
+			Msg("收到 HTTP 事件上报")
+
+		a, ok := b.adapterFor(protocol)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if err := b.dispatcher.HandleAdapterHTTP(w, r, a, protocol); err != nil {
+			b.logger.Warn().Err(err).Str("协议", string(protocol)).Msg("HTTP 事件上报处理失败")
+		}
 	}
 }
 
@@ -312,9 +481,22 @@ func (b *botImpl) ShutDown() error {
 
 	b.logger.Info().Msg("关闭机器人服务...")
 
+	hc := hook.NewBotHookContext(context.Background(), hook.BotOnStop, b)
+	if err := b.pluginManager.BroadcastHook(hook.BotOnStop, hc.HookContext); err != nil {
+		b.logger.Warn().Err(err).Msg("机器人关闭Hook执行失败")
+	}
+
 	// 关闭插件
 	b.logger.Info().Msg("卸载插件...")
 
+	// 断开主动连接
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	// 停止事件分发
+	b.dispatcher.Stop()
+
 	err := b.pluginManager.Unload()
 	if err != nil {
 		b.logger.Error().Err(err).Msg("插件卸载失败")
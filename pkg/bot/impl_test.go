@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yora/pkg/adapter"
+	"yora/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 记录 HTTP 上报次数的适配器
+type httpAdapter struct {
+	adapter.Adapter
+	protocol adapter.Protocol
+	calls    int
+}
+
+func (a *httpAdapter) Protocol() adapter.Protocol { return a.protocol }
+
+func (a *httpAdapter) HandleHTTP(w http.ResponseWriter, r *http.Request, f func(message []byte)) error {
+	a.calls++
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func TestRoutesDispatchToOneAdapter(t *testing.T) {
+	onebot := &httpAdapter{protocol: adapter.ProtocolOneBot}
+	other := &httpAdapter{protocol: "other"}
+
+	b := &botImpl{
+		logger:          log.NewBot("test"),
+		adapterRegistry: adapter.NewAdapterRegistry(),
+		dispatcher:      &EventDispatcher{logger: log.NewMatcher("test")},
+	}
+	require.NoError(t, b.adapterRegistry.Register(onebot))
+	require.NoError(t, b.adapterRegistry.Register(other))
+	mux := b.setupRoutes()
+
+	post := func(path string) int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, post("/onebot/v11/http"))
+	assert.Equal(t, 1, onebot.calls)
+	assert.Equal(t, 0, other.calls)
+
+	assert.Equal(t, http.StatusNoContent, post("/other/http"))
+	assert.Equal(t, 1, onebot.calls)
+	assert.Equal(t, 1, other.calls)
+
+	assert.Equal(t, http.StatusNotFound, post("/missing/http"))
+}
+
+// 报告固定健康状态的适配器
+type healthAdapter struct {
+	httpAdapter
+	accounts []adapter.Health
+}
+
+func (a *healthAdapter) Health() []adapter.Health { return a.accounts }
+
+func (a *healthAdapter) OnConnectionLost(f func(h adapter.Health)) {}
+
+func TestHealthCheckRequiresConnectedAccount(t *testing.T) {
+	reporter := &healthAdapter{httpAdapter: httpAdapter{protocol: adapter.ProtocolOneBot}}
+
+	b := &botImpl{
+		logger:          log.NewBot("test"),
+		adapterRegistry: adapter.NewAdapterRegistry(),
+		dispatcher:      &EventDispatcher{logger: log.NewMatcher("test")},
+	}
+	require.NoError(t, b.adapterRegistry.Register(reporter))
+	mux := b.setupRoutes()
+
+	get := func() int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	// 还没有任何账号连接
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+
+	reporter.accounts = []adapter.Health{{SelfID: "10", Healthy: true}}
+	assert.Equal(t, http.StatusOK, get())
+
+	reporter.accounts[0].Healthy = false
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+}
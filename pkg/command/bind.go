@@ -0,0 +1,275 @@
+package command
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 结构体绑定使用的标签：
+//
+//	type BanArgs struct {
+//		User    string `arg:"0,at" help:"要禁言的用户"`
+//		Minutes int    `opt:"t,time" default:"10" help:"禁言时长（分钟）"`
+//		Reason  string `arg:"1,optional"`
+//	}
+//
+// arg:"<位置>[,<类型>][,optional]" 为位置参数，类型缺省时由字段类型推断，可选 at/image/url；
+// opt:"<短选项>,<长选项>[,required]" 为选项参数，短/长选项可留空；
+// default 为默认值，help 为帮助信息，name 为位置参数显示名。
+const (
+	tagArg     = "arg"
+	tagOpt     = "opt"
+	tagDefault = "default"
+	tagHelp    = "help"
+	tagName    = "name"
+)
+
+// 结构体字段绑定信息
+type fieldSpec struct {
+	index    int
+	name     string
+	typ      string
+	isArg    bool
+	position int
+	opt      Opt
+	def      string
+	hasDef   bool
+	required bool
+	help     string
+}
+
+// Bind 将解析结果绑定到结构体指针，绑定完成后如结构体实现了 Validate() error 则执行校验
+func (r *ParseResult) Bind(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("绑定目标必须是非空的结构体指针，实际为 %T", dst)
+	}
+
+	specs, err := fieldSpecs(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range specs {
+		var (
+			value any
+			found bool
+		)
+
+		if f.isArg {
+			if f.position < len(r.Positionals) {
+				value, err = convertToken(r.Positionals[f.position], f.typ)
+				if err != nil {
+					return fmt.Errorf("参数 %s 的值无效: %s", f.name, err)
+				}
+				found = true
+			}
+		} else {
+			value, found = r.Options[f.opt.Key()]
+		}
+
+		if !found && f.hasDef {
+			if value, err = convertValue(f.def, f.typ); err != nil {
+				return fmt.Errorf("%s 的默认值无效: %s", f.name, err)
+			}
+			found = true
+		}
+
+		if !found {
+			if f.required {
+				if f.isArg {
+					return fmt.Errorf("缺少必填参数: %s", f.name)
+				}
+				return fmt.Errorf("缺少必填选项: %s", f.opt.Key())
+			}
+			continue
+		}
+
+		if err := assign(rv.Elem().Field(f.index), value); err != nil {
+			return fmt.Errorf("无法绑定 %s: %s", f.name, err)
+		}
+	}
+
+	if v, ok := dst.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// FromStruct 根据结构体标签生成命令的位置参数与选项定义
+func FromStruct(t reflect.Type) ([]Arg, []Opt, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	specs, err := fieldSpecs(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		args []Arg
+		opts []Opt
+	)
+	for _, f := range specs {
+		if f.isArg {
+			args = append(args, Arg{Name: f.name, Type: f.typ, Optional: !f.required, Help: f.help})
+			continue
+		}
+		opt := f.opt
+		if f.hasDef {
+			if v, err := convertValue(f.def, f.typ); err == nil {
+				opt.Default = v
+			}
+		}
+		opts = append(opts, opt)
+	}
+	return args, opts, nil
+}
+
+// IsBindable 判断类型是否为带有 arg/opt 标签的结构体指针
+func IsBindable(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	st := t.Elem()
+	for i := 0; i < st.NumField(); i++ {
+		tag := st.Field(i).Tag
+		if _, ok := tag.Lookup(tagArg); ok {
+			return true
+		}
+		if _, ok := tag.Lookup(tagOpt); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析结构体标签，位置参数按位置排序
+func fieldSpecs(t reflect.Type) ([]fieldSpec, error) {
+	var specs []fieldSpec
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		argTag, isArg := field.Tag.Lookup(tagArg)
+		optTag, isOpt := field.Tag.Lookup(tagOpt)
+		if !isArg && !isOpt {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("字段 %s 必须导出", field.Name)
+		}
+
+		f := fieldSpec{
+			index: i,
+			name:  strings.ToLower(field.Name),
+			typ:   kindType(field.Type),
+			help:  field.Tag.Get(tagHelp),
+		}
+		f.def, f.hasDef = field.Tag.Lookup(tagDefault)
+		if name := field.Tag.Get(tagName); name != "" {
+			f.name = name
+		}
+
+		if isArg {
+			parts := strings.Split(argTag, ",")
+			pos, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+			if err != nil {
+				return nil, fmt.Errorf("字段 %s: 无效的参数位置 %q", field.Name, parts[0])
+			}
+			f.isArg = true
+			f.position = pos
+			f.required = !f.hasDef
+			for _, p := range parts[1:] {
+				switch p = strings.TrimSpace(p); p {
+				case "optional":
+					f.required = false
+				case "":
+				default:
+					f.typ = p
+				}
+			}
+		} else {
+			parts := strings.Split(optTag, ",")
+			if len(parts) < 2 {
+				parts = append(parts, "")
+			}
+			f.opt = Opt{
+				Short: strings.TrimSpace(parts[0]),
+				Long:  strings.TrimSpace(parts[1]),
+				Type:  f.typ,
+				Help:  f.help,
+			}
+			if f.opt.Short == "" && f.opt.Long == "" {
+				return nil, fmt.Errorf("字段 %s: 选项需要短选项或长选项名", field.Name)
+			}
+			for _, p := range parts[2:] {
+				switch p = strings.TrimSpace(p); p {
+				case "required":
+					f.required = true
+					f.opt.Required = true
+				case "":
+				default:
+					f.typ = p
+					f.opt.Type = p
+				}
+			}
+			if f.opt.Long != "" {
+				f.name = f.opt.Long
+			}
+		}
+
+		specs = append(specs, f)
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		if specs[i].isArg != specs[j].isArg {
+			return specs[i].isArg
+		}
+		return specs[i].isArg && specs[i].position < specs[j].position
+	})
+	return specs, nil
+}
+
+// 根据字段类型推断参数类型
+func kindType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Int {
+			return "[]int"
+		}
+		return "[]string"
+	default:
+		return "string"
+	}
+}
+
+// 将转换后的值写入字段
+func assign(field reflect.Value, value any) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+	if v.Type().ConvertibleTo(field.Type()) && v.Kind() != reflect.String {
+		field.Set(v.Convert(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.String {
+		field.SetString(fmt.Sprint(value))
+		return nil
+	}
+	return fmt.Errorf("类型 %s 无法赋值给 %s", v.Type(), field.Type())
+}
@@ -1,9 +1,15 @@
-package main
+// Package command 提供 shell 风格的命令定义与解析，支持子命令、别名、位置参数与选项。
+//
+// 命令通过 on.OnShellCommand 声明为匹配器，解析结果 *ParseResult 可直接注入到 handler。
+package command
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+	"yora/pkg/message"
 )
 
 // Arg 位置参数
@@ -32,22 +38,26 @@ type Command struct {
 	Args        []Arg               // 位置参数
 	Options     []Opt               // 选项参数
 	SubCommands map[string]*Command // 子命令
-	Handler     func(*ParseResult)  // 处理函数
+	Passthrough bool                // 不解析选项，命令名后的参数全部作为位置参数（用于 on.OnCommand）
+
+	parent *Command // 父命令（注册时设置）
 }
 
 // ParseResult 解析结果
 type ParseResult struct {
-	Command   *Command
-	Args      map[string]interface{}
-	Options   map[string]interface{}
-	RawArgs   []string
-	Remaining []string
+	Command     *Command
+	Args        map[string]interface{}
+	Options     map[string]interface{}
+	RawArgs     []string
+	Remaining   []string
+	Positionals []Token // 全部位置参数（含 Args 已消费的部分），用于结构体绑定
 }
 
 // Parser 命令解析器
 type Parser struct {
-	Commands map[string]*Command
-	Prefix   string
+	Commands   map[string]*Command
+	Prefix     string
+	IgnoreCase bool // 命令名（含别名）是否忽略大小写
 }
 
 // NewParser 创建解析器
@@ -58,18 +68,95 @@ func NewParser(prefix string) *Parser {
 	}
 }
 
+// SetIgnoreCase 设置命令名是否忽略大小写
+func (p *Parser) SetIgnoreCase(ignore bool) *Parser {
+	p.IgnoreCase = ignore
+	return p
+}
+
 // Register 注册命令
 func (p *Parser) Register(cmd *Command) {
+	cmd.link(nil)
 	p.Commands[cmd.Name] = cmd
 }
 
-// Parse 解析命令
+// ParseError 命令解析错误，携带出错的命令以便展示用法
+type ParseError struct {
+	Command *Command // 出错的命令（未知命令时为 nil）
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// 设置子命令的父命令
+func (c *Command) link(parent *Command) {
+	c.parent = parent
+	for _, sub := range c.SubCommands {
+		sub.link(c)
+	}
+}
+
+// Names 返回命令名及全部别名
+func (c *Command) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// Path 返回从根命令开始的完整命令路径，如 "user add"
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// 按名称或别名查找子命令
+func (c *Command) findSub(name string) *Command {
+	if sub, ok := c.SubCommands[name]; ok {
+		return sub
+	}
+	for _, sub := range c.SubCommands {
+		if slices.Contains(sub.Aliases, name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// 按名称或别名查找已注册命令
+func (p *Parser) find(name string) *Command {
+	if cmd, ok := p.Commands[name]; ok {
+		return cmd
+	}
+	for _, cmd := range p.Commands {
+		if slices.Contains(cmd.Aliases, name) {
+			return cmd
+		}
+	}
+	if p.IgnoreCase {
+		for _, cmd := range p.Commands {
+			for _, n := range cmd.Names() {
+				if strings.EqualFold(n, name) {
+					return cmd
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Parse 解析命令文本
 func (p *Parser) Parse(input string) (*ParseResult, error) {
 	input = strings.TrimSpace(input)
 
 	// 检查前缀
 	if p.Prefix != "" && !strings.HasPrefix(input, p.Prefix) {
-		return nil, fmt.Errorf("command must start with prefix: %s", p.Prefix)
+		return nil, fmt.Errorf("命令必须以 %s 开头", p.Prefix)
 	}
 
 	if p.Prefix != "" {
@@ -78,51 +165,94 @@ func (p *Parser) Parse(input string) (*ParseResult, error) {
 	}
 
 	if input == "" {
-		return nil, fmt.Errorf("empty command")
+		return nil, fmt.Errorf("命令为空")
+	}
+
+	return p.parseTokens(textTokens(Tokenize(input)))
+}
+
+// ParseMessage 解析消息，@ 与图片消息段会作为 at/image 类型的参数
+func (p *Parser) ParseMessage(msg message.Message) (*ParseResult, error) {
+	tokens, err := p.messageTokens(msg)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseTokens(tokens)
+}
+
+// Matches 消息的第一个参数是否为已注册的命令（与 ParseMessage 使用相同的切分方式）
+func (p *Parser) Matches(msg message.Message) bool {
+	tokens, err := p.messageTokens(msg)
+	if err != nil {
+		return false
+	}
+	return tokens[0].Type == TokenText && p.find(tokens[0].Value) != nil
+}
+
+// 切分消息并去掉命令前缀
+func (p *Parser) messageTokens(msg message.Message) ([]Token, error) {
+	tokens := TokenizeMessage(msg)
+
+	if p.Prefix != "" {
+		if len(tokens) == 0 || tokens[0].Type != TokenText || !strings.HasPrefix(tokens[0].Value, p.Prefix) {
+			return nil, fmt.Errorf("命令必须以 %s 开头", p.Prefix)
+		}
+		tokens[0].Value = strings.TrimPrefix(tokens[0].Value, p.Prefix)
+		if tokens[0].Value == "" {
+			tokens = tokens[1:]
+		}
 	}
 
-	tokens := tokenize(input)
 	if len(tokens) == 0 {
-		return nil, fmt.Errorf("no command provided")
+		return nil, fmt.Errorf("命令为空")
+	}
+	return tokens, nil
+}
+
+func (p *Parser) parseTokens(tokens []Token) (*ParseResult, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("未提供命令")
 	}
 
-	// 查找主命令
-	cmdName := tokens[0]
-	cmd, exists := p.Commands[cmdName]
-	if !exists {
-		return nil, fmt.Errorf("unknown command: %s", cmdName)
+	// 查找主命令（支持别名）
+	cmdName := tokens[0].Value
+	cmd := p.find(cmdName)
+	if tokens[0].Type != TokenText || cmd == nil {
+		return nil, &ParseError{Err: fmt.Errorf("未知命令: %s", cmdName)}
 	}
 
 	tokens = tokens[1:]
 
-	// 检查子命令
-	if len(tokens) > 0 && cmd.SubCommands != nil {
-		if subCmd, exists := cmd.SubCommands[tokens[0]]; exists {
-			cmd = subCmd
-			tokens = tokens[1:]
+	// 逐级查找子命令
+	for len(tokens) > 0 && tokens[0].Type == TokenText && cmd.SubCommands != nil {
+		sub := cmd.findSub(tokens[0].Value)
+		if sub == nil {
+			break
 		}
+		cmd = sub
+		tokens = tokens[1:]
 	}
 
-	return p.parseCommand(cmd, tokens)
+	result, err := p.parseCommand(cmd, tokens)
+	if err != nil {
+		return nil, &ParseError{Command: cmd, Err: err}
+	}
+	return result, nil
 }
 
 // parseCommand 解析具体命令
-func (p *Parser) parseCommand(cmd *Command, tokens []string) (*ParseResult, error) {
+func (p *Parser) parseCommand(cmd *Command, tokens []Token) (*ParseResult, error) {
 	result := &ParseResult{
 		Command: cmd,
 		Args:    make(map[string]interface{}),
 		Options: make(map[string]interface{}),
-		RawArgs: tokens,
+		RawArgs: tokenValues(tokens),
 	}
 
 	// 初始化选项默认值
 	for _, opt := range cmd.Options {
 		if opt.Default != nil {
-			key := opt.Long
-			if key == "" {
-				key = opt.Short
-			}
-			result.Options[key] = opt.Default
+			result.Options[opt.Key()] = opt.Default
 		}
 	}
 
@@ -130,14 +260,21 @@ func (p *Parser) parseCommand(cmd *Command, tokens []string) (*ParseResult, erro
 	argIndex := 0
 
 	for i < len(tokens) {
-		token := tokens[i]
+		token := tokens[i].Value
+
+		if cmd.Passthrough {
+			result.Positionals = append(result.Positionals, tokens[i])
+			result.Remaining = append(result.Remaining, token)
+			i++
+			continue
+		}
 
-		if strings.HasPrefix(token, "--") {
+		if tokens[i].Type == TokenText && strings.HasPrefix(token, "--") {
 			// 长选项
 			optName := strings.TrimPrefix(token, "--")
 			opt := findOptionByLong(cmd, optName)
 			if opt == nil {
-				return nil, fmt.Errorf("unknown option: --%s", optName)
+				return nil, fmt.Errorf("未知选项: --%s", optName)
 			}
 
 			if opt.Type == "bool" {
@@ -145,49 +282,47 @@ func (p *Parser) parseCommand(cmd *Command, tokens []string) (*ParseResult, erro
 				i++
 			} else {
 				if i+1 >= len(tokens) {
-					return nil, fmt.Errorf("option --%s requires a value", optName)
+					return nil, fmt.Errorf("选项 --%s 缺少值", optName)
 				}
-				value, err := convertValue(tokens[i+1], opt.Type)
+				value, err := convertToken(tokens[i+1], opt.Type)
 				if err != nil {
-					return nil, fmt.Errorf("invalid value for option --%s: %s", optName, err)
+					return nil, fmt.Errorf("选项 --%s 的值无效: %s", optName, err)
 				}
 				result.Options[opt.Long] = value
 				i += 2
 			}
-		} else if strings.HasPrefix(token, "-") && len(token) > 1 {
-			// 短选项
+		} else if tokens[i].Type == TokenText && strings.HasPrefix(token, "-") && len(token) > 1 && !isNumber(token) {
+			// 短选项（负数视为位置参数）
 			optName := strings.TrimPrefix(token, "-")
 			opt := findOptionByShort(cmd, optName)
 			if opt == nil {
-				return nil, fmt.Errorf("unknown option: -%s", optName)
+				return nil, fmt.Errorf("未知选项: -%s", optName)
 			}
 
-			key := opt.Long
-			if key == "" {
-				key = opt.Short
-			}
+			key := opt.Key()
 
 			if opt.Type == "bool" {
 				result.Options[key] = true
 				i++
 			} else {
 				if i+1 >= len(tokens) {
-					return nil, fmt.Errorf("option -%s requires a value", optName)
+					return nil, fmt.Errorf("选项 -%s 缺少值", optName)
 				}
-				value, err := convertValue(tokens[i+1], opt.Type)
+				value, err := convertToken(tokens[i+1], opt.Type)
 				if err != nil {
-					return nil, fmt.Errorf("invalid value for option -%s: %s", optName, err)
+					return nil, fmt.Errorf("选项 -%s 的值无效: %s", optName, err)
 				}
 				result.Options[key] = value
 				i += 2
 			}
 		} else {
 			// 位置参数
+			result.Positionals = append(result.Positionals, tokens[i])
 			if argIndex < len(cmd.Args) {
 				arg := cmd.Args[argIndex]
-				value, err := convertValue(token, arg.Type)
+				value, err := convertToken(tokens[i], arg.Type)
 				if err != nil {
-					return nil, fmt.Errorf("invalid value for argument %s: %s", arg.Name, err)
+					return nil, fmt.Errorf("参数 %s 的值无效: %s", arg.Name, err)
 				}
 				result.Args[arg.Name] = value
 				argIndex++
@@ -201,19 +336,15 @@ func (p *Parser) parseCommand(cmd *Command, tokens []string) (*ParseResult, erro
 	// 检查必需的参数
 	for i, arg := range cmd.Args {
 		if i >= argIndex && !arg.Optional {
-			return nil, fmt.Errorf("missing required argument: %s", arg.Name)
+			return nil, fmt.Errorf("缺少必填参数: %s", arg.Name)
 		}
 	}
 
 	// 检查必需的选项
 	for _, opt := range cmd.Options {
 		if opt.Required {
-			key := opt.Long
-			if key == "" {
-				key = opt.Short
-			}
-			if _, exists := result.Options[key]; !exists {
-				return nil, fmt.Errorf("missing required option: %s", key)
+			if _, exists := result.Options[opt.Key()]; !exists {
+				return nil, fmt.Errorf("缺少必填选项: %s", opt.Key())
 			}
 		}
 	}
@@ -221,11 +352,17 @@ func (p *Parser) parseCommand(cmd *Command, tokens []string) (*ParseResult, erro
 	return result, nil
 }
 
-// Execute 执行命令
-func (result *ParseResult) Execute() {
-	if result.Command.Handler != nil {
-		result.Command.Handler(result)
+// Key 选项在 ParseResult.Options 中的键（优先长选项）
+func (o Opt) Key() string {
+	if o.Long != "" {
+		return o.Long
 	}
+	return o.Short
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
 }
 
 // 辅助函数
@@ -247,18 +384,28 @@ func findOptionByShort(cmd *Command, name string) *Opt {
 	return nil
 }
 
-type Bool bool
-
 func convertValue(value string, targetType string) (interface{}, error) {
 	switch targetType {
 	case "string":
 		return value, nil
 	case "int":
-		return strconv.Atoi(value)
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是整数", value)
+		}
+		return n, nil
 	case "float":
-		return strconv.ParseFloat(value, 64)
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是数字", value)
+		}
+		return f, nil
 	case "bool":
-		return strconv.ParseBool(value)
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是布尔值", value)
+		}
+		return b, nil
 	case "[]string":
 		return strings.Split(value, ","), nil
 	case "[]int":
@@ -267,19 +414,19 @@ func convertValue(value string, targetType string) (interface{}, error) {
 		for i, v := range values {
 			n, err := strconv.Atoi(v)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("%q 不是整数", v)
 			}
 			result[i] = n
 		}
 		return result, nil
 	case "url":
 		if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
-			return "", fmt.Errorf("invalid url: %s", value)
+			return "", fmt.Errorf("无效的链接: %s", value)
 		}
 		return value, nil
 	case "image":
 		if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
-			return "", fmt.Errorf("invalid image url: %s", value)
+			return "", fmt.Errorf("无效的图片链接: %s", value)
 		}
 		return value, nil
 
@@ -288,143 +435,45 @@ func convertValue(value string, targetType string) (interface{}, error) {
 	}
 }
 
-func tokenize(input string) []string {
+// Tokenize 按空白（含换行）切分参数，支持单双引号包裹含空格的参数
+//
+// 只有位于参数开头的引号用于包裹，参数中间的引号原样保留，
+// 因此 {"a":1} 这样的 JSON 可直接作为参数，含空格的 JSON 可用另一种引号包裹：'{"a": 1}'。
+// 引号包裹的空参数（""）保留为空字符串；闭合引号结束当前参数，'a b'c 切分为 "a b" 与 "c"。
+func Tokenize(input string) []string {
 	var tokens []string
 	var current strings.Builder
-	inQuotes := false
-	quoteChar := byte(0)
-
-	for i := 0; i < len(input); i++ {
-		char := input[i]
+	started := false // 当前参数是否已开始（引号包裹的空参数也算）
+	quoteChar := rune(0)
+
+	flush := func() {
+		if started {
+			tokens = append(tokens, current.String())
+			current.Reset()
+			started = false
+		}
+	}
 
-		if inQuotes {
+	for _, char := range input {
+		switch {
+		case quoteChar != 0:
 			if char == quoteChar {
-				inQuotes = false
 				quoteChar = 0
+				flush()
 			} else {
-				current.WriteByte(char)
-			}
-		} else {
-			if char == '"' || char == '\'' {
-				inQuotes = true
-				quoteChar = char
-			} else if char == ' ' || char == '\t' {
-				if current.Len() > 0 {
-					tokens = append(tokens, current.String())
-					current.Reset()
-				}
-			} else {
-				current.WriteByte(char)
+				current.WriteRune(char)
 			}
+		case (char == '"' || char == '\'') && !started:
+			quoteChar = char
+			started = true
+		case unicode.IsSpace(char):
+			flush()
+		default:
+			current.WriteRune(char)
+			started = true
 		}
 	}
-
-	if current.Len() > 0 {
-		tokens = append(tokens, current.String())
-	}
+	flush()
 
 	return tokens
 }
-
-// 示例用法
-func main() {
-	// 创建解析器
-	parser := NewParser("/")
-
-	// 直接定义命令结构
-	userCmd := &Command{
-		Name: "user",
-		Help: "用户管理命令",
-		SubCommands: map[string]*Command{
-			"add": {
-				Name: "add",
-				Help: "添加用户",
-				Args: []Arg{
-					{Name: "username", Type: "string", Optional: false, Help: "用户名"},
-					{Name: "email", Type: "string", Optional: true, Help: "邮箱地址"},
-				},
-				Options: []Opt{
-					{Short: "a", Long: "admin", Type: "bool", Default: false, Help: "是否为管理员"},
-					{Short: "g", Long: "group", Type: "string", Default: "default", Help: "用户组"},
-				},
-				Handler: func(result *ParseResult) {
-					username := result.Args["username"].(string)
-					email, hasEmail := result.Args["email"]
-					isAdmin := result.Options["admin"].(bool)
-					group := result.Options["group"].(string)
-
-					fmt.Printf("添加用户: %s\n", username)
-					if hasEmail {
-						fmt.Printf("邮箱: %s\n", email.(string))
-					}
-					fmt.Printf("管理员: %t\n", isAdmin)
-					fmt.Printf("用户组: %s\n", group)
-				},
-			},
-			"list": {
-				Name: "list",
-				Help: "列出用户",
-				Options: []Opt{
-					{Short: "l", Long: "limit", Type: "int", Default: 10, Help: "限制数量"},
-					{Short: "f", Long: "filter", Type: "string", Help: "过滤条件"},
-				},
-				Handler: func(result *ParseResult) {
-					limit := result.Options["limit"].(int)
-					filter, hasFilter := result.Options["filter"]
-
-					fmt.Printf("列出用户，限制: %d\n", limit)
-					if hasFilter {
-						fmt.Printf("过滤条件: %s\n", filter.(string))
-					}
-				},
-			},
-		},
-	}
-
-	// 系统命令
-	sysCmd := &Command{
-		Name: "sys",
-		Help: "系统管理命令",
-		Args: []Arg{
-			{Name: "action", Type: "string", Optional: false, Help: "操作类型"},
-		},
-		Options: []Opt{
-			{Short: "f", Long: "force", Type: "bool", Default: false, Help: "强制执行"},
-			{Short: "t", Long: "timeout", Type: "int", Default: 30, Help: "超时时间(秒)"},
-		},
-		Handler: func(result *ParseResult) {
-			action := result.Args["action"].(string)
-			force := result.Options["force"].(bool)
-			timeout := result.Options["timeout"].(int)
-
-			fmt.Printf("系统操作: %s\n", action)
-			fmt.Printf("强制执行: %t\n", force)
-			fmt.Printf("超时时间: %d秒\n", timeout)
-		},
-	}
-
-	// 注册命令
-	parser.Register(userCmd)
-	parser.Register(sysCmd)
-
-	// 测试命令解析
-	testCommands := []string{
-		"/user add john john@example.com --admin --group staff",
-		"/user list --limit 20 --filter active",
-		"/sys restart --force --timeout 60",
-		"/user add alice 123",
-	}
-
-	for _, cmd := range testCommands {
-		fmt.Printf("\n解析命令: %s\n", cmd)
-		fmt.Println(strings.Repeat("-", 50))
-
-		result, err := parser.Parse(cmd)
-		if err != nil {
-			fmt.Printf("错误: %s\n", err)
-			continue
-		}
-
-		result.Execute()
-	}
-}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"yora/pkg/message"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserCommand() *Command {
+	return &Command{
+		Name:    "user",
+		Help:    "用户管理命令",
+		Aliases: []string{"u"},
+		SubCommands: map[string]*Command{
+			"add": {
+				Name:    "add",
+				Help:    "添加用户",
+				Aliases: []string{"new"},
+				Args: []Arg{
+					{Name: "username", Type: "string", Help: "用户名"},
+					{Name: "email", Type: "string", Optional: true, Help: "邮箱地址"},
+				},
+				Options: []Opt{
+					{Short: "a", Long: "admin", Type: "bool", Default: false, Help: "是否为管理员"},
+					{Short: "g", Long: "group", Type: "string", Default: "default", Help: "用户组"},
+				},
+			},
+			"list": {
+				Name: "list",
+				Options: []Opt{
+					{Short: "l", Long: "limit", Type: "int", Default: 10, Help: "限制数量"},
+				},
+			},
+		},
+	}
+}
+
+func TestParseSubCommandWithOptions(t *testing.T) {
+	p := NewParser("/")
+	p.Register(newUserCommand())
+
+	result, err := p.Parse(`/user add john "john doe@example.com" --admin -g staff`)
+	require.NoError(t, err)
+	assert.Equal(t, "user add", result.Command.Path())
+	assert.Equal(t, "john", result.Args["username"])
+	assert.Equal(t, "john doe@example.com", result.Args["email"])
+	assert.Equal(t, true, result.Options["admin"])
+	assert.Equal(t, "staff", result.Options["group"])
+}
+
+func TestParseAliases(t *testing.T) {
+	p := NewParser("")
+	p.Register(newUserCommand())
+
+	result, err := p.Parse("u new alice")
+	require.NoError(t, err)
+	assert.Equal(t, "add", result.Command.Name)
+	assert.Equal(t, "default", result.Options["group"])
+}
+
+func TestParseErrorCarriesCommand(t *testing.T) {
+	p := NewParser("")
+	p.Register(newUserCommand())
+
+	_, err := p.Parse("user list --limit many")
+	var pe *ParseError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "list", pe.Command.Name)
+
+	_, err = p.Parse("user add")
+	require.True(t, errors.As(err, &pe))
+	assert.Contains(t, pe.Command.Usage(), "用法：user add <username> [email] [选项]")
+	assert.Contains(t, pe.Command.Usage(), "-g, --group <string>  用户组（默认：default）")
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"引号包裹", `a "b c"  'd'`, []string{"a", "b c", "d"}},
+		{"参数中间的引号原样保留", `k {"a":["b"]}`, []string{"k", `{"a":["b"]}`}},
+		{"另一种引号包裹 JSON", `'{"a": 1}'`, []string{`{"a": 1}`}},
+		{"换行与全角空格", "cmd a\nb\r\nc\u3000d", []string{"cmd", "a", "b", "c", "d"}},
+		{"引号内保留换行", "say 'a\nb'", []string{"say", "a\nb"}},
+		{"空引号参数", `set name "" ''`, []string{"set", "name", "", ""}},
+		{"闭合引号结束参数", `'a b'c`, []string{"a b", "c"}},
+		{"未闭合的引号", `say "a b`, []string{"say", "a b"}},
+		{"只有空白", " \t\n ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Tokenize(tt.input))
+		})
+	}
+}
+
+type banArgs struct {
+	User    string `arg:"0,at" help:"要禁言的用户"`
+	Minutes int    `opt:"t,time" default:"10" help:"禁言时长（分钟）"`
+	Reason  string `arg:"1,optional"`
+}
+
+func (b *banArgs) Validate() error {
+	if b.Minutes <= 0 {
+		return errors.New("禁言时长必须为正数")
+	}
+	return nil
+}
+
+func TestBindStructFromMessage(t *testing.T) {
+	args, opts, err := FromStruct(reflect.TypeOf(&banArgs{}))
+	require.NoError(t, err)
+
+	p := NewParser("")
+	p.Register(&Command{Name: "ban", Args: args, Options: opts})
+
+	msg := message.New(
+		message.NewSegment("text", map[string]any{"text": "ban "}),
+		message.NewSegment("at", map[string]any{"qq": "12345"}),
+		message.NewSegment("text", map[string]any{"text": " 刷屏 -t 30"}),
+	)
+	result, err := p.ParseMessage(msg)
+	require.NoError(t, err)
+
+	var b banArgs
+	require.NoError(t, result.Bind(&b))
+	assert.Equal(t, banArgs{User: "12345", Minutes: 30, Reason: "刷屏"}, b)
+
+	result, err = p.Parse("ban 12345")
+	require.NoError(t, err)
+	b = banArgs{}
+	require.NoError(t, result.Bind(&b))
+	assert.Equal(t, 10, b.Minutes)
+
+	_, err = p.Parse("ban someone")
+	assert.Error(t, err)
+
+	result, err = p.Parse("ban 12345 -t 0")
+	require.NoError(t, err)
+	assert.EqualError(t, result.Bind(&banArgs{}), "禁言时长必须为正数")
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"yora/pkg/message"
+)
+
+// 参数来源类型
+const (
+	TokenText  = "text"  // 文本参数
+	TokenAt    = "at"    // @ 消息段，值为用户ID
+	TokenImage = "image" // 图片消息段，值为图片URL
+)
+
+// Token 命令参数，记录参数值及其来源消息段类型
+type Token struct {
+	Type  string
+	Value string
+}
+
+// TokenizeMessage 将消息切分为参数：文本段按空白切分（支持引号），@ 段解析为用户ID，图片段解析为URL
+func TokenizeMessage(msg message.Message) []Token {
+	var tokens []Token
+	if msg == nil {
+		return tokens
+	}
+
+	for _, seg := range msg.Segments() {
+		switch seg.Type() {
+		case "text":
+			tokens = append(tokens, textTokens(Tokenize(seg.String()))...)
+		case "at":
+			if id := segmentValue(seg, "qq", "user_id", "id"); id != "" {
+				tokens = append(tokens, Token{Type: TokenAt, Value: id})
+			}
+		case "image":
+			if url := segmentValue(seg, "url", "file"); url != "" {
+				tokens = append(tokens, Token{Type: TokenImage, Value: url})
+			}
+		}
+	}
+	return tokens
+}
+
+// 读取消息段中第一个非空的数据字段
+func segmentValue(seg message.Segment, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := seg.GetData(key); ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func textTokens(values []string) []Token {
+	tokens := make([]Token, len(values))
+	for i, v := range values {
+		tokens[i] = Token{Type: TokenText, Value: v}
+	}
+	return tokens
+}
+
+func tokenValues(tokens []Token) []string {
+	values := make([]string, len(tokens))
+	for i, t := range tokens {
+		values[i] = t.Value
+	}
+	return values
+}
+
+// 按目标类型转换参数，at/image 类型同时校验参数来源
+func convertToken(token Token, targetType string) (interface{}, error) {
+	switch targetType {
+	case TokenAt:
+		if token.Type == TokenAt {
+			return token.Value, nil
+		}
+		// 允许直接输入用户ID
+		if token.Type == TokenText && isUserID(token.Value) {
+			return strings.TrimPrefix(token.Value, "@"), nil
+		}
+		return nil, fmt.Errorf("需要 @用户，实际为: %s", token.Value)
+	case TokenImage:
+		if token.Type == TokenImage {
+			return token.Value, nil
+		}
+		return convertValue(token.Value, "image")
+	}
+
+	if token.Type != TokenText {
+		return nil, fmt.Errorf("需要 %s，实际为 %s", targetType, token.Type)
+	}
+	return convertValue(token.Value, targetType)
+}
+
+func isUserID(s string) bool {
+	s = strings.TrimPrefix(s, "@")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Syntax 返回单行命令语法，如 "user add <username> [email] [选项]"
+func (c *Command) Syntax() string {
+	var b strings.Builder
+	b.WriteString(c.Path())
+
+	if len(c.SubCommands) > 0 && len(c.Args) == 0 {
+		b.WriteString(" <子命令>")
+	}
+	for _, arg := range c.Args {
+		if arg.Optional {
+			fmt.Fprintf(&b, " [%s]", arg.Name)
+		} else {
+			fmt.Fprintf(&b, " <%s>", arg.Name)
+		}
+	}
+	if len(c.Options) > 0 {
+		b.WriteString(" [选项]")
+	}
+	return b.String()
+}
+
+// Usage 返回完整的帮助文本（语法、参数、选项、子命令、别名）
+func (c *Command) Usage() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "用法：%s", c.Syntax())
+	if c.Help != "" {
+		fmt.Fprintf(&b, "\n%s", c.Help)
+	}
+
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(&b, "\n别名：%s", strings.Join(c.Aliases, ", "))
+	}
+
+	if len(c.Args) > 0 {
+		b.WriteString("\n参数：")
+		for _, arg := range c.Args {
+			fmt.Fprintf(&b, "\n  %s", arg.Name)
+			if arg.Type != "" && arg.Type != "string" {
+				fmt.Fprintf(&b, " <%s>", arg.Type)
+			}
+			if arg.Help != "" {
+				fmt.Fprintf(&b, "  %s", arg.Help)
+			}
+			if arg.Optional {
+				b.WriteString("（可选）")
+			}
+		}
+	}
+
+	if len(c.Options) > 0 {
+		b.WriteString("\n选项：")
+		for _, opt := range c.Options {
+			fmt.Fprintf(&b, "\n  %s", opt.Flags())
+			if opt.Type != "" && opt.Type != "bool" {
+				fmt.Fprintf(&b, " <%s>", opt.Type)
+			}
+			if opt.Help != "" {
+				fmt.Fprintf(&b, "  %s", opt.Help)
+			}
+			if opt.Required {
+				b.WriteString("（必填）")
+			} else if opt.Default != nil && opt.Type != "bool" {
+				fmt.Fprintf(&b, "（默认：%v）", opt.Default)
+			}
+		}
+	}
+
+	if subs := c.SortedSubCommands(); len(subs) > 0 {
+		b.WriteString("\n子命令：")
+		for _, sub := range subs {
+			fmt.Fprintf(&b, "\n  %s", sub.Name)
+			if sub.Help != "" {
+				fmt.Fprintf(&b, "  %s", sub.Help)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Flags 返回选项的书写形式，如 "-f, --force"
+func (o Opt) Flags() string {
+	switch {
+	case o.Short != "" && o.Long != "":
+		return "-" + o.Short + ", --" + o.Long
+	case o.Long != "":
+		return "--" + o.Long
+	default:
+		return "-" + o.Short
+	}
+}
+
+// SortedSubCommands 按名称排序返回子命令
+func (c *Command) SortedSubCommands() []*Command {
+	subs := make([]*Command, 0, len(c.SubCommands))
+	for _, sub := range c.SubCommands {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].Name < subs[j].Name
+	})
+	return subs
+}
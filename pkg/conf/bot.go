@@ -1,14 +1,66 @@
 package conf
 
+import "time"
+
+// 事件队列溢出策略
+const (
+	OverflowBlock      = "block"       // 阻塞等待队列空位
+	OverflowDropOldest = "drop-oldest" // 丢弃队列中最旧的事件
+	OverflowDropNewest = "drop-newest" // 丢弃新到达的事件
+)
+
 type BotConfig struct {
 	*BaseConfig
 	Port        string `json:"port"`         // Port to listen on
 	LoggerLevel string `json:"logger_level"` // Logger level
 	SelfID      string `json:"self_id"`      // Bot's ID
+
+	SuperUsers []string `json:"super_users"` // 超级用户ID
+	PolicyFile string   `json:"policy_file"` // 插件开关策略存储文件
+
+	WorkerCount    int    `json:"worker_count"`    // 事件处理 worker 数量
+	QueueSize      int    `json:"queue_size"`      // 每个 worker 的事件队列容量
+	OverflowPolicy string `json:"overflow_policy"` // 队列满时的处理策略
+
+	OneBot OneBotConfig `json:"onebot"` // OneBot 连接配置
+}
+
+// OneBotConfig OneBot 连接配置，未填写的字段使用适配器默认值
+type OneBotConfig struct {
+	Mode          string `json:"mode"`           // 连接模式：ws-reverse、ws、http
+	URL           string `json:"url"`            // 正向 WebSocket 地址或 HTTP API 地址
+	AccessToken   string `json:"access_token"`   // 访问令牌
+	Secret        string `json:"secret"`         // HTTP 上报签名密钥
+	MessageFormat string `json:"message_format"` // 发送消息的格式：array、string
+
+	Timeout        time.Duration            `json:"timeout"`         // API 调用默认超时
+	ActionTimeouts map[string]time.Duration `json:"action_timeouts"` // 按 API 名称设置的超时，覆盖默认值
+
+	Send OneBotSendConfig `json:"send"` // 发送消息的限速与重试
+}
+
+// OneBotSendConfig 发送队列配置：频率为每秒条数，零值使用默认值，负数表示不限制/不启用
+type OneBotSendConfig struct {
+	GlobalRate  float64 `json:"global_rate"`  // 全局发送频率
+	GlobalBurst int     `json:"global_burst"` // 全局突发条数
+	GroupRate   float64 `json:"group_rate"`   // 每个群的发送频率
+	GroupBurst  int     `json:"group_burst"`  // 每个群的突发条数
+	UserRate    float64 `json:"user_rate"`    // 每个私聊用户的发送频率
+	UserBurst   int     `json:"user_burst"`   // 每个私聊用户的突发条数
+
+	Jitter           time.Duration `json:"jitter"`             // 每条消息发送前的随机延迟上限
+	MaxRetries       int           `json:"max_retries"`        // 暂时性失败的最大重试次数
+	RetryInterval    time.Duration `json:"retry_interval"`     // 初始重试间隔
+	MaxRetryInterval time.Duration `json:"max_retry_interval"` // 最大重试间隔
+	RetryRetcodes    []int         `json:"retry_retcodes"`     // 需要重试的 retcode
 }
 
 func NewBotConfig() *BotConfig {
 	return &BotConfig{
-		BaseConfig: NewBaseConfig(),
+		BaseConfig:     NewBaseConfig(),
+		WorkerCount:    4,
+		QueueSize:      100,
+		OverflowPolicy: OverflowBlock,
+		PolicyFile:     "data/policy.json",
 	}
 }
@@ -0,0 +1,26 @@
+package conf
+
+import (
+	"fmt"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// LoadBotConfig 从配置文件加载机器人配置（yaml、json、toml 等，按扩展名识别），
+// 字段名与 json 标签一致，文件中未填写的字段保留默认值
+func LoadBotConfig(path string) (*BotConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取配置文件 %s 失败: %w", path, err)
+	}
+
+	cfg := NewBotConfig()
+	if err := v.Unmarshal(cfg, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "json"
+	}); err != nil {
+		return nil, fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return cfg, nil
+}
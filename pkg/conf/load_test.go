@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBotConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yora.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+port: "9000"
+super_users: ["10001", "10002"]
+worker_count: 8
+onebot:
+  mode: ws
+  url: ws://127.0.0.1:3001
+  access_token: token
+  message_format: string
+  timeout: 15s
+  action_timeouts:
+    upload_group_file: 10m
+  send:
+    global_rate: 1.5
+    group_burst: 2
+    jitter: -1
+    max_retries: 5
+    retry_interval: 2s
+    retry_retcodes: [100, 1200]
+`), 0644))
+
+	cfg, err := LoadBotConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "9000", cfg.Port)
+	assert.Equal(t, []string{"10001", "10002"}, cfg.SuperUsers)
+	assert.Equal(t, 8, cfg.WorkerCount)
+	assert.Equal(t, "ws", cfg.OneBot.Mode)
+	assert.Equal(t, "ws://127.0.0.1:3001", cfg.OneBot.URL)
+	assert.Equal(t, "token", cfg.OneBot.AccessToken)
+	assert.Equal(t, "string", cfg.OneBot.MessageFormat)
+	assert.Equal(t, 15*time.Second, cfg.OneBot.Timeout)
+	assert.Equal(t, map[string]time.Duration{"upload_group_file": 10 * time.Minute}, cfg.OneBot.ActionTimeouts)
+	assert.Equal(t, 1.5, cfg.OneBot.Send.GlobalRate)
+	assert.Equal(t, 2, cfg.OneBot.Send.GroupBurst)
+	assert.Equal(t, time.Duration(-1), cfg.OneBot.Send.Jitter)
+	assert.Equal(t, 5, cfg.OneBot.Send.MaxRetries)
+	assert.Equal(t, 2*time.Second, cfg.OneBot.Send.RetryInterval)
+	assert.Equal(t, []int{100, 1200}, cfg.OneBot.Send.RetryRetcodes)
+
+	// 未填写的字段保留默认值
+	assert.Equal(t, 100, cfg.QueueSize)
+	assert.Equal(t, OverflowBlock, cfg.OverflowPolicy)
+	assert.NotNil(t, cfg.BaseConfig)
+}
+
+func TestLoadBotConfigMissingFile(t *testing.T) {
+	_, err := LoadBotConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package event
 
 import (
+	"context"
 	"time"
 )
 
@@ -34,9 +35,13 @@ type MetaEvent interface {
 	Extra() map[string]any
 }
 
+// NoticeEvent 通知事件接口（如群成员变动、撤回、戳一戳等）
 type NoticeEvent interface {
 	Event
 
+	// NoticeType 通知类型，比如 group_increase、notify
+	NoticeType() string
+
 	// UserID 相关用户ID
 	UserID() string
 
@@ -54,6 +59,9 @@ type NoticeEvent interface {
 type RequestEvent interface {
 	Event
 
+	// RequestType 请求类型，比如 friend、group
+	RequestType() string
+
 	// UserID 请求用户ID
 	UserID() string
 
@@ -66,6 +74,12 @@ type RequestEvent interface {
 	// Flag 请求标识符，用于响应请求
 	Flag() string
 
+	// Approve 同意请求，remark 为好友备注（平台不支持时忽略）
+	Approve(ctx context.Context, remark string) error
+
+	// Reject 拒绝请求，reason 为拒绝理由（平台不支持时忽略）
+	Reject(ctx context.Context, reason string) error
+
 	// Extra 额外数据
 	Extra() map[string]any
 }
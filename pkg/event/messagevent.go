@@ -12,9 +12,12 @@ type MessageEvent interface {
 	// ChatID 聊天会话ID（群聊ID、频道ID等）
 	ChatID() string
 
-	// Message 返回结构化消息
+	// Message 返回结构化消息，消息段为协议原生格式
 	Message() message.Message
 
+	// StandardMessage 返回转换为标准消息段的消息，与协议无关的插件应使用它
+	StandardMessage() message.Message
+
 	// RawMessage 返回原始文本内容
 	RawMessage() string
 
@@ -46,9 +49,12 @@ type GroupMessageEvent interface {
 	// ChatID 聊天会话ID（群聊ID、频道ID等）
 	ChatID() string
 
-	// Message 返回结构化消息
+	// Message 返回结构化消息，消息段为协议原生格式
 	Message() message.Message
 
+	// StandardMessage 返回转换为标准消息段的消息，与协议无关的插件应使用它
+	StandardMessage() message.Message
+
 	// RawMessage 返回原始文本内容
 	RawMessage() string
 
@@ -77,9 +83,12 @@ type PrimaryMessageEvent interface {
 	// UserID 发言者用户ID
 	UserID() string
 
-	// Message 返回结构化消息
+	// Message 返回结构化消息，消息段为协议原生格式
 	Message() message.Message
 
+	// StandardMessage 返回转换为标准消息段的消息，与协议无关的插件应使用它
+	StandardMessage() message.Message
+
 	// RawMessage 返回原始文本内容
 	RawMessage() string
 
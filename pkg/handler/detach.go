@@ -0,0 +1,21 @@
+package handler
+
+import "context"
+
+type detachKey struct{}
+
+// WithDetach 挂载脱离回调，由事件 worker 提供
+func WithDetach(ctx context.Context, detach func()) context.Context {
+	return context.WithValue(ctx, detachKey{}, detach)
+}
+
+// Detach 通知事件 worker 当前处理将长时间等待（如会话等待回复），
+// worker 不再等待本次处理结束，继续处理队列中的后续事件。重复调用无效果
+func Detach(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if detach, ok := ctx.Value(detachKey{}).(func()); ok {
+		detach()
+	}
+}
@@ -2,8 +2,9 @@
 //
 // 核心功能：
 //   - 使用 NewHandler 构造并注册事件处理器
-//   - 使用 RegisterProvider 方法注入函数依赖
+//   - 使用 RegisterProviders 方法注入仅对该 handler 生效的依赖
 //   - 使用 Call 方法执行并注入上下文和依赖参数
+//   - 每个事件通过 Scope 拥有独立的依赖缓存，并发事件之间互不干扰
 //
 // 本包适用于构建具有自动依赖注入能力的事件驱动系统。
 
@@ -11,11 +12,16 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"yora/pkg/event"
 	"yora/pkg/provider"
 )
 
+// 由 handler 返回，表示正常结束本匹配器的处理链，后续 handler 不再执行
+var ErrFinish = errors.New("处理链已结束")
+
 // 事件处理函数
 type Handler struct {
 	id         uintptr        // 函数的唯一标识（指针地址）
@@ -23,6 +29,8 @@ type Handler struct {
 	fnValue    reflect.Value  // 函数值（用于调用）
 	numParams  int            // 参数数量
 	paramTypes []reflect.Type // 参数类型（用于依赖匹配）
+
+	providers []provider.Provider // handler 私有依赖（优先于作用域与全局依赖，结果按 handler 缓存在作用域内）
 }
 
 // 创建一个新的 Handler 实例并注册到 Registry
@@ -54,29 +62,36 @@ func NewHandler(fn any) *Handler {
 	return handler
 }
 
-// 注册依赖到注册中心
+// 注册 handler 私有依赖
 func (h *Handler) RegisterProviders(provs ...provider.Provider) *Handler {
-	GetHandlerRegistry().RegisterProviders(provs...)
+	h.providers = append(h.providers, provs...)
 	return h
 }
 
+// 参数类型列表
+func (h *Handler) ParamTypes() []reflect.Type {
+	return h.paramTypes
+}
+
 // 执行 handler 函数
 func (h *Handler) Call(ctx context.Context, e event.Event) error {
-	reg := GetHandlerRegistry()
-
-	// 为本次调用构建参数依赖（全局缓存中如已存在则跳过）
-	if err := reg.BuildTypedValues(h.id, h.paramTypes, ctx, e); err != nil {
-		return err
+	// 未经分发器调用时（如直接测试），创建临时作用域
+	scope, ok := ScopeFromContext(ctx)
+	if !ok {
+		scope = NewScope(e)
+		ctx = WithScope(ctx, scope)
 	}
 
-	// 获取参数值并填充
+	// 获取参数值并填充（按需构建）
 	args := make([]reflect.Value, h.numParams)
 	for i, t := range h.paramTypes {
-		v, err := reg.GetTypedDependency(t)
+		v, err := h.resolve(ctx, scope, t)
 		if err != nil {
+			return fmt.Errorf("构建依赖失败 [%v]: %w", t, err)
+		}
+		if args[i], err = adaptValue(v, t); err != nil {
 			return err
 		}
-		args[i] = v
 	}
 
 	// 执行函数
@@ -84,7 +99,7 @@ func (h *Handler) Call(ctx context.Context, e event.Event) error {
 
 	// 处理最后一个返回值（如果为 error 且非 nil，则返回）
 	if len(results) > 0 {
-		if last := results[len(results)-1]; !last.IsNil() {
+		if last := results[len(results)-1]; last.Kind() == reflect.Interface && !last.IsNil() {
 			if err, ok := last.Interface().(error); ok {
 				return err
 			}
@@ -93,3 +108,11 @@ func (h *Handler) Call(ctx context.Context, e event.Event) error {
 
 	return nil
 }
+
+// 解析单个参数：私有依赖 -> 作用域
+func (h *Handler) resolve(ctx context.Context, scope *Scope, t reflect.Type) (reflect.Value, error) {
+	if v, ok := scope.providePrivate(ctx, h, t); ok {
+		return v, nil
+	}
+	return scope.Resolve(ctx, t)
+}
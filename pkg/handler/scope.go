@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"yora/pkg/event"
+	"yora/pkg/provider"
+)
+
+type scopeKey struct{}
+
+// 单次事件的依赖注入作用域
+//
+// 每个被分发的事件拥有独立的 Scope，依赖在 handler 调用时按需构建并缓存在作用域内，
+// 不同事件之间互不影响。
+type Scope struct {
+	parent    *Scope // 父作用域，读取缓存时回退
+	event     event.Event
+	providers []provider.Provider // 事件级依赖（如当前事件、上下文、Bot）
+
+	mu      sync.Mutex
+	values  map[reflect.Type]reflect.Value // 缓存：类型 -> 构造出的值（仅本事件有效）
+	memos   map[any]any                    // 缓存：键 -> 值（见 Memo）
+	own     providerCache                  // 事件级依赖的调用结果
+	global  providerCache                  // 全局动态依赖的调用结果
+	private map[*Handler]*providerCache    // handler 私有依赖的调用结果
+}
+
+// provider 调用结果，同一作用域内每个 provider 最多调用一次
+type providerCache struct {
+	called  []bool
+	results []reflect.Value // 与 provider 一一对应，返回 nil 时为无效值
+}
+
+// 创建事件作用域
+func NewScope(e event.Event, provs ...provider.Provider) *Scope {
+	return &Scope{
+		event:     e,
+		providers: provs,
+		values:    make(map[reflect.Type]reflect.Value),
+		memos:     make(map[any]any),
+		private:   make(map[*Handler]*providerCache),
+	}
+}
+
+// 派生子作用域：读取时回退到父作用域的缓存，写入只影响子作用域
+//
+// 同一事件的多个匹配器并发执行时各自使用子作用域，避免 Set 相互覆盖。
+func (s *Scope) Fork() *Scope {
+	return &Scope{
+		parent:    s,
+		event:     s.event,
+		providers: s.providers,
+		values:    make(map[reflect.Type]reflect.Value),
+		memos:     make(map[any]any),
+		private:   make(map[*Handler]*providerCache),
+	}
+}
+
+// 将作用域挂载到上下文
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// 从上下文中获取作用域
+func ScopeFromContext(ctx context.Context) (*Scope, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	s, ok := ctx.Value(scopeKey{}).(*Scope)
+	return s, ok
+}
+
+// 作用域所属事件
+func (s *Scope) Event() event.Event {
+	return s.event
+}
+
+// 手动放入依赖值（如命令解析结果），之后同类型参数直接使用该值
+func (s *Scope) Set(v any) {
+	if v == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[reflect.TypeOf(v)] = reflect.ValueOf(v)
+}
+
+// 按类型解析依赖：作用域缓存 -> 事件级依赖 -> 全局静态依赖 -> 全局动态依赖
+func (s *Scope) Resolve(ctx context.Context, t reflect.Type) (reflect.Value, error) {
+	if v, ok := s.cached(t); ok {
+		return v, nil
+	}
+
+	v, ok := s.provide(ctx, &s.own, s.providers, t)
+	if !ok {
+		v, ok = GetHandlerRegistry().static(t)
+	}
+	if !ok {
+		v, ok = s.provide(ctx, &s.global, GetHandlerRegistry().dynamic(), t)
+	}
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("未匹配到类型 [%v] 的依赖", t)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exist, ok := s.values[t]; ok {
+		return exist, nil // 并发构建时以先写入者为准
+	}
+	s.values[t] = v
+	return v, nil
+}
+
+// 从作用域缓存中查找兼容类型的值，未找到时查找父作用域
+func (s *Scope) cached(t reflect.Type) (reflect.Value, bool) {
+	if v, ok := s.ownCached(t); ok {
+		return v, true
+	}
+	if s.parent != nil {
+		return s.parent.cached(t)
+	}
+	return reflect.Value{}, false
+}
+
+func (s *Scope) ownCached(t reflect.Type) (reflect.Value, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.values[t]; ok {
+		return v, true
+	}
+	for vt, v := range s.values {
+		if isTypeCompatible(vt, t) {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// 按 key 缓存 build 的结果，同一作用域（含父作用域）内 build 只执行一次
+//
+// 用于缓存同一事件中多处需要的计算结果，如命令解析结果。
+func (s *Scope) Memo(key any, build func() any) any {
+	if v, ok := s.memo(key); ok {
+		return v
+	}
+
+	v := build()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exist, ok := s.memos[key]; ok {
+		return exist // 并发构建时以先写入者为准
+	}
+	s.memos[key] = v
+	return v
+}
+
+func (s *Scope) memo(key any) (any, bool) {
+	s.mu.Lock()
+	v, ok := s.memos[key]
+	s.mu.Unlock()
+	if !ok && s.parent != nil {
+		return s.parent.memo(key)
+	}
+	return v, ok
+}
+
+// 解析 handler 私有依赖，结果按 handler 缓存在作用域内
+func (s *Scope) providePrivate(ctx context.Context, h *Handler, t reflect.Type) (reflect.Value, bool) {
+	if len(h.providers) == 0 {
+		return reflect.Value{}, false
+	}
+	s.mu.Lock()
+	c, ok := s.private[h]
+	if !ok {
+		c = &providerCache{}
+		s.private[h] = c
+	}
+	s.mu.Unlock()
+	return s.provide(ctx, c, h.providers, t)
+}
+
+// 按顺序查找第一个类型兼容的值，尚未调用的 provider 调用一次并缓存结果
+//
+// provider 在锁外调用，允许 provider 内部再次解析其他依赖。
+func (s *Scope) provide(ctx context.Context, c *providerCache, provs []provider.Provider, t reflect.Type) (reflect.Value, bool) {
+	for i, pro := range provs {
+		s.mu.Lock()
+		if len(c.called) != len(provs) {
+			c.called, c.results = make([]bool, len(provs)), make([]reflect.Value, len(provs))
+		}
+		called, v := c.called[i], c.results[i]
+		s.mu.Unlock()
+
+		if !called {
+			if out := pro.Provide(ctx, s.event); out != nil {
+				v = reflect.ValueOf(out)
+			}
+			s.mu.Lock()
+			if len(c.called) == len(provs) {
+				c.called[i], c.results[i] = true, v
+			}
+			s.mu.Unlock()
+		}
+		if v.IsValid() && isTypeCompatible(v.Type(), t) {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// 构造参数值，ptr/非ptr 之间做必要的转换
+func adaptValue(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	vt := v.Type()
+	if vt.AssignableTo(t) {
+		return v, nil
+	}
+	if vt.Kind() == reflect.Ptr && vt.Elem() == t {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("类型 [%v] 的依赖为空指针", t)
+		}
+		return v.Elem(), nil
+	}
+	if vt.Kind() != reflect.Ptr && t.Kind() == reflect.Ptr && t.Elem() == vt {
+		p := reflect.New(vt)
+		p.Elem().Set(v)
+		return p, nil
+	}
+	return reflect.Value{}, fmt.Errorf("依赖类型 [%v] 无法转换为 [%v]", vt, t)
+}
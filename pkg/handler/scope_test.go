@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"yora/pkg/event"
+	"yora/pkg/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	id string
+}
+
+func (e *testEvent) Type() string    { return "message" }
+func (e *testEvent) SubType() string { return "" }
+func (e *testEvent) Time() time.Time { return time.Time{} }
+func (e *testEvent) SelfID() string  { return e.id }
+func (e *testEvent) Raw() any        { return e }
+
+func TestScopeIsolatesConcurrentEvents(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string]string)
+
+	h := NewHandler(func(ctx context.Context, e *testEvent) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		got[ctx.Value("want").(string)] = e.id
+	})
+
+	ids := []string{"a", "b", "c", "d"}
+	errs := make(chan error, len(ids))
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			e := &testEvent{id: id}
+			ctx := context.WithValue(context.Background(), "want", id)
+			ctx = WithScope(ctx, NewScope(e, provider.Ctx(), provider.Event()))
+			errs <- h.Call(ctx, e)
+		}(id)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	for want, id := range got {
+		assert.Equal(t, want, id)
+	}
+	assert.Len(t, got, 4)
+}
+
+func TestScopeCachesAndSet(t *testing.T) {
+	e := &testEvent{id: "x"}
+	calls := 0
+	s := NewScope(e, provider.DynamicProvider(func(ctx context.Context, e event.Event) any {
+		calls++
+		return []string{"cached"}
+	}))
+	s.Set(42)
+
+	ctx := WithScope(context.Background(), s)
+	h := NewHandler(func(a []string, b []string, n int) error {
+		assert.Equal(t, []string{"cached"}, a)
+		assert.Equal(t, 42, n)
+		return nil
+	})
+
+	require.NoError(t, h.Call(ctx, e))
+	assert.Equal(t, 1, calls)
+}
+
+func TestProvidersCalledOncePerScope(t *testing.T) {
+	e := &testEvent{id: "x"}
+	var scopeCalls, privateCalls int
+	s := NewScope(e, provider.DynamicProvider(func(ctx context.Context, e event.Event) any {
+		scopeCalls++
+		return 42
+	}))
+
+	h := NewHandler(func(a []string, b *[]string, n int, m int) {}).
+		RegisterProviders(provider.DynamicProvider(func(ctx context.Context, e event.Event) any {
+			privateCalls++
+			return []string{"private"}
+		}))
+
+	ctx := WithScope(context.Background(), s)
+	require.NoError(t, h.Call(ctx, e))
+	require.NoError(t, h.Call(ctx, e))
+	assert.Equal(t, 1, scopeCalls)
+	assert.Equal(t, 1, privateCalls)
+}
+
+func TestScopeMemo(t *testing.T) {
+	s := NewScope(&testEvent{})
+	builds := 0
+	build := func() any {
+		builds++
+		return "parsed"
+	}
+
+	assert.Equal(t, "parsed", s.Memo("key", build))
+	assert.Equal(t, "parsed", s.Fork().Memo("key", build)) // 子作用域读取父作用域的结果
+	assert.Equal(t, 1, builds)
+}
+
+func TestHandlerMissingDependency(t *testing.T) {
+	h := NewHandler(func(v *sync.Mutex) {})
+	assert.Error(t, h.Call(context.Background(), &testEvent{}))
+}
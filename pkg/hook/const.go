@@ -9,6 +9,9 @@ const (
 	BotOnStop      HookType = "bot.on_stop"
 	BotOnReload    HookType = "bot.on_reload"
 	BotHealthCheck HookType = "bot.health_check"
+
+	// 账号心跳超时，HookContext 中 "health" 为 adapter.Health
+	BotOnConnectionLost HookType = "bot.on_connection_lost"
 )
 
 // Plugin
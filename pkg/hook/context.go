@@ -17,6 +17,8 @@ type HookContext struct {
 	timestamp time.Time
 	err       error
 
+	typed any // 具体类型的上下文（BotHookContext、PluginHookContext 等）
+
 	mutex sync.RWMutex
 }
 
@@ -9,10 +9,12 @@ type PluginHookContext struct {
 
 func NewPluginHookContext(ctx context.Context, hookType HookType, plugin any) *PluginHookContext {
 	hc := NewHookContext(ctx, hookType)
-	return &PluginHookContext{
+	phc := &PluginHookContext{
 		HookContext: hc,
 		Plugin:      plugin,
 	}
+	hc.typed = phc
+	return phc
 }
 
 type BotHookContext struct {
@@ -22,21 +24,52 @@ type BotHookContext struct {
 
 func NewBotHookContext(ctx context.Context, hookType HookType, bot any) *BotHookContext {
 	hc := NewHookContext(ctx, hookType)
-	return &BotHookContext{
+	bhc := &BotHookContext{
 		HookContext: hc,
 		Bot:         bot,
 	}
+	hc.typed = bhc
+	return bhc
 }
 
 type MessageHookContext struct {
 	*HookContext
-	Message any // 消息实例
+	Message any    // 消息实例
+	Event   any    // 触发事件（发送消息时为来源事件，可能为空）
+	UserID  string // 发送目标用户ID
+	GroupID string // 发送目标群ID
 }
 
 func NewMessageHookContext(ctx context.Context, hookType HookType, message any) *MessageHookContext {
 	hc := NewHookContext(ctx, hookType)
-	return &MessageHookContext{
+	mhc := &MessageHookContext{
 		HookContext: hc,
 		Message:     message,
 	}
+	hc.typed = mhc
+	return mhc
+}
+
+type MatcherHookContext struct {
+	*HookContext
+	Matcher any   // 匹配器实例
+	Event   any   // 触发事件
+	Err     error // 处理器返回的错误（MatcherOnError）
+}
+
+func NewMatcherHookContext(ctx context.Context, hookType HookType, matcher any, event any) *MatcherHookContext {
+	hc := NewHookContext(ctx, hookType)
+	mhc := &MatcherHookContext{
+		HookContext: hc,
+		Matcher:     matcher,
+		Event:       event,
+	}
+	hc.typed = mhc
+	return mhc
+}
+
+// As 获取 Hook 上下文对应的具体类型，如 hook.As[*hook.MessageHookContext](ctx)
+func As[T any](hc *HookContext) (T, bool) {
+	t, ok := hc.typed.(T)
+	return t, ok
 }
@@ -103,6 +103,13 @@ func (hm *HookManager) RemoveAllHooks(hookType HookType) {
 	delete(hm.hooks, hookType)
 }
 
+// Clear 移除所有类型的Hook
+func (hm *HookManager) Clear() {
+	hm.mutex.Lock()
+	defer hm.mutex.Unlock()
+	hm.hooks = make(map[HookType][]*HookInfo)
+}
+
 // DisableHook 禁用Hook类型
 func (hm *HookManager) DisableHook(hookType HookType) {
 	hm.mutex.Lock()
@@ -0,0 +1,109 @@
+package message
+
+import "strings"
+
+var _ Message = Segments{}
+var _ Segment = (*basicSegment)(nil)
+
+// 通用消息段，由适配器按类型转换为协议消息段
+type basicSegment struct {
+	typ  string
+	data map[string]any
+}
+
+// NewSegment 创建通用消息段
+func NewSegment(segmentType string, data map[string]any) Segment {
+	if data == nil {
+		data = make(map[string]any)
+	}
+	return &basicSegment{typ: segmentType, data: data}
+}
+
+func (s *basicSegment) Type() string {
+	return s.typ
+}
+
+func (s *basicSegment) Data() map[string]any {
+	return s.data
+}
+
+func (s *basicSegment) String() string {
+	switch s.typ {
+	case TypeText, TypeQuote:
+		return StringData(s, "text")
+	case TypeAt:
+		return "@" + StringData(s, "user_id")
+	case TypeCode:
+		return StringData(s, "code")
+	}
+	return ""
+}
+
+func (s *basicSegment) IsType(segmentType string) bool {
+	return s.typ == segmentType
+}
+
+func (s *basicSegment) GetData(key string) (any, bool) {
+	v, ok := s.data[key]
+	return v, ok
+}
+
+// Segments 通用消息，由多个消息段组成
+type Segments []Segment
+
+// New 创建通用消息
+func New(segments ...Segment) Segments {
+	return Segments(segments)
+}
+
+// Text 创建纯文本消息
+func Text(text string) Segments {
+	return Segments{NewTextSegment(text)}
+}
+
+func (m Segments) Segments() []Segment {
+	result := make([]Segment, len(m))
+	copy(result, m)
+	return result
+}
+
+func (m Segments) String() string {
+	var b strings.Builder
+	for _, seg := range m {
+		b.WriteString(seg.String())
+	}
+	return b.String()
+}
+
+func (m Segments) PlainText() string {
+	var b strings.Builder
+	for _, seg := range m {
+		if seg.IsType(TypeText) {
+			b.WriteString(seg.String())
+		}
+	}
+	return b.String()
+}
+
+func (m Segments) IsEmpty() bool {
+	return len(m) == 0
+}
+
+func (m Segments) HasType(segmentType string) bool {
+	for _, seg := range m {
+		if seg.IsType(segmentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Segments) GetSegmentsByType(segmentType string) []Segment {
+	var result []Segment
+	for _, seg := range m {
+		if seg.IsType(segmentType) {
+			result = append(result, seg)
+		}
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package message
+
+// Builder 通用消息构建器
+//
+//	msg := message.NewBuilder().Reply(id).At(uid).Text(" 你好").Build()
+type Builder struct {
+	segments Segments
+}
+
+// NewBuilder 创建消息构建器
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Segment 添加任意消息段
+func (b *Builder) Segment(segs ...Segment) *Builder {
+	b.segments = append(b.segments, segs...)
+	return b
+}
+
+// Message 添加消息中的所有消息段
+func (b *Builder) Message(msg Message) *Builder {
+	if msg == nil {
+		return b
+	}
+	return b.Segment(msg.Segments()...)
+}
+
+// Text 添加文本
+func (b *Builder) Text(text string) *Builder {
+	return b.Segment(NewTextSegment(text))
+}
+
+// Image 添加图片
+func (b *Builder) Image(file string) *Builder {
+	return b.Segment(NewImageSegment(file))
+}
+
+// Audio 添加语音
+func (b *Builder) Audio(file string) *Builder {
+	return b.Segment(NewAudioSegment(file))
+}
+
+// Video 添加视频
+func (b *Builder) Video(file string) *Builder {
+	return b.Segment(NewVideoSegment(file))
+}
+
+// File 添加文件
+func (b *Builder) File(file, name string) *Builder {
+	return b.Segment(NewFileSegment(file, name))
+}
+
+// At 添加 @
+func (b *Builder) At(userID string) *Builder {
+	return b.Segment(NewAtSegment(userID))
+}
+
+// AtAll 添加 @全体成员
+func (b *Builder) AtAll() *Builder {
+	return b.Segment(NewAtAllSegment())
+}
+
+// Emoji 添加平台表情
+func (b *Builder) Emoji(id string) *Builder {
+	return b.Segment(NewEmojiSegment(id))
+}
+
+// Reply 添加回复
+func (b *Builder) Reply(messageID string) *Builder {
+	return b.Segment(NewReplySegment(messageID))
+}
+
+// Forward 添加合并转发
+func (b *Builder) Forward(id string) *Builder {
+	return b.Segment(NewForwardSegment(id))
+}
+
+// Location 添加位置
+func (b *Builder) Location(lat, lon float64, title, content string) *Builder {
+	return b.Segment(NewLocationSegment(lat, lon, title, content))
+}
+
+// Contact 添加联系人分享
+func (b *Builder) Contact(contactType, id string) *Builder {
+	return b.Segment(NewContactSegment(contactType, id))
+}
+
+// Link 添加链接分享
+func (b *Builder) Link(url, title, content, image string) *Builder {
+	return b.Segment(NewLinkSegment(url, title, content, image))
+}
+
+// Code 添加代码块
+func (b *Builder) Code(language, code string) *Builder {
+	return b.Segment(NewCodeSegment(language, code))
+}
+
+// Quote 添加引用文本
+func (b *Builder) Quote(text string) *Builder {
+	return b.Segment(NewQuoteSegment(text))
+}
+
+// Build 生成消息
+func (b *Builder) Build() Segments {
+	return b.segments.Segments()
+}
@@ -0,0 +1,34 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	msg := NewBuilder().
+		Reply("1").
+		At("2").
+		Text(" 你好").
+		Image("https://example.com/a.png").
+		Code("go", "fmt.Println()").
+		Build()
+
+	segs := msg.Segments()
+	require.Len(t, segs, 5)
+	assert.Equal(t, TypeReply, segs[0].Type())
+	assert.Equal(t, "2", StringData(segs[1], "user_id"))
+	assert.Equal(t, "https://example.com/a.png", StringData(segs[3], "file"))
+	assert.Equal(t, " 你好", msg.PlainText())
+	assert.Equal(t, "@2 你好fmt.Println()", msg.String())
+	assert.True(t, msg.HasType(TypeCode))
+}
+
+func TestStringData(t *testing.T) {
+	seg := NewLocationSegment(1.5, 2, "家", "")
+	assert.Equal(t, "1.5", StringData(seg, "lat"))
+	assert.Equal(t, "", StringData(seg, "missing"))
+	assert.Equal(t, AtAll, StringData(NewAtAllSegment(), "user_id"))
+}
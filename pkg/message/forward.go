@@ -0,0 +1,13 @@
+package message
+
+// ForwardNode 合并转发中的一条消息，由适配器转换为协议的转发节点
+type ForwardNode struct {
+	UserID   string  // 显示的发送者ID
+	Nickname string  // 显示的发送者昵称
+	Content  Message // 消息内容
+}
+
+// NewForwardNode 创建合并转发节点
+func NewForwardNode(userID, nickname string, content Message) ForwardNode {
+	return ForwardNode{UserID: userID, Nickname: nickname, Content: content}
+}
@@ -0,0 +1,129 @@
+package message
+
+import "fmt"
+
+// 标准消息段类型，各适配器负责与协议消息段互相转换
+const (
+	TypeText     = "text"     // text
+	TypeImage    = "image"    // file、name
+	TypeAudio    = "audio"    // file、name
+	TypeVideo    = "video"    // file、name
+	TypeFile     = "file"     // file、name
+	TypeAt       = "at"       // user_id，@全体成员时为 "all"
+	TypeEmoji    = "emoji"    // id
+	TypeReply    = "reply"    // id
+	TypeForward  = "forward"  // id
+	TypeLocation = "location" // lat、lon、title、content
+	TypeContact  = "contact"  // type（user 或 group）、id
+	TypeLink     = "link"     // url、title、content、image
+	TypeCode     = "code"     // language、code
+	TypeQuote    = "quote"    // text
+)
+
+// AtAll @全体成员时 at 消息段的 user_id
+const AtAll = "all"
+
+// 联系人类型
+const (
+	ContactUser  = "user"
+	ContactGroup = "group"
+)
+
+// 媒体消息段的 file 可以是 URL、本地路径（file://）或 base64://
+
+// NewTextSegment 创建文本消息段
+func NewTextSegment(text string) Segment {
+	return NewSegment(TypeText, map[string]any{"text": text})
+}
+
+// NewImageSegment 创建图片消息段
+func NewImageSegment(file string) Segment {
+	return NewSegment(TypeImage, map[string]any{"file": file})
+}
+
+// NewAudioSegment 创建语音消息段
+func NewAudioSegment(file string) Segment {
+	return NewSegment(TypeAudio, map[string]any{"file": file})
+}
+
+// NewVideoSegment 创建视频消息段
+func NewVideoSegment(file string) Segment {
+	return NewSegment(TypeVideo, map[string]any{"file": file})
+}
+
+// NewFileSegment 创建文件消息段，name 为显示的文件名
+func NewFileSegment(file, name string) Segment {
+	return NewSegment(TypeFile, map[string]any{"file": file, "name": name})
+}
+
+// NewAtSegment 创建 @ 消息段
+func NewAtSegment(userID string) Segment {
+	return NewSegment(TypeAt, map[string]any{"user_id": userID})
+}
+
+// NewAtAllSegment 创建 @全体成员 消息段
+func NewAtAllSegment() Segment {
+	return NewAtSegment(AtAll)
+}
+
+// NewEmojiSegment 创建平台表情消息段
+func NewEmojiSegment(id string) Segment {
+	return NewSegment(TypeEmoji, map[string]any{"id": id})
+}
+
+// NewReplySegment 创建回复消息段
+func NewReplySegment(messageID string) Segment {
+	return NewSegment(TypeReply, map[string]any{"id": messageID})
+}
+
+// NewForwardSegment 创建合并转发消息段
+func NewForwardSegment(id string) Segment {
+	return NewSegment(TypeForward, map[string]any{"id": id})
+}
+
+// NewLocationSegment 创建位置消息段
+func NewLocationSegment(lat, lon float64, title, content string) Segment {
+	return NewSegment(TypeLocation, map[string]any{
+		"lat":     lat,
+		"lon":     lon,
+		"title":   title,
+		"content": content,
+	})
+}
+
+// NewContactSegment 创建联系人分享消息段，contactType 为 ContactUser 或 ContactGroup
+func NewContactSegment(contactType, id string) Segment {
+	return NewSegment(TypeContact, map[string]any{"type": contactType, "id": id})
+}
+
+// NewLinkSegment 创建链接分享消息段
+func NewLinkSegment(url, title, content, image string) Segment {
+	return NewSegment(TypeLink, map[string]any{
+		"url":     url,
+		"title":   title,
+		"content": content,
+		"image":   image,
+	})
+}
+
+// NewCodeSegment 创建代码块消息段
+func NewCodeSegment(language, code string) Segment {
+	return NewSegment(TypeCode, map[string]any{"language": language, "code": code})
+}
+
+// NewQuoteSegment 创建引用文本消息段
+func NewQuoteSegment(text string) Segment {
+	return NewSegment(TypeQuote, map[string]any{"text": text})
+}
+
+// StringData 读取消息段中的字符串字段，非字符串值按 fmt.Sprint 转换
+func StringData(seg Segment, key string) string {
+	v, ok := seg.GetData(key)
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
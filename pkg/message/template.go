@@ -0,0 +1,169 @@
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// 消息段占位符的起止标记（Unicode 私用区字符），占位符中带有每次渲染随机生成的 nonce，
+// 数据中的同名字符不会被误识别为消息段
+const (
+	markerStart = "\uE000"
+	markerEnd   = "\uE001"
+)
+
+// Template 消息模板，基于 text/template，渲染结果为消息段列表
+//
+//	t := message.MustParseTemplate("roll", "{{at .UserID}} 掷出了 {{.N}} {{image .URL}}")
+//	msg, err := t.Render(data)
+//
+// 除 text/template 内置函数外，可使用以下函数插入消息段：
+//
+//	at、atAll、image、audio、video、file、emoji（face）、reply、forward、link、code、quote、
+//	seg（插入 Segment 或 Message）
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate 解析消息模板
+func ParseTemplate(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(segmentFuncs(nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板 %s 失败: %w", name, err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// MustParseTemplate 解析消息模板，失败时 panic
+func MustParseTemplate(name, text string) *Template {
+	t, err := ParseTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name 模板名
+func (t *Template) Name() string {
+	return t.tmpl.Name()
+}
+
+// Render 渲染模板为消息，相邻文本合并，空文本丢弃
+func (t *Template) Render(data any) (Segments, error) {
+	r := &render{nonce: strconv.FormatUint(rand.Uint64(), 36)}
+
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("渲染模板 %s 失败: %w", t.Name(), err)
+	}
+	tmpl.Funcs(segmentFuncs(r))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("渲染模板 %s 失败: %w", t.Name(), err)
+	}
+	return r.split(buf.String()), nil
+}
+
+// 单次渲染的状态，记录模板函数生成的消息段
+type render struct {
+	nonce    string
+	segments []Segment
+}
+
+// 记录消息段并返回占位符
+func (r *render) add(segs ...Segment) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		b.WriteString(markerStart + r.nonce + "#" + strconv.Itoa(len(r.segments)) + markerEnd)
+		r.segments = append(r.segments, seg)
+	}
+	return b.String()
+}
+
+// 将渲染结果按占位符拆分为消息段
+func (r *render) split(out string) Segments {
+	var result Segments
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			result = append(result, NewTextSegment(text.String()))
+			text.Reset()
+		}
+	}
+
+	prefix := markerStart + r.nonce + "#"
+	for {
+		i := strings.Index(out, prefix)
+		if i < 0 {
+			break
+		}
+		end := strings.Index(out[i+len(prefix):], markerEnd)
+		if end < 0 {
+			break
+		}
+		idx, err := strconv.Atoi(out[i+len(prefix) : i+len(prefix)+end])
+		if err != nil || idx < 0 || idx >= len(r.segments) {
+			// 不是本次渲染生成的占位符，按文本处理
+			text.WriteString(out[:i+len(prefix)])
+			out = out[i+len(prefix):]
+			continue
+		}
+
+		text.WriteString(out[:i])
+		seg := r.segments[idx]
+		if seg.IsType(TypeText) {
+			text.WriteString(StringData(seg, "text"))
+		} else {
+			flush()
+			result = append(result, seg)
+		}
+		out = out[i+len(prefix)+end+len(markerEnd):]
+	}
+	text.WriteString(out)
+	flush()
+	return result
+}
+
+// 模板中可用的消息段函数，r 为空时仅用于解析
+func segmentFuncs(r *render) template.FuncMap {
+	str := func(v any) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+	emoji := func(id any) string { return r.add(NewEmojiSegment(str(id))) }
+
+	return template.FuncMap{
+		"at":      func(userID any) string { return r.add(NewAtSegment(str(userID))) },
+		"atAll":   func() string { return r.add(NewAtAllSegment()) },
+		"image":   func(file any) string { return r.add(NewImageSegment(str(file))) },
+		"audio":   func(file any) string { return r.add(NewAudioSegment(str(file))) },
+		"video":   func(file any) string { return r.add(NewVideoSegment(str(file))) },
+		"file":    func(file, name any) string { return r.add(NewFileSegment(str(file), str(name))) },
+		"emoji":   emoji,
+		"face":    emoji,
+		"reply":   func(id any) string { return r.add(NewReplySegment(str(id))) },
+		"forward": func(id any) string { return r.add(NewForwardSegment(str(id))) },
+		"link":    func(url, title any) string { return r.add(NewLinkSegment(str(url), str(title), "", "")) },
+		"code":    func(language, code any) string { return r.add(NewCodeSegment(str(language), str(code))) },
+		"quote":   func(text any) string { return r.add(NewQuoteSegment(str(text))) },
+		"seg": func(v any) (string, error) {
+			switch v := v.(type) {
+			case Segment:
+				return r.add(v), nil
+			case Message:
+				return r.add(v.Segments()...), nil
+			case nil:
+				return "", nil
+			}
+			return "", fmt.Errorf("seg: 不支持的类型 %T", v)
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package message
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tmpl := MustParseTemplate("roll", "{{at .UserID}} 你掷出了 {{.N}} {{image .URL}}{{if .Lucky}}{{face 14}}{{end}}")
+
+	msg, err := tmpl.Render(map[string]any{"UserID": 10, "N": 6, "URL": "https://example.com/6.png", "Lucky": true})
+	require.NoError(t, err)
+
+	segs := msg.Segments()
+	require.Len(t, segs, 4)
+	assert.Equal(t, "10", StringData(segs[0], "user_id"))
+	assert.Equal(t, " 你掷出了 6 ", StringData(segs[1], "text"))
+	assert.Equal(t, "https://example.com/6.png", StringData(segs[2], "file"))
+	assert.Equal(t, TypeEmoji, segs[3].Type())
+	assert.Equal(t, "14", StringData(segs[3], "id"))
+}
+
+func TestTemplateEscaping(t *testing.T) {
+	tmpl := MustParseTemplate("echo", "{{.}}")
+
+	// 数据中的 CQ 码、模板语法和占位符字符都只是文本
+	raw := "[CQ:at,qq=all] {{at 1}} \uE000x#0\uE001"
+	msg, err := tmpl.Render(raw)
+	require.NoError(t, err)
+	require.Len(t, msg, 1)
+	assert.Equal(t, raw, msg.PlainText())
+}
+
+func TestTemplateSeg(t *testing.T) {
+	tmpl := MustParseTemplate("seg", "前{{seg .Msg}}后")
+	msg, err := tmpl.Render(map[string]any{"Msg": NewBuilder().Text("中").At("1").Build()})
+	require.NoError(t, err)
+	require.Len(t, msg, 3)
+	assert.Equal(t, "前中", msg[0].String())
+	assert.Equal(t, "@1后", msg.String()[len("前中"):])
+
+	_, err = tmpl.Render(map[string]any{"Msg": 1})
+	assert.Error(t, err)
+
+	_, err = ParseTemplate("bad", "{{at}")
+	assert.Error(t, err)
+}
+
+func TestTemplatesLocale(t *testing.T) {
+	fsys := fstest.MapFS{
+		"roll.tmpl":       {Data: []byte("{{at .}} 掷骰子\n")},
+		"en/roll.tmpl":    {Data: []byte("{{at .}} rolled\n")},
+		"zh-TW/roll.tmpl": {Data: []byte("{{at .}} 擲骰子\n")},
+		"README.md":       {Data: []byte("ignored")},
+	}
+	ts := NewTemplates()
+	require.NoError(t, ts.LoadFS(fsys))
+
+	cases := map[string]string{
+		"":      " 掷骰子",
+		"en-US": " rolled",
+		"zh_tw": " 擲骰子",
+		"ja":    " 掷骰子",
+	}
+	for locale, want := range cases {
+		msg, err := ts.Render(locale, "roll", "1")
+		require.NoError(t, err, locale)
+		assert.Equal(t, want, msg.PlainText(), locale)
+	}
+
+	ts.SetDefaultLocale("en")
+	msg, err := ts.Render("ja", "roll", "1")
+	require.NoError(t, err)
+	assert.Equal(t, " rolled", msg.PlainText())
+
+	_, err = ts.Render("", "missing", nil)
+	assert.Error(t, err)
+
+	// 目录不存在时忽略
+	assert.NoError(t, ts.LoadDir(t.TempDir()+"/missing"))
+}
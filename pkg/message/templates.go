@@ -0,0 +1,157 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// TemplateExt 模板文件扩展名
+const TemplateExt = ".tmpl"
+
+// Templates 按语言区域管理的一组消息模板，通常每个插件一组
+//
+// 从目录加载时，根目录下的 <name>.tmpl 为默认模板，<locale>/<name>.tmpl 为对应语言的模板：
+//
+//	templates/
+//	  roll.tmpl
+//	  en/roll.tmpl
+//	  zh-TW/roll.tmpl
+//
+// 渲染时依次查找 zh-TW、zh、默认语言区域，最后使用默认模板
+type Templates struct {
+	mu            sync.RWMutex
+	templates     map[string]map[string]*Template // locale -> name -> 模板，默认模板的 locale 为空
+	defaultLocale string
+}
+
+// NewTemplates 创建模板集
+func NewTemplates() *Templates {
+	return &Templates{
+		templates: make(map[string]map[string]*Template),
+	}
+}
+
+// SetDefaultLocale 设置默认语言区域，找不到请求的语言时使用
+func (ts *Templates) SetDefaultLocale(locale string) *Templates {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.defaultLocale = normalizeLocale(locale)
+	return ts
+}
+
+// Add 添加模板，locale 为空时为默认模板，同名模板会被覆盖
+func (ts *Templates) Add(locale, name, text string) error {
+	t, err := ParseTemplate(name, text)
+	if err != nil {
+		return err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	locale = normalizeLocale(locale)
+	if ts.templates[locale] == nil {
+		ts.templates[locale] = make(map[string]*Template)
+	}
+	ts.templates[locale][name] = t
+	return nil
+}
+
+// LoadFS 从文件系统加载 .tmpl 模板，可用于 embed.FS 中的内置模板
+func (ts *Templates) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != TemplateExt {
+			return nil
+		}
+
+		var locale string
+		dir, file := path.Split(p)
+		if dir = strings.Trim(dir, "/"); dir != "" {
+			if strings.Contains(dir, "/") {
+				return nil // 只支持一层语言目录
+			}
+			locale = dir
+		}
+
+		raw, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("读取模板 %s 失败: %w", p, err)
+		}
+		// 去掉编辑器在文件末尾添加的换行
+		text := strings.TrimSuffix(strings.TrimSuffix(string(raw), "\n"), "\r")
+		return ts.Add(locale, strings.TrimSuffix(file, TemplateExt), text)
+	})
+}
+
+// LoadDir 从目录加载模板，覆盖同名模板，目录不存在时忽略
+// 可先用 LoadFS 加载内置模板，再从配置目录加载社区修改的版本
+func (ts *Templates) LoadDir(dir string) error {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return ts.LoadFS(os.DirFS(dir))
+}
+
+// Lookup 按语言区域查找模板
+func (ts *Templates) Lookup(locale, name string) (*Template, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	for _, l := range ts.candidates(locale) {
+		if t, ok := ts.templates[l][name]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Render 使用对应语言区域的模板渲染消息
+func (ts *Templates) Render(locale, name string, data any) (Segments, error) {
+	t, ok := ts.Lookup(locale, name)
+	if !ok {
+		return nil, fmt.Errorf("模板 %s 不存在", name)
+	}
+	return t.Render(data)
+}
+
+// 查找顺序：完整语言区域、语言、默认语言区域、默认模板
+func (ts *Templates) candidates(locale string) []string {
+	var result []string
+	add := func(l string) {
+		for _, v := range result {
+			if v == l {
+				return
+			}
+		}
+		result = append(result, l)
+	}
+
+	for _, l := range []string{normalizeLocale(locale), ts.defaultLocale} {
+		if l == "" {
+			continue
+		}
+		add(l)
+		if lang, _, ok := strings.Cut(l, "-"); ok {
+			add(lang)
+		}
+	}
+	add("")
+	return result
+}
+
+// 统一语言区域格式：zh_CN、zh-cn 均转为 zh-CN
+func normalizeLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	lang, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"yora/pkg/event"
+	"yora/pkg/message"
+)
+
+// Outgoing 待发送的消息
+type Outgoing struct {
+	UserID  string                // 目标用户ID（群消息时为 "0" 或空）
+	GroupID string                // 目标群ID（私聊时为 "0" 或空）
+	Message message.Message       // 消息内容，中间件可直接替换（合并转发时为空消息）
+	Forward []message.ForwardNode // 合并转发节点，非空时按合并转发发送，中间件可改写节点内容
+	Event   event.Event           // 来源事件，主动发送时为空
+}
+
+// 是否发往群聊
+func (o *Outgoing) IsGroup() bool {
+	return o.GroupID != "" && o.GroupID != "0"
+}
+
+// 是否为合并转发消息
+func (o *Outgoing) IsForward() bool {
+	return len(o.Forward) > 0
+}
+
+// SendFunc 发送函数，返回消息ID
+type SendFunc func(ctx context.Context, out *Outgoing) (string, error)
+
+// OutboundMiddleware 出站中间件，在消息到达适配器之前执行
+//
+// 中间件可以检查、改写（修改 out.Message）、延迟消息，或不调用 next 直接返回以丢弃消息。
+type OutboundMiddleware interface {
+	// Send 中间件处理函数
+	Send(ctx context.Context, out *Outgoing, next SendFunc) (string, error)
+
+	// Name 中间件名称
+	Name() string
+}
+
+type outboundFuncWrapper struct {
+	name string
+	fn   func(ctx context.Context, out *Outgoing, next SendFunc) (string, error)
+}
+
+func (m outboundFuncWrapper) Send(ctx context.Context, out *Outgoing, next SendFunc) (string, error) {
+	id, err := m.fn(ctx, out, next)
+	if err != nil {
+		logger.Error().Err(err).Str("middleware", m.name).Msg("出站中间件处理失败")
+	}
+	return id, err
+}
+
+func (m outboundFuncWrapper) Name() string {
+	return m.name
+}
+
+// 将普通函数转为出站中间件接口
+func OutboundFunc(name string, fn func(ctx context.Context, out *Outgoing, next SendFunc) (string, error)) OutboundMiddleware {
+	return outboundFuncWrapper{
+		name: name,
+		fn:   fn,
+	}
+}
+
+// 构建出站中间件链 并返回最终发送函数
+func ChainOutbound(middlewares []OutboundMiddleware, final SendFunc) SendFunc {
+	current := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		m := middlewares[i]
+		next := current
+		current = func(ctx context.Context, out *Outgoing) (string, error) {
+			return m.Send(ctx, out, next)
+		}
+	}
+	return current
+}
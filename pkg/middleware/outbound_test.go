@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/message"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainOutboundRewritesAndDrops(t *testing.T) {
+	var sent []string
+	final := func(ctx context.Context, out *Outgoing) (string, error) {
+		sent = append(sent, out.Message.PlainText())
+		return "1", nil
+	}
+
+	var order []string
+	trace := func(name string) OutboundMiddleware {
+		return OutboundFunc(name, func(ctx context.Context, out *Outgoing, next SendFunc) (string, error) {
+			order = append(order, name)
+			out.Message = message.Text(out.Message.PlainText() + name)
+			return next(ctx, out)
+		})
+	}
+	drop := OutboundFunc("drop", func(ctx context.Context, out *Outgoing, next SendFunc) (string, error) {
+		if out.Message.PlainText() == "secret" {
+			return "", nil
+		}
+		return next(ctx, out)
+	})
+
+	send := ChainOutbound([]OutboundMiddleware{drop, trace("a"), trace("b")}, final)
+
+	id, err := send(context.Background(), &Outgoing{GroupID: "1", Message: message.Text("hi ")})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+	assert.Equal(t, []string{"a", "b"}, order)
+
+	id, err = send(context.Background(), &Outgoing{GroupID: "1", Message: message.Text("secret")})
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+	assert.Equal(t, []string{"hi ab"}, sent)
+}
@@ -1,9 +1,12 @@
 package on
 
 import (
+	"context"
+	"yora/pkg/command"
+	"yora/pkg/event"
 	"yora/pkg/handler"
+	"yora/pkg/log"
 	"yora/pkg/plugin"
-	"yora/pkg/provider"
 	"yora/pkg/rule"
 )
 
@@ -24,6 +27,21 @@ func OnNotice(handler *handler.Handler) *plugin.Matcher {
 	return plugin.NewMatcher(rule.IsNoticeEvent(), handler)
 }
 
+// 指定类型的通知，如 OnNoticeType("group_increase", h)
+func OnNoticeType(noticeType string, handler *handler.Handler) *plugin.Matcher {
+	return plugin.NewMatcher(rule.IsNoticeType(noticeType), handler)
+}
+
+// 指定类型与子类型的通知，如 OnNoticeSubType("notify", "poke", h)
+func OnNoticeSubType(noticeType string, subType string, handler *handler.Handler) *plugin.Matcher {
+	return plugin.NewMatcher(rule.IsNoticeType(noticeType, subType), handler)
+}
+
+// 指定具体类型的通知，如 OnNoticeOf[*events.PokeNotice](h)，处理器可直接注入该类型
+func OnNoticeOf[T event.NoticeEvent](handler *handler.Handler) *plugin.Matcher {
+	return plugin.NewMatcher(rule.IsEventOf[T](), handler)
+}
+
 func OnRequest(handler *handler.Handler) *plugin.Matcher {
 	return plugin.NewMatcher(rule.IsRequestEvent(), handler)
 }
@@ -49,9 +67,14 @@ func OnKeyword(keyword string, handler *handler.Handler) *plugin.Matcher {
 	return plugin.NewMatcher(rule.Keyword(keyword), handler)
 }
 
+// 命令，cmds[0] 为命令名，其余为别名；未指定命令名时记录错误，返回的匹配器不会被触发
 func OnCommand(cmds []string, caseSensitive bool, handler *handler.Handler) *plugin.Matcher {
-	handler.RegisterProviders(provider.CommandArgs(cmds))
-	return plugin.NewMatcher(rule.Command(caseSensitive, cmds...), handler)
+	if len(cmds) == 0 {
+		logger := log.NewMatcher("OnCommand")
+		logger.Error().Msg("未指定命令名，匹配器不会被触发")
+		return plugin.NewMatcher(rule.RuleFunc(func(context.Context, event.Event) bool { return false }), handler)
+	}
+	return onCommand(&command.Command{Name: cmds[0], Aliases: cmds[1:], Passthrough: true}, caseSensitive, handler)
 }
 
 func OnRegex(pattern string, handler *handler.Handler) *plugin.Matcher {
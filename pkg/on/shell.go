@@ -0,0 +1,107 @@
+package on
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"yora/pkg/command"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/plugin"
+	"yora/pkg/provider"
+	"yora/pkg/replier"
+	"yora/pkg/rule"
+)
+
+// OnShellCommand 根据命令树声明匹配器（命令名区分大小写）
+//
+// 命中命令名或别名后先解析消息，解析结果 *command.ParseResult 可注入到 handler；
+// handler 中带有 arg/opt 标签的结构体指针参数会自动绑定并校验（命令未定义参数时由该结构体生成）。
+// 解析或校验失败时自动向用户回复错误信息与命令用法，不再执行 handler。
+func OnShellCommand(cmd *command.Command, h *handler.Handler) *plugin.Matcher {
+	return onCommand(cmd, true, h)
+}
+
+// 命令匹配器：规则、依赖注入与错误回复使用同一个解析器
+func onCommand(cmd *command.Command, caseSensitive bool, h *handler.Handler) *plugin.Matcher {
+	var bindTypes []reflect.Type
+	for _, t := range h.ParamTypes() {
+		if command.IsBindable(t) {
+			bindTypes = append(bindTypes, t)
+		}
+	}
+
+	if len(bindTypes) > 0 && len(cmd.Args) == 0 && len(cmd.Options) == 0 {
+		args, opts, err := command.FromStruct(bindTypes[0])
+		if err != nil {
+			panic(fmt.Sprintf("OnShellCommand: %v", err))
+		}
+		cmd.Args, cmd.Options = args, opts
+		cmd.Passthrough = false // 按生成的参数与选项解析，否则位置参数不会绑定
+	}
+
+	parser := command.NewParser("").SetIgnoreCase(!caseSensitive)
+	parser.Register(cmd)
+
+	parse := memoParse(parser)
+	h.RegisterProviders(provider.CommandResult(parse), provider.CommandArgs(parse))
+	for _, t := range bindTypes {
+		h.RegisterProviders(provider.CommandStruct(parse, t))
+	}
+
+	// 解析或绑定失败时回复用法并结束
+	guard := handler.NewHandler(func(ctx context.Context, e event.MessageEvent) error {
+		result, err := parse(ctx, e)
+		if err != nil {
+			replyUsage(ctx, e, cmd, err)
+			return handler.ErrFinish
+		}
+		for _, t := range bindTypes {
+			if err := result.Bind(reflect.New(t.Elem()).Interface()); err != nil {
+				replyUsage(ctx, e, cmd, &command.ParseError{Command: result.Command, Err: err})
+				return handler.ErrFinish
+			}
+		}
+		return nil
+	})
+
+	return plugin.NewMatcher(rule.CommandParser(parser), guard, h).SetCommand(cmd)
+}
+
+// 解析结果
+type parsed struct {
+	result *command.ParseResult
+	err    error
+}
+
+// 同一事件作用域内只解析一次，守卫与各依赖共用解析结果
+func memoParse(parser *command.Parser) provider.CommandParseFunc {
+	return func(ctx context.Context, e event.Event) (*command.ParseResult, error) {
+		scope, ok := handler.ScopeFromContext(ctx)
+		if !ok {
+			return provider.ParseCommand(parser, e)
+		}
+		p := scope.Memo(parser, func() any {
+			result, err := provider.ParseCommand(parser, e)
+			return parsed{result, err}
+		}).(parsed)
+		return p.result, p.err
+	}
+}
+
+// 回复解析错误与出错命令的用法
+func replyUsage(ctx context.Context, e event.MessageEvent, root *command.Command, err error) {
+	target := root
+	var pe *command.ParseError
+	if errors.As(err, &pe) && pe.Command != nil {
+		target = pe.Command
+	}
+
+	r, rerr := replier.New(ctx, e)
+	if rerr != nil {
+		return
+	}
+	r.Reply(message.Text(fmt.Sprintf("%v\n%s", err, target.Usage())))
+}
@@ -0,0 +1,99 @@
+package on
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/command"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/params"
+	"yora/pkg/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessageEvent struct {
+	event.MessageEvent
+	msg message.Message
+}
+
+func (e *fakeMessageEvent) Type() string             { return "message" }
+func (e *fakeMessageEvent) Message() message.Message { return e.msg }
+func (e *fakeMessageEvent) RawMessage() string       { return e.msg.String() }
+func (e *fakeMessageEvent) SelfID() string           { return "10000" }
+func (e *fakeMessageEvent) UserID() string           { return "1001" }
+func (e *fakeMessageEvent) ChatID() string           { return "2002" }
+func (e *fakeMessageEvent) IsGroup() bool            { return true }
+func (e *fakeMessageEvent) PlainText() string        { return e.msg.PlainText() }
+
+type banArgs struct {
+	User    string `arg:"0,at"`
+	Minutes int    `opt:"t,time" default:"10"`
+}
+
+func call(t *testing.T, e event.Event, match func(context.Context, event.Event) bool, call func(context.Context, event.Event) error) bool {
+	ctx := handler.WithScope(context.Background(), handler.NewScope(e, provider.Ctx(), provider.MessageEvent()))
+	if !match(ctx, e) {
+		return false
+	}
+	require.NoError(t, call(ctx, e))
+	return true
+}
+
+func TestShellCommandInjectsParseResult(t *testing.T) {
+	var result *command.ParseResult
+	var args *banArgs
+	m := OnShellCommand(&command.Command{Name: "ban"}, handler.NewHandler(func(r *command.ParseResult, a *banArgs) {
+		result, args = r, a
+	}))
+
+	// 回复消息段不影响匹配，规则与解析器使用同样的切分
+	e := &fakeMessageEvent{msg: message.New(
+		message.NewReplySegment("1"),
+		message.NewTextSegment("ban "),
+		message.NewAtSegment("123"),
+		message.NewTextSegment(" -t 5"),
+	)}
+	require.True(t, call(t, e, m.Match, func(ctx context.Context, e event.Event) error { return m.Call(ctx, e) }))
+	require.NotNil(t, result)
+	assert.Equal(t, "ban", result.Command.Name)
+	assert.Equal(t, &banArgs{User: "123", Minutes: 5}, args)
+
+	assert.False(t, m.Match(context.Background(), &fakeMessageEvent{msg: message.Text("banana")}))
+}
+
+func TestCommandIgnoreCase(t *testing.T) {
+	var got params.CommandArgs
+	m := OnCommand([]string{"echo"}, false, handler.NewHandler(func(args *params.CommandArgs) {
+		got = *args
+	}))
+
+	e := &fakeMessageEvent{msg: message.Text(`ECHO a "b c" --raw`)}
+	require.True(t, call(t, e, m.Match, func(ctx context.Context, e event.Event) error { return m.Call(ctx, e) }))
+	assert.Equal(t, params.CommandArgs{"a", "b c", "--raw"}, got)
+
+	strict := OnCommand([]string{"echo"}, true, handler.NewHandler(func() {}))
+	assert.False(t, strict.Match(context.Background(), e))
+}
+
+func TestCommandBindsTaggedStruct(t *testing.T) {
+	var args *banArgs
+	m := OnCommand([]string{"ban", "禁言"}, true, handler.NewHandler(func(a *banArgs) {
+		args = a
+	}))
+
+	e := &fakeMessageEvent{msg: message.New(
+		message.NewTextSegment("禁言 "),
+		message.NewAtSegment("123"),
+		message.NewTextSegment(" -t 5"),
+	)}
+	require.True(t, call(t, e, m.Match, func(ctx context.Context, e event.Event) error { return m.Call(ctx, e) }))
+	assert.Equal(t, &banArgs{User: "123", Minutes: 5}, args)
+}
+
+func TestCommandWithoutNames(t *testing.T) {
+	m := OnCommand(nil, true, handler.NewHandler(func() {}))
+	assert.False(t, m.Match(context.Background(), &fakeMessageEvent{msg: message.Text("ban")}))
+}
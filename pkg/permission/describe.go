@@ -0,0 +1,32 @@
+package permission
+
+// Describer 可描述的权限，用于帮助信息展示
+type Describer interface {
+	Description() string
+}
+
+// 带描述的权限
+type describedPermission struct {
+	Permission
+	description string
+}
+
+func (p describedPermission) Description() string {
+	return p.description
+}
+
+// Named 为权限附加描述
+func Named(description string, p Permission) Permission {
+	return describedPermission{Permission: p, description: description}
+}
+
+// Describe 返回权限描述，未命名的权限返回 "自定义权限"
+func Describe(p Permission) string {
+	if p == nil {
+		return "所有人"
+	}
+	if d, ok := p.(Describer); ok {
+		return d.Description()
+	}
+	return "自定义权限"
+}
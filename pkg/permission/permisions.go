@@ -8,9 +8,9 @@ import (
 
 // Everyone 所有人权限
 func Everyone() Permission {
-	return PermissionFunc(func(ctx context.Context, e event.Event) bool {
+	return Named("所有人", PermissionFunc(func(ctx context.Context, e event.Event) bool {
 		return true
-	})
+	}))
 }
 
 // 仅超级用户权限
@@ -20,36 +20,36 @@ func SuperUser(superUsers ...string) Permission {
 		userSet[user] = true
 	}
 
-	return PermissionFunc(func(ctx context.Context, e event.Event) bool {
+	return Named("超级用户", PermissionFunc(func(ctx context.Context, e event.Event) bool {
 		if msgEvent, ok := e.(event.MessageEvent); ok {
 			return userSet[msgEvent.UserID()]
 		}
 		return false
-	})
+	}))
 }
 
 // GroupOwner 仅群主权限
 func GroupOwner() Permission {
-	return PermissionFunc(func(ctx context.Context, e event.Event) bool {
+	return Named("群主", PermissionFunc(func(ctx context.Context, e event.Event) bool {
 		return getRole(e) == "owner"
-	})
+	}))
 }
 
 // GroupAdmin 仅群管理员权限
 func GroupAdmin() Permission {
-	return PermissionFunc(func(ctx context.Context, e event.Event) bool {
+	return Named("群管理员", PermissionFunc(func(ctx context.Context, e event.Event) bool {
 		return getRole(e) == "admin"
-	})
+	}))
 }
 
 // GroupMember 仅群成员权限
 func GroupMember() Permission {
-	return PermissionFunc(func(ctx context.Context, e event.Event) bool {
+	return Named("群成员", PermissionFunc(func(ctx context.Context, e event.Event) bool {
 		return getRole(e) == "member"
-	})
+	}))
 }
 
 // 超级用户、群主、管理员
-func GroupAdminOrOwner() Permission {
-	return condition.Any(SuperUser(), GroupOwner(), GroupAdmin())
+func GroupAdminOrOwner(superUsers ...string) Permission {
+	return Named("超级用户/群主/群管理员", condition.Any(SuperUser(superUsers...), GroupOwner(), GroupAdmin()))
 }
@@ -14,11 +14,14 @@ func Register[T Plugin](p T) *Wrapper[T] {
 	return &Wrapper[T]{Plugin: p}
 }
 
+// 注册插件Hook：插件实现 hook.Hookable 时注册到插件自身，否则注册到插件管理器为该插件维护的Hook管理器
 func (w *Wrapper[T]) WithHook(event hook.HookType, fn hook.HookHandler) *Wrapper[T] {
-	if h, ok := any(w.Plugin).(interface {
-		RegisterHook(hook.HookType, hook.HookHandler)
-	}); ok {
+	if h, ok := any(w.Plugin).(hook.Hookable); ok {
 		h.RegisterHook(event, fn)
+		return w
+	}
+	if p, ok := any(w.Plugin).(Plugin); ok && p.PluginInfo() != nil {
+		GetPluginRegistry().PluginHooks(p.PluginInfo().ID).AddHook(event, fn)
 	}
 	return w
 }
@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"context"
+	"errors"
+	"yora/pkg/command"
 	"yora/pkg/condition"
 	"yora/pkg/event"
 	"yora/pkg/handler"
@@ -12,20 +14,23 @@ import (
 
 type Matcher struct {
 	plugin     Plugin                // 插件
+	Name       string                // 名称(插件内唯一，用于按匹配器开关功能)
 	Rule       rule.Rule             // 规则(必须全部满足)
-	Permission permission.Permission // 权限(任意满足即可)
+	Permission permission.Permission // 权限(任意满足即可，nil 表示所有人)
 	Priority   int                   // 优先级(越大越优先)
 	Block      bool                  // 是否阻止事件传播
 	Handlers   []*handler.Handler    // 处理器
+	Command    *command.Command      // 命令定义(用于生成帮助信息)
+
+	temporary bool // 临时匹配器（会话等待），在事件接收时优先处理
 }
 
 func NewMatcher(rule rule.Rule, handlers ...*handler.Handler) *Matcher {
 	return &Matcher{
-		Rule:       rule,
-		Permission: permission.Everyone(),
-		Priority:   10,
-		Block:      false,
-		Handlers:   handlers,
+		Rule:     rule,
+		Priority: 10,
+		Block:    false,
+		Handlers: handlers,
 	}
 }
 
@@ -43,18 +48,54 @@ func (m *Matcher) SetPriority(priority int) *Matcher {
 	return m
 }
 
+// 设置命令定义，未设置名称时使用命令名
+func (m *Matcher) SetCommand(cmd *command.Command) *Matcher {
+	m.Command = cmd
+	if m.Name == "" && cmd != nil {
+		m.Name = cmd.Name
+	}
+	return m
+}
+
+func (m *Matcher) SetName(name string) *Matcher {
+	m.Name = name
+	return m
+}
+
 func (m *Matcher) SetBlock(block bool) *Matcher {
 	m.Block = block
 	return m
 }
 
+// 标记为临时匹配器
+func (m *Matcher) SetTemporary(temporary bool) *Matcher {
+	m.temporary = temporary
+	return m
+}
+
+func (m *Matcher) IsTemporary() bool {
+	return m.temporary
+}
+
 func (m *Matcher) AppendRule(rule rule.Rule) *Matcher {
 	m.Rule = condition.All(m.Rule, rule)
 	return m
 }
 
-func (m *Matcher) AppendPermission(permission permission.Permission) *Matcher {
-	m.Permission = condition.Any(m.Permission, permission)
+// 替换权限（默认不限制，所有人可用）
+func (m *Matcher) SetPermission(perm permission.Permission) *Matcher {
+	m.Permission = perm
+	return m
+}
+
+// 追加权限，与已有权限任意满足即可；未设置权限时直接使用 perm
+func (m *Matcher) AppendPermission(perm permission.Permission) *Matcher {
+	if m.Permission == nil {
+		m.Permission = perm
+		return m
+	}
+	desc := permission.Describe(m.Permission) + " 或 " + permission.Describe(perm)
+	m.Permission = permission.Named(desc, condition.Any(m.Permission, perm))
 	return m
 }
 
@@ -76,6 +117,9 @@ func (m *Matcher) Match(ctx context.Context, e event.Event) bool {
 func (m *Matcher) Call(ctx context.Context, e event.Event, provs ...provider.Provider) error {
 	for _, h := range m.Handlers {
 		if err := h.Call(ctx, e); err != nil {
+			if errors.Is(err, handler.ErrFinish) {
+				return nil
+			}
 			return err
 		}
 	}
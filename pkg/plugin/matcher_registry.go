@@ -25,6 +25,8 @@ var (
 // 匹配器注册中心
 type MatcherRegistry struct {
 	matchers []*Matcher
+	byPlugin map[string][]*Matcher // 插件ID -> 匹配器
+	disabled map[string]bool       // 已禁用的插件ID
 	logger   zerolog.Logger
 	mu       sync.RWMutex
 }
@@ -39,6 +41,8 @@ func GetMatcherRegistry() *MatcherRegistry {
 func newMatcherRegistry() *MatcherRegistry {
 	return &MatcherRegistry{
 		matchers: make([]*Matcher, 0),
+		byPlugin: make(map[string][]*Matcher),
+		disabled: make(map[string]bool),
 		logger:   log.NewHandler("MatcherRegistry"),
 	}
 }
@@ -48,38 +52,202 @@ func (mr *MatcherRegistry) RegisterMatchers(ms ...*Matcher) {
 	defer mr.mu.Unlock()
 
 	mr.matchers = append(mr.matchers, ms...)
+	for _, m := range ms {
+		if id := pluginID(m); id != "" {
+			mr.byPlugin[id] = append(mr.byPlugin[id], m)
+		}
+	}
 
-	sort.Slice(mr.matchers, func(i, j int) bool {
-		return mr.matchers[i].Priority < mr.matchers[j].Priority
+	// 优先级越大越优先，同优先级保持注册顺序
+	sort.SliceStable(mr.matchers, func(i, j int) bool {
+		return mr.matchers[i].Priority > mr.matchers[j].Priority
 	})
 }
+
 func (mr *MatcherRegistry) UnregisterMatchers(ms *Matcher) {
 	mr.mu.Lock()
 	defer mr.mu.Unlock()
 
-	for i, m := range mr.matchers {
-		if m == ms {
-			mr.matchers = append(mr.matchers[:i], mr.matchers[i+1:]...)
-			break
+	mr.matchers = removeMatcher(mr.matchers, ms)
+	if id := pluginID(ms); id != "" {
+		mr.byPlugin[id] = removeMatcher(mr.byPlugin[id], ms)
+		if len(mr.byPlugin[id]) == 0 {
+			delete(mr.byPlugin, id)
 		}
 	}
 }
 
-// 匹配事件并缓存匹配到的matcher
-func (mr *MatcherRegistry) MatchedMatchers(ctx context.Context, evt event.Event) []*Matcher {
+// 移除插件的所有匹配器，返回移除数量
+func (mr *MatcherRegistry) UnregisterPlugin(id string) int {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	owned := mr.byPlugin[id]
+	if len(owned) == 0 {
+		return 0
+	}
+
+	remaining := make([]*Matcher, 0, len(mr.matchers)-len(owned))
+	for _, m := range mr.matchers {
+		if pluginID(m) != id {
+			remaining = append(remaining, m)
+		}
+	}
+	mr.matchers = remaining
+	delete(mr.byPlugin, id)
+	delete(mr.disabled, id)
+
+	mr.logger.Debug().Str("插件ID", id).Int("数量", len(owned)).Msg("移除插件匹配器")
+	return len(owned)
+}
+
+// 启用或禁用插件的匹配器，禁用后不再参与匹配
+func (mr *MatcherRegistry) SetPluginEnabled(id string, enabled bool) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if enabled {
+		delete(mr.disabled, id)
+	} else {
+		mr.disabled[id] = true
+	}
+}
+
+// 在同一把锁内比较并切换插件启用状态，状态发生变化时返回 true
+func (mr *MatcherRegistry) SwapPluginEnabled(id string, enabled bool) bool {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if !mr.disabled[id] == enabled {
+		return false
+	}
+	if enabled {
+		delete(mr.disabled, id)
+	} else {
+		mr.disabled[id] = true
+	}
+	return true
+}
+
+// 插件是否启用
+func (mr *MatcherRegistry) IsPluginEnabled(id string) bool {
 	mr.mu.RLock()
 	defer mr.mu.RUnlock()
+	return !mr.disabled[id]
+}
 
-	matched := make([]*Matcher, 0, len(mr.matchers))
+// 获取插件的所有匹配器（按注册顺序）
+func (mr *MatcherRegistry) MatchersByPlugin(id string) []*Matcher {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	matchers := make([]*Matcher, len(mr.byPlugin[id]))
+	copy(matchers, mr.byPlugin[id])
+	return matchers
+}
 
+// 按优先级从高到低分层，同一层内的匹配器优先级相同（不含临时匹配器）
+func (mr *MatcherRegistry) PriorityTiers() [][]*Matcher {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	var tiers [][]*Matcher
 	for _, m := range mr.matchers {
-		if m.Rule.Match(ctx, evt) {
+		if m.temporary {
+			continue // 临时匹配器由 MatchTemporary 处理
+		}
+		if mr.disabled[pluginID(m)] {
+			continue
+		}
+		if n := len(tiers); n > 0 && tiers[n-1][0].Priority == m.Priority {
+			tiers[n-1] = append(tiers[n-1], m)
+			continue
+		}
+		tiers = append(tiers, []*Matcher{m})
+	}
+	return tiers
+}
+
+// 匹配事件（规则与权限均满足），按优先级从高到低返回
+//
+// 某一层中有阻断匹配器命中时，该层之后的匹配器不再参与匹配。
+func (mr *MatcherRegistry) MatchedMatchers(ctx context.Context, evt event.Event) []*Matcher {
+	var matched []*Matcher
+	for _, tier := range mr.PriorityTiers() {
+		hits := MatchTier(ctx, evt, tier)
+		matched = append(matched, hits...)
+		if Blocks(hits) {
+			break
+		}
+	}
+	return matched
+}
+
+// 匹配同一优先级层内的匹配器
+func MatchTier(ctx context.Context, evt event.Event, tier []*Matcher) []*Matcher {
+	var matched []*Matcher
+	for _, m := range tier {
+		if m.Match(ctx, evt) {
 			matched = append(matched, m)
 		}
+	}
+	return matched
+}
+
+// 命中的匹配器中是否有阻断事件传播的
+func Blocks(matched []*Matcher) bool {
+	for _, m := range matched {
 		if m.Block {
-			break
+			return true
 		}
 	}
+	return false
+}
 
+// 匹配临时匹配器（优先级从高到低）
+func (mr *MatcherRegistry) MatchTemporary(ctx context.Context, evt event.Event) []*Matcher {
+	mr.mu.RLock()
+	temps := make([]*Matcher, 0)
+	for _, m := range mr.matchers {
+		if m.temporary {
+			temps = append(temps, m)
+		}
+	}
+	mr.mu.RUnlock()
+
+	if len(temps) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(temps, func(i, j int) bool {
+		return temps[i].Priority > temps[j].Priority
+	})
+
+	matched := make([]*Matcher, 0, len(temps))
+	for _, m := range temps {
+		if m.Match(ctx, evt) {
+			matched = append(matched, m)
+			if m.Block {
+				break
+			}
+		}
+	}
 	return matched
 }
+
+// 匹配器所属插件ID
+func pluginID(m *Matcher) string {
+	if m.plugin == nil || m.plugin.PluginInfo() == nil {
+		return ""
+	}
+	return m.plugin.PluginInfo().ID
+}
+
+func removeMatcher(ms []*Matcher, target *Matcher) []*Matcher {
+	for i, m := range ms {
+		if m == target {
+			return append(ms[:i], ms[i+1:]...)
+		}
+	}
+	return ms
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yora/pkg/event"
+	"yora/pkg/permission"
+	"yora/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct{}
+
+func (testEvent) Type() string    { return "notice" }
+func (testEvent) SubType() string { return "" }
+func (testEvent) Time() time.Time { return time.Time{} }
+func (testEvent) SelfID() string  { return "10000" }
+func (testEvent) Raw() any        { return nil }
+
+func always(ok bool) rule.Rule {
+	return rule.RuleFunc(func(ctx context.Context, e event.Event) bool { return ok })
+}
+
+func deny() permission.Permission {
+	return permission.PermissionFunc(func(ctx context.Context, e event.Event) bool { return false })
+}
+
+func TestMatchedMatchersOrderPermissionAndBlock(t *testing.T) {
+	mr := newMatcherRegistry()
+
+	low := NewMatcher(always(true)).SetPriority(1)
+	high := NewMatcher(always(true)).SetPriority(100)
+	denied := NewMatcher(always(true)).SetPriority(100)
+	denied.Permission = deny()
+	unmatchedBlocker := NewMatcher(always(false)).SetPriority(50).SetBlock(true)
+	mid := NewMatcher(always(true)).SetPriority(10)
+
+	mr.RegisterMatchers(low, mid, unmatchedBlocker, denied, high)
+
+	// 未命中的阻断匹配器不影响后续匹配，权限不满足的匹配器被跳过
+	assert.Equal(t, []*Matcher{high, mid, low}, mr.MatchedMatchers(context.Background(), testEvent{}))
+
+	// 命中的阻断匹配器阻止低优先级层，但同层其他匹配器仍会执行
+	blocker := NewMatcher(always(true)).SetPriority(10).SetBlock(true)
+	mr.RegisterMatchers(blocker)
+	assert.Equal(t, []*Matcher{high, mid, blocker}, mr.MatchedMatchers(context.Background(), testEvent{}))
+}
+
+type userEvent struct {
+	event.MessageEvent
+	userID string
+}
+
+func (e userEvent) UserID() string { return e.userID }
+
+func TestAppendPermissionReplacesDefault(t *testing.T) {
+	m := NewMatcher(always(true)).AppendPermission(permission.SuperUser("10001"))
+
+	assert.Equal(t, "超级用户", permission.Describe(m.Permission))
+	assert.True(t, m.Match(context.Background(), userEvent{userID: "10001"}))
+	assert.False(t, m.Match(context.Background(), userEvent{userID: "20001"}))
+
+	m.AppendPermission(permission.GroupOwner())
+	assert.Equal(t, "超级用户 或 群主", permission.Describe(m.Permission))
+}
+
+func TestPriorityTiers(t *testing.T) {
+	mr := newMatcherRegistry()
+	a := NewMatcher(always(true)).SetPriority(5)
+	b := NewMatcher(always(true)).SetPriority(5)
+	c := NewMatcher(always(true)).SetPriority(9)
+	temp := NewMatcher(always(true)).SetPriority(9).SetTemporary(true)
+	mr.RegisterMatchers(a, temp, b, c)
+
+	assert.Equal(t, [][]*Matcher{{c}, {a, b}}, mr.PriorityTiers())
+}
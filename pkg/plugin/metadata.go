@@ -11,4 +11,6 @@ type PluginInfo struct {
 	Examples    []string       // 插件示例
 	Group       string         // 插件分组
 	Extra       map[string]any // 额外信息
+
+	DisabledByDefault bool // 默认关闭，需在群/用户范围内手动开启
 }
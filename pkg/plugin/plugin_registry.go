@@ -1,9 +1,11 @@
 package plugin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"yora/pkg/hook"
 	"yora/pkg/log"
 
 	"github.com/rs/zerolog"
@@ -17,6 +19,9 @@ type PluginRegistry struct {
 	logger    zerolog.Logger      // 日志记录器
 	mu        sync.RWMutex        // 读写锁，保护并发访问
 	mr        *MatcherRegistry    // 匹配器管理器
+
+	hooks   map[string]*hook.HookManager // 插件ID -> 插件Hook管理器，插件注销时清理
+	hooksMu sync.Mutex
 }
 
 var (
@@ -40,12 +45,13 @@ func newPluginRegistry() *PluginRegistry {
 		groups:    make(map[string][]Plugin),
 		logger:    log.NewPluginRegistry("插件管理器"),
 		mr:        GetMatcherRegistry(),
+		hooks:     make(map[string]*hook.HookManager),
 	}
 }
 
 func (pr *PluginRegistry) Unload() (err error) {
-	for _, p := range pr.plugins {
-		err = pr.unregisterPlugin(p)
+	for _, p := range pr.Plugins() {
+		err = pr.UnregisterPlugin(p.PluginInfo().ID)
 		if err != nil {
 			pr.logger.Error().Err(err).Msg("插件卸载失败")
 		} else {
@@ -83,6 +89,23 @@ func (pr *PluginRegistry) GetPluginsByGroup(group string) []Plugin {
 
 // 注册插件到管理器
 func (pr *PluginRegistry) RegisterPlugins(ps ...Plugin) error {
+	if err := pr.registerPlugins(ps); err != nil {
+		return err
+	}
+
+	// 释放锁后触发 Hook，Hook 中可以访问插件管理器
+	for _, p := range ps {
+		if !pr.mr.IsPluginEnabled(p.PluginInfo().ID) {
+			continue
+		}
+		if err := pr.triggerPluginHook(hook.PluginOnStart, p); err != nil {
+			pr.logger.Warn().Err(err).Str("插件ID", p.PluginInfo().ID).Msg("插件Hook执行失败")
+		}
+	}
+	return nil
+}
+
+func (pr *PluginRegistry) registerPlugins(ps []Plugin) error {
 	pr.mu.Lock()
 	defer pr.mu.Unlock()
 
@@ -199,26 +222,40 @@ func (pr *PluginRegistry) Plugins() []Plugin {
 	return plugins
 }
 
-// 根据ID注销插件
+// 根据ID注销插件，同时清理插件的Hook管理器
 func (pr *PluginRegistry) UnregisterPlugin(id string) error {
+	return pr.unregister(id, false)
+}
+
+// 注销插件，释放锁后触发 PluginOnStop；keepHooks 为 true 时保留插件的Hook管理器（用于重新加载）
+func (pr *PluginRegistry) unregister(id string, keepHooks bool) error {
 	if id == "" {
 		return fmt.Errorf("插件ID不能为空")
 	}
 
 	pr.mu.Lock()
-	defer pr.mu.Unlock()
-
 	plugin, exists := pr.plugins[id]
 	if !exists {
+		pr.mu.Unlock()
 		pr.logger.Warn().Str("插件ID", id).Msg("尝试注销不存在的插件")
 		return fmt.Errorf("未找到插件: %s", id)
 	}
+	wasEnabled := pr.unregisterPlugin(plugin)
+	pr.mu.Unlock()
 
-	return pr.unregisterPlugin(plugin)
+	if wasEnabled {
+		if err := pr.triggerPluginHook(hook.PluginOnStop, plugin); err != nil {
+			pr.logger.Warn().Err(err).Str("插件ID", id).Msg("插件Hook执行失败")
+		}
+	}
+	if !keepHooks {
+		pr.removePluginHooks(id)
+	}
+	return nil
 }
 
-// unregisterPlugin 内部注销插件方法
-func (pr *PluginRegistry) unregisterPlugin(plugin Plugin) error {
+// unregisterPlugin 内部注销插件方法，调用方需持有写锁；返回注销前插件是否启用
+func (pr *PluginRegistry) unregisterPlugin(plugin Plugin) bool {
 	metadata := plugin.PluginInfo()
 
 	// 卸载插件（如果支持）
@@ -247,16 +284,158 @@ func (pr *PluginRegistry) unregisterPlugin(plugin Plugin) error {
 		}
 	}
 
-	// TODO: 从匹配器注册表中删除相关匹配器
+	// 从匹配器注册表中删除相关匹配器
+	wasEnabled := pr.mr.IsPluginEnabled(metadata.ID)
+	removed := pr.mr.UnregisterPlugin(metadata.ID)
 
 	pr.logger.Info().
 		Str("插件ID", metadata.ID).
 		Str("插件名", metadata.Name).
+		Int("移除匹配器", removed).
 		Msg("插件注销成功")
 
+	return wasEnabled
+}
+
+// 重新加载插件：注销后重新执行初始化、加载与验证并注册匹配器，保留启用状态
+func (pr *PluginRegistry) ReloadPlugin(id string) error {
+	p, err := pr.GetPlugin(id)
+	if err != nil {
+		return err
+	}
+
+	enabled := pr.mr.IsPluginEnabled(id)
+	// 重新加载不丢失已注册的插件Hook
+	if err := pr.unregister(id, true); err != nil {
+		return err
+	}
+
+	// 注册前恢复禁用状态，匹配器不会在重新加载期间生效，也不会触发 PluginOnStart
+	if !enabled {
+		pr.mr.SetPluginEnabled(id, false)
+	}
+	if err := pr.RegisterPlugins(p); err != nil {
+		return fmt.Errorf("重新加载插件[%s]失败: %w", id, err)
+	}
+	if err := pr.triggerPluginHook(hook.PluginOnReload, p); err != nil {
+		pr.logger.Warn().Err(err).Str("插件ID", id).Msg("插件Hook执行失败")
+	}
+
+	pr.logger.Info().Str("插件ID", id).Msg("插件重新加载成功")
 	return nil
 }
 
+// 启用插件，插件匹配器恢复参与匹配，并触发 PluginOnStart
+func (pr *PluginRegistry) EnablePlugin(id string) error {
+	return pr.setPluginEnabled(id, true)
+}
+
+// 禁用插件，插件匹配器不再参与匹配（不卸载插件），并触发 PluginOnStop
+func (pr *PluginRegistry) DisablePlugin(id string) error {
+	return pr.setPluginEnabled(id, false)
+}
+
+// 插件是否启用
+func (pr *PluginRegistry) IsPluginEnabled(id string) bool {
+	return pr.mr.IsPluginEnabled(id)
+}
+
+func (pr *PluginRegistry) setPluginEnabled(id string, enabled bool) error {
+	p, err := pr.GetPlugin(id)
+	if err != nil {
+		return err
+	}
+
+	// 并发开关同一插件时只有一方会触发 Hook
+	if !pr.mr.SwapPluginEnabled(id, enabled) {
+		return nil
+	}
+
+	hookType, action := hook.PluginOnStop, "禁用"
+	if enabled {
+		hookType, action = hook.PluginOnStart, "启用"
+	}
+	if err := pr.triggerPluginHook(hookType, p); err != nil {
+		pr.logger.Warn().Err(err).Str("插件ID", id).Str("Hook", string(hookType)).Msg("插件Hook执行失败")
+	}
+
+	pr.logger.Info().Str("插件ID", id).Msg("插件已" + action)
+	return nil
+}
+
+// 获取插件的Hook管理器，不存在时创建；插件注销时随之清理
+func (pr *PluginRegistry) PluginHooks(id string) *hook.HookManager {
+	pr.hooksMu.Lock()
+	defer pr.hooksMu.Unlock()
+
+	hm, ok := pr.hooks[id]
+	if !ok {
+		hm = hook.NewHookManager()
+		pr.hooks[id] = hm
+	}
+	return hm
+}
+
+func (pr *PluginRegistry) removePluginHooks(id string) {
+	pr.hooksMu.Lock()
+	defer pr.hooksMu.Unlock()
+
+	if hm, ok := pr.hooks[id]; ok {
+		hm.Clear()
+		delete(pr.hooks, id)
+	}
+}
+
+// 依次触发全局Hook、插件Hook管理器中的Hook与插件自身的Hook（插件实现 hook.Hookable 时），
+// 任一Hook返回错误即停止并返回该错误；p 为空时只触发全局Hook
+func (pr *PluginRegistry) TriggerHook(hookType hook.HookType, p Plugin, hc *hook.HookContext) error {
+	if err := hook.TriggerGlobalHook(hookType, hc); err != nil {
+		return err
+	}
+	if p == nil || p.PluginInfo() == nil {
+		return nil
+	}
+
+	pr.hooksMu.Lock()
+	hm := pr.hooks[p.PluginInfo().ID]
+	pr.hooksMu.Unlock()
+	if hm != nil {
+		if err := hm.TriggerHook(hookType, hc); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := p.(hook.Hookable); ok {
+		return h.TriggerHook(hookType, hc)
+	}
+	return nil
+}
+
+// 触发全局Hook后依次触发所有插件的Hook（如 BotOnStart），返回所有错误
+func (pr *PluginRegistry) BroadcastHook(hookType hook.HookType, hc *hook.HookContext) error {
+	errs := []error{hook.TriggerGlobalHook(hookType, hc)}
+	for _, p := range pr.Plugins() {
+		pr.hooksMu.Lock()
+		hm := pr.hooks[p.PluginInfo().ID]
+		pr.hooksMu.Unlock()
+		if hm != nil {
+			errs = append(errs, hm.TriggerHook(hookType, hc))
+		}
+		if h, ok := p.(hook.Hookable); ok {
+			errs = append(errs, h.TriggerHook(hookType, hc))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 触发插件生命周期Hook
+func (pr *PluginRegistry) triggerPluginHook(hookType hook.HookType, p Plugin) error {
+	hc := hook.NewPluginHookContext(context.Background(), hookType, p)
+	hc.Set("plugin_id", p.PluginInfo().ID)
+	hc.Set("plugin", p)
+	return pr.TriggerHook(hookType, p, hc.HookContext)
+}
+
 // 配置插件
 func (pr *PluginRegistry) ConfigurePlugin(id string, config map[string]any) error {
 	plugin, err := pr.GetPlugin(id)
@@ -280,6 +459,10 @@ func (pr *PluginRegistry) ConfigurePlugin(id string, config map[string]any) erro
 		}
 	}
 
+	if err := pr.triggerPluginHook(hook.PluginOnConfigChange, plugin); err != nil {
+		pr.logger.Warn().Err(err).Str("插件ID", id).Msg("插件Hook执行失败")
+	}
+
 	pr.logger.Info().Str("插件ID", id).Msg("插件配置成功")
 	return nil
 }
@@ -0,0 +1,189 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"yora/pkg/hook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPlugin struct {
+	info     *PluginInfo
+	matchers []*Matcher
+	hooks    *hook.HookManager
+}
+
+func (p *testPlugin) PluginInfo() *PluginInfo { return p.info }
+func (p *testPlugin) Matchers() []*Matcher    { return p.matchers }
+
+func (p *testPlugin) HookManager() *hook.HookManager { return p.hooks }
+func (p *testPlugin) RegisterHook(t hook.HookType, h hook.HookHandler) string {
+	return p.hooks.AddHook(t, h)
+}
+func (p *testPlugin) TriggerHook(t hook.HookType, ctx *hook.HookContext) error {
+	return p.hooks.TriggerHook(t, ctx)
+}
+
+func newTestPluginRegistry() *PluginRegistry {
+	pr := newPluginRegistry()
+	pr.mr = newMatcherRegistry()
+	return pr
+}
+
+func TestDisableEnablePlugin(t *testing.T) {
+	pr := newTestPluginRegistry()
+	m := NewMatcher(always(true))
+	p := &testPlugin{
+		info:     &PluginInfo{ID: "demo", Name: "Demo"},
+		matchers: []*Matcher{m},
+		hooks:    hook.NewHookManager(),
+	}
+
+	var fired []hook.HookType
+	p.RegisterHook(hook.PluginOnStop, func(ctx *hook.HookContext) error {
+		fired = append(fired, ctx.HookType())
+		return nil
+	})
+	p.RegisterHook(hook.PluginOnStart, func(ctx *hook.HookContext) error {
+		fired = append(fired, ctx.HookType())
+		return nil
+	})
+
+	require.NoError(t, pr.RegisterPlugins(p))
+	assert.Equal(t, []*Matcher{m}, pr.mr.MatchersByPlugin("demo"))
+
+	require.NoError(t, pr.DisablePlugin("demo"))
+	assert.False(t, pr.IsPluginEnabled("demo"))
+	assert.Empty(t, pr.mr.MatchedMatchers(context.Background(), testEvent{}))
+
+	require.NoError(t, pr.DisablePlugin("demo")) // 重复禁用不再触发 Hook
+	require.NoError(t, pr.EnablePlugin("demo"))
+	assert.Equal(t, []*Matcher{m}, pr.mr.MatchedMatchers(context.Background(), testEvent{}))
+
+	assert.Equal(t, []hook.HookType{hook.PluginOnStart, hook.PluginOnStop, hook.PluginOnStart}, fired)
+	assert.Error(t, pr.EnablePlugin("missing"))
+}
+
+func TestUnregisterPluginRemovesMatchers(t *testing.T) {
+	pr := newTestPluginRegistry()
+	keep := NewMatcher(always(true))
+	other := &testPlugin{info: &PluginInfo{ID: "other", Name: "Other"}, matchers: []*Matcher{keep}, hooks: hook.NewHookManager()}
+	p := &testPlugin{
+		info:     &PluginInfo{ID: "demo", Name: "Demo"},
+		matchers: []*Matcher{NewMatcher(always(true)), NewMatcher(always(true))},
+		hooks:    hook.NewHookManager(),
+	}
+	require.NoError(t, pr.RegisterPlugins(p, other))
+
+	require.NoError(t, pr.UnregisterPlugin("demo"))
+	assert.Empty(t, pr.mr.MatchersByPlugin("demo"))
+	assert.Equal(t, []*Matcher{keep}, pr.mr.MatchedMatchers(context.Background(), testEvent{}))
+}
+
+func TestReloadPluginKeepsDisabledState(t *testing.T) {
+	pr := newTestPluginRegistry()
+	m := NewMatcher(always(true))
+	p := &testPlugin{info: &PluginInfo{ID: "demo", Name: "Demo"}, matchers: []*Matcher{m}, hooks: hook.NewHookManager()}
+	require.NoError(t, pr.RegisterPlugins(p))
+	require.NoError(t, pr.DisablePlugin("demo"))
+
+	// 重新加载禁用的插件不触发 PluginOnStart
+	var started int
+	p.RegisterHook(hook.PluginOnStart, func(ctx *hook.HookContext) error {
+		started++
+		return nil
+	})
+
+	require.NoError(t, pr.ReloadPlugin("demo"))
+	assert.Equal(t, []*Matcher{m}, pr.mr.MatchersByPlugin("demo"))
+	assert.False(t, pr.IsPluginEnabled("demo"))
+	assert.Empty(t, pr.mr.MatchedMatchers(context.Background(), testEvent{}))
+	assert.Zero(t, started)
+	assert.Error(t, pr.ReloadPlugin("missing"))
+}
+
+func TestConcurrentDisableFiresStopOnce(t *testing.T) {
+	pr := newTestPluginRegistry()
+	p := &testPlugin{info: &PluginInfo{ID: "demo", Name: "Demo"}, hooks: hook.NewHookManager()}
+	var stopped atomic.Int32
+	p.RegisterHook(hook.PluginOnStop, func(ctx *hook.HookContext) error {
+		stopped.Add(1)
+		return nil
+	})
+	require.NoError(t, pr.RegisterPlugins(p))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, pr.DisablePlugin("demo"))
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, stopped.Load())
+}
+
+func TestPluginHooksClearedOnUnregister(t *testing.T) {
+	pr := newTestPluginRegistry()
+	p := &testPlugin{info: &PluginInfo{ID: "demo", Name: "Demo"}, hooks: hook.NewHookManager()}
+
+	var fired []hook.HookType
+	hm := pr.PluginHooks("demo")
+	hm.AddHook(hook.PluginOnStart, func(ctx *hook.HookContext) error {
+		phc, ok := hook.As[*hook.PluginHookContext](ctx)
+		require.True(t, ok)
+		assert.Equal(t, p, phc.Plugin)
+		fired = append(fired, ctx.HookType())
+		return nil
+	})
+	hm.AddHook(hook.MatcherBeforeHandle, func(ctx *hook.HookContext) error {
+		return errors.New("veto")
+	})
+
+	require.NoError(t, pr.RegisterPlugins(p))
+	assert.Equal(t, []hook.HookType{hook.PluginOnStart}, fired)
+	assert.EqualError(t, pr.TriggerHook(hook.MatcherBeforeHandle, p, hook.NewHookContext(context.Background(), hook.MatcherBeforeHandle)), "veto")
+
+	require.NoError(t, pr.ReloadPlugin("demo"))
+	assert.Same(t, hm, pr.PluginHooks("demo"))
+	assert.Equal(t, 1, hm.HookCount(hook.PluginOnStart), "重新加载保留插件Hook")
+	assert.Equal(t, []hook.HookType{hook.PluginOnStart, hook.PluginOnStart}, fired, "重新加载后插件Hook仍会触发")
+	assert.EqualError(t, pr.TriggerHook(hook.MatcherBeforeHandle, p, hook.NewHookContext(context.Background(), hook.MatcherBeforeHandle)), "veto")
+
+	require.NoError(t, pr.UnregisterPlugin("demo"))
+	assert.Zero(t, hm.HookCount(hook.PluginOnStart))
+	assert.NotSame(t, hm, pr.PluginHooks("demo"))
+}
+
+func TestPluginHooksMayUseRegistry(t *testing.T) {
+	pr := newTestPluginRegistry()
+	p := &testPlugin{info: &PluginInfo{ID: "demo", Name: "Demo"}, hooks: hook.NewHookManager()}
+
+	var seen []int
+	for _, ht := range []hook.HookType{hook.PluginOnStart, hook.PluginOnStop} {
+		pr.PluginHooks("demo").AddHook(ht, func(ctx *hook.HookContext) error {
+			seen = append(seen, len(pr.Plugins()))
+			return nil
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, pr.RegisterPlugins(p))
+		assert.NoError(t, pr.UnregisterPlugin("demo"))
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Hook 中访问插件管理器发生死锁")
+	}
+	assert.Equal(t, []int{1, 0}, seen)
+}
@@ -0,0 +1,328 @@
+// Package policy 插件开关策略
+//
+// 按全局、群、用户三个范围开启或关闭插件（或插件中的单个匹配器），
+// 事件分发时先经过策略过滤，再进行规则与权限匹配。
+//
+// 判定顺序：用户 > 群 > 全局 > 插件默认值（PluginInfo.DisabledByDefault），
+// 同一范围内匹配器级别的设置优先于插件级别。
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"yora/pkg/event"
+	"yora/pkg/log"
+	"yora/pkg/permission"
+	"yora/pkg/plugin"
+
+	"github.com/rs/zerolog"
+)
+
+// 策略范围
+const (
+	ScopeGlobal = "global" // 全局
+	ScopeGroup  = "group"  // 群
+	ScopeUser   = "user"   // 用户
+)
+
+var (
+	once     sync.Once
+	instance *Policy
+)
+
+// 策略数据：范围ID -> 目标 -> 是否开启
+type data struct {
+	Global map[string]bool            `json:"global"`
+	Groups map[string]map[string]bool `json:"groups"`
+	Users  map[string]map[string]bool `json:"users"`
+}
+
+// Policy 插件开关策略
+type Policy struct {
+	data   data
+	path   string // 持久化文件，为空时仅保存在内存
+	logger zerolog.Logger
+	mu     sync.RWMutex
+}
+
+// GetPolicy 获取策略单例
+func GetPolicy() *Policy {
+	once.Do(func() {
+		instance = New()
+	})
+	return instance
+}
+
+// New 创建仅保存在内存中的策略
+func New() *Policy {
+	return &Policy{
+		data: data{
+			Global: make(map[string]bool),
+			Groups: make(map[string]map[string]bool),
+			Users:  make(map[string]map[string]bool),
+		},
+		logger: log.NewHandler("Policy"),
+	}
+}
+
+// SetFile 设置持久化文件并加载已有策略，文件不存在时在首次修改后创建
+func (p *Policy) SetFile(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.path = path
+	if path == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取策略文件失败: %w", err)
+	}
+
+	var d data
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return fmt.Errorf("解析策略文件失败: %w", err)
+	}
+	if d.Global != nil {
+		p.data.Global = d.Global
+	}
+	if d.Groups != nil {
+		p.data.Groups = d.Groups
+	}
+	if d.Users != nil {
+		p.data.Users = d.Users
+	}
+
+	p.logger.Info().Str("文件", path).Msg("加载插件开关策略")
+	return nil
+}
+
+// Target 策略目标：插件ID，或 "插件ID:匹配器名" 表示单个匹配器
+func Target(pluginID string, matcher string) string {
+	if matcher == "" {
+		return pluginID
+	}
+	return pluginID + ":" + matcher
+}
+
+// Set 在指定范围内开启或关闭目标，并写入持久化文件
+func (p *Policy) Set(scope, id, target string, enabled bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m, err := p.scopeMap(scope, id, true)
+	if err != nil {
+		return err
+	}
+	old, had := m[target]
+	m[target] = enabled
+	if err := p.save(); err != nil {
+		p.restore(scope, id, target, old, had)
+		return err
+	}
+	return nil
+}
+
+// Reset 清除指定范围内目标的设置，恢复为上级范围的结果
+func (p *Policy) Reset(scope, id, target string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m, err := p.scopeMap(scope, id, false)
+	if err != nil || m == nil {
+		return err
+	}
+	old, had := m[target]
+	if !had {
+		return nil
+	}
+	delete(m, target)
+	if err := p.save(); err != nil {
+		p.restore(scope, id, target, old, had)
+		return err
+	}
+	return nil
+}
+
+// Get 获取指定范围内目标的设置
+func (p *Policy) Get(scope, id, target string) (enabled bool, ok bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	m, err := p.scopeMap(scope, id, false)
+	if err != nil || m == nil {
+		return false, false
+	}
+	enabled, ok = m[target]
+	return enabled, ok
+}
+
+// Enabled 判断匹配器在群 groupID、用户 userID 下是否开启，groupID 为空表示私聊
+func (p *Policy) Enabled(m *plugin.Matcher, groupID, userID string) bool {
+	pl := m.Plugin()
+	if pl == nil || pl.PluginInfo() == nil {
+		return true // 不属于插件的匹配器不受策略控制
+	}
+	info := pl.PluginInfo()
+	if m.Name == "" {
+		return p.resolve(info, groupID, userID, info.ID)
+	}
+	return p.resolve(info, groupID, userID, Target(info.ID, m.Name), info.ID)
+}
+
+// PluginEnabled 判断插件在群 groupID、用户 userID 下是否开启（不考虑匹配器级别的设置）
+func (p *Policy) PluginEnabled(info *plugin.PluginInfo, groupID, userID string) bool {
+	return p.resolve(info, groupID, userID, info.ID)
+}
+
+// 按 用户 > 群 > 全局 的顺序查找 targets 的设置，均未设置时使用插件默认值
+func (p *Policy) resolve(info *plugin.PluginInfo, groupID, userID string, targets ...string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	levels := []map[string]bool{p.data.Users[userID], p.data.Groups[groupID], p.data.Global}
+	if userID == "" {
+		levels[0] = nil
+	}
+	if groupID == "" {
+		levels[1] = nil
+	}
+
+	for _, level := range levels {
+		for _, t := range targets {
+			if v, ok := level[t]; ok {
+				return v
+			}
+		}
+	}
+	return !info.DisabledByDefault
+}
+
+// Filter 过滤出在事件所在群、用户下开启的匹配器
+func (p *Policy) Filter(e event.Event, ms []*plugin.Matcher) []*plugin.Matcher {
+	groupID, userID := Subject(e)
+
+	enabled := ms[:0:0]
+	for _, m := range ms {
+		if p.Enabled(m, groupID, userID) {
+			enabled = append(enabled, m)
+		}
+	}
+	return enabled
+}
+
+// CanManage 判断事件触发者能否修改指定范围的策略
+//
+// 超级用户可修改任意范围；群主和群管理员只能修改自己所在群的群范围策略。
+func CanManage(ctx context.Context, e event.Event, scope, id string, superUsers ...string) bool {
+	if permission.SuperUser(superUsers...).Match(ctx, e) {
+		return true
+	}
+	if scope != ScopeGroup {
+		return false
+	}
+	groupID, _ := Subject(e)
+	return groupID != "" && groupID == id && permission.GroupAdminOrOwner(superUsers...).Match(ctx, e)
+}
+
+// Subject 获取事件所在的群和触发用户，私聊时群ID为空
+func Subject(e event.Event) (groupID, userID string) {
+	switch v := e.(type) {
+	case event.MessageEvent:
+		if v.IsGroup() {
+			groupID = v.ChatID()
+		}
+		userID = v.UserID()
+	case event.NoticeEvent:
+		groupID, userID = v.ChatID(), v.UserID()
+	case event.RequestEvent:
+		groupID, userID = v.ChatID(), v.UserID()
+	}
+	if groupID == "0" {
+		groupID = ""
+	}
+	return groupID, userID
+}
+
+// 获取范围对应的设置表，create 为 true 时自动创建
+func (p *Policy) scopeMap(scope, id string, create bool) (map[string]bool, error) {
+	var parent map[string]map[string]bool
+	switch scope {
+	case ScopeGlobal:
+		return p.data.Global, nil
+	case ScopeGroup:
+		parent = p.data.Groups
+	case ScopeUser:
+		parent = p.data.Users
+	default:
+		return nil, fmt.Errorf("未知策略范围: %s", scope)
+	}
+
+	if id == "" {
+		return nil, fmt.Errorf("%s 范围的ID不能为空", scope)
+	}
+	m, ok := parent[id]
+	if !ok && create {
+		m = make(map[string]bool)
+		parent[id] = m
+	}
+	return m, nil
+}
+
+// 写入失败时恢复目标原来的设置，并清理为此创建的空设置表
+func (p *Policy) restore(scope, id, target string, old, had bool) {
+	m, _ := p.scopeMap(scope, id, false)
+	if m == nil {
+		return
+	}
+	if had {
+		m[target] = old
+		return
+	}
+	delete(m, target)
+	if len(m) > 0 {
+		return
+	}
+	switch scope {
+	case ScopeGroup:
+		delete(p.data.Groups, id)
+	case ScopeUser:
+		delete(p.data.Users, id)
+	}
+}
+
+// 写入持久化文件（先写临时文件再替换，避免写入中断损坏文件）
+func (p *Policy) save() error {
+	if p.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(p.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化策略失败: %w", err)
+	}
+
+	if dir := filepath.Dir(p.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建策略目录失败: %w", err)
+		}
+	}
+
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return fmt.Errorf("写入策略文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		return fmt.Errorf("写入策略文件失败: %w", err)
+	}
+	return nil
+}
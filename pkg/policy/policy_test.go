@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"yora/pkg/event"
+	"yora/pkg/message"
+	"yora/pkg/plugin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPlugin struct {
+	info *plugin.PluginInfo
+}
+
+func (p *testPlugin) PluginInfo() *plugin.PluginInfo { return p.info }
+func (p *testPlugin) Matchers() []*plugin.Matcher    { return nil }
+
+type testSender struct {
+	message.Sender
+	role string
+}
+
+func (s *testSender) Role() string { return s.role }
+
+type testGroupEvent struct {
+	event.GroupMessageEvent
+	user, group, role string
+}
+
+func (e *testGroupEvent) UserID() string         { return e.user }
+func (e *testGroupEvent) ChatID() string         { return e.group }
+func (e *testGroupEvent) IsGroup() bool          { return true }
+func (e *testGroupEvent) Sender() message.Sender { return &testSender{role: e.role} }
+
+func newMatcher(info *plugin.PluginInfo, name string) *plugin.Matcher {
+	return plugin.NewMatcher(nil).SetPlugin(&testPlugin{info: info}).SetName(name)
+}
+
+func TestEnabledPrecedence(t *testing.T) {
+	p := New()
+	info := &plugin.PluginInfo{ID: "demo"}
+	m := newMatcher(info, "roll")
+
+	assert.True(t, p.Enabled(m, "g1", "u1"), "默认开启")
+
+	require.NoError(t, p.Set(ScopeGlobal, "", "demo", false))
+	assert.False(t, p.Enabled(m, "g1", "u1"))
+
+	require.NoError(t, p.Set(ScopeGroup, "g1", "demo", true))
+	assert.True(t, p.Enabled(m, "g1", "u1"), "群范围覆盖全局")
+	assert.False(t, p.Enabled(m, "g2", "u1"))
+
+	require.NoError(t, p.Set(ScopeGroup, "g1", Target("demo", "roll"), false))
+	assert.False(t, p.Enabled(m, "g1", "u1"), "匹配器设置优先于插件设置")
+	assert.True(t, p.Enabled(newMatcher(info, "other"), "g1", "u1"))
+
+	require.NoError(t, p.Set(ScopeUser, "u1", "demo", true))
+	assert.True(t, p.Enabled(m, "g1", "u1"), "用户范围优先于群范围")
+
+	require.NoError(t, p.Reset(ScopeUser, "u1", "demo"))
+	assert.False(t, p.Enabled(m, "g1", "u1"))
+
+	off := &plugin.PluginInfo{ID: "off", DisabledByDefault: true}
+	assert.False(t, p.Enabled(newMatcher(off, ""), "g1", "u1"))
+	assert.True(t, p.Enabled(plugin.NewMatcher(nil), "g1", "u1"), "无插件的匹配器不受控制")
+
+	assert.Error(t, p.Set(ScopeGroup, "", "demo", true))
+	assert.Error(t, p.Set("unknown", "x", "demo", true))
+}
+
+func TestPolicyPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "policy.json")
+
+	p := New()
+	require.NoError(t, p.SetFile(path))
+	require.NoError(t, p.Set(ScopeGroup, "g1", "demo", false))
+
+	reloaded := New()
+	require.NoError(t, reloaded.SetFile(path))
+	enabled, ok := reloaded.Get(ScopeGroup, "g1", "demo")
+	assert.True(t, ok)
+	assert.False(t, enabled)
+}
+
+func TestSetRollsBackWhenSaveFails(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+
+	p := New()
+	require.NoError(t, p.Set(ScopeGlobal, "", "demo", true))
+	p.path = filepath.Join(blocker, "policy.json") // 父路径是文件，写入必然失败
+
+	assert.Error(t, p.Set(ScopeGlobal, "", "demo", false))
+	enabled, ok := p.Get(ScopeGlobal, "", "demo")
+	assert.True(t, ok)
+	assert.True(t, enabled, "写入失败时保留原设置")
+
+	assert.Error(t, p.Set(ScopeGroup, "g1", "demo", false))
+	_, ok = p.Get(ScopeGroup, "g1", "demo")
+	assert.False(t, ok, "写入失败时不留下新设置")
+	assert.NotContains(t, p.data.Groups, "g1")
+
+	assert.Error(t, p.Reset(ScopeGlobal, "", "demo"))
+	_, ok = p.Get(ScopeGlobal, "", "demo")
+	assert.True(t, ok, "清除失败时保留原设置")
+}
+
+func TestCanManage(t *testing.T) {
+	ctx := context.Background()
+	admin := &testGroupEvent{user: "u1", group: "g1", role: "admin"}
+	member := &testGroupEvent{user: "u2", group: "g1", role: "member"}
+	root := &testGroupEvent{user: "root", group: "g1", role: "member"}
+
+	assert.True(t, CanManage(ctx, admin, ScopeGroup, "g1", "root"))
+	assert.False(t, CanManage(ctx, admin, ScopeGroup, "g2", "root"), "不能修改其他群")
+	assert.False(t, CanManage(ctx, admin, ScopeGlobal, "", "root"))
+	assert.False(t, CanManage(ctx, member, ScopeGroup, "g1", "root"))
+	assert.True(t, CanManage(ctx, root, ScopeGlobal, "", "root"))
+}
@@ -2,11 +2,58 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"yora/pkg/command"
 	"yora/pkg/event"
+	"yora/pkg/params"
 )
 
-func CommandArgs(cmds []string) Provider {
+// ParseCommand 使用解析器解析消息事件，命令匹配器的规则、依赖与错误回复均基于此结果
+func ParseCommand(parser *command.Parser, e event.Event) (*command.ParseResult, error) {
+	msgEvent, ok := e.(event.MessageEvent)
+	if !ok {
+		return nil, fmt.Errorf("当前事件不是消息事件")
+	}
+	return parser.ParseMessage(msgEvent.Message())
+}
+
+// CommandParseFunc 解析当前事件中的命令，调用方可在同一事件内缓存解析结果
+type CommandParseFunc func(ctx context.Context, e event.Event) (*command.ParseResult, error)
+
+// CommandResult 命令解析结果 *command.ParseResult，解析失败时不提供
+func CommandResult(parse CommandParseFunc) Provider {
+	return DynamicProvider(func(ctx context.Context, e event.Event) any {
+		result, err := parse(ctx, e)
+		if err != nil {
+			return nil
+		}
+		return result
+	})
+}
+
+// CommandStruct 绑定了命令参数的结构体指针（t 为带 arg/opt 标签的结构体指针类型），解析或绑定失败时不提供
+func CommandStruct(parse CommandParseFunc, t reflect.Type) Provider {
+	return DynamicProvider(func(ctx context.Context, e event.Event) any {
+		result, err := parse(ctx, e)
+		if err != nil {
+			return nil
+		}
+		v := reflect.New(t.Elem())
+		if err := result.Bind(v.Interface()); err != nil {
+			return nil
+		}
+		return v.Interface()
+	})
+}
+
+// CommandArgs 命令参数：去掉命令名后的参数（支持引号）
+func CommandArgs(parse CommandParseFunc) Provider {
 	return DynamicProvider(func(ctx context.Context, e event.Event) any {
-		return cmds
+		args := params.CommandArgs{}
+		if result, err := parse(ctx, e); err == nil {
+			args = params.CommandArgs(result.RawArgs)
+		}
+		return &args
 	})
 }
@@ -0,0 +1,160 @@
+// Package replier 提供回复当前事件的便捷方法
+//
+// 回复总是通过事件来源适配器和账号发送到事件所在的会话（群聊或私聊）：
+//
+//	func (p *plugin) ping(r *replier.Replier) error {
+//		_, err := r.Reply(message.Text("pong"))
+//		return err
+//	}
+//
+// 也可以直接使用 replier.Reply(ctx, msg)。
+package replier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/provider"
+)
+
+var (
+	ErrNoAdapter = errors.New("上下文中缺少事件来源适配器")
+	ErrNoEvent   = errors.New("当前事件不是消息、通知或请求事件，无法确定回复目标")
+)
+
+// Replier 回复器，绑定事件来源适配器与会话
+type Replier struct {
+	ctx       context.Context
+	adapter   adapter.Adapter
+	userID    string
+	chatID    string
+	messageID string
+}
+
+// New 根据上下文中的适配器和事件创建回复器
+func New(ctx context.Context, e event.Event) (*Replier, error) {
+	a, ok := adapter.FromContext(ctx)
+	if !ok {
+		return nil, ErrNoAdapter
+	}
+
+	r := &Replier{ctx: ctx, adapter: a}
+	switch v := e.(type) {
+	case event.MessageEvent:
+		r.userID, r.messageID = v.UserID(), v.MessageID()
+		if v.IsGroup() {
+			r.chatID = v.ChatID()
+		}
+	case event.NoticeEvent:
+		r.userID, r.chatID = v.UserID(), v.ChatID()
+	case event.RequestEvent:
+		r.userID, r.chatID = v.UserID(), v.ChatID()
+	default:
+		return nil, ErrNoEvent
+	}
+	if r.chatID == "0" {
+		r.chatID = ""
+	}
+	return r, nil
+}
+
+// FromContext 从上下文的依赖注入作用域中获取当前事件并创建回复器
+func FromContext(ctx context.Context) (*Replier, error) {
+	scope, ok := handler.ScopeFromContext(ctx)
+	if !ok {
+		return nil, ErrNoEvent
+	}
+	return New(ctx, scope.Event())
+}
+
+// Provider 注入 *Replier
+func Provider() provider.Provider {
+	return provider.DynamicProvider(func(ctx context.Context, e event.Event) any {
+		r, err := New(ctx, e)
+		if err != nil {
+			return nil
+		}
+		return r
+	})
+}
+
+// Reply 回复消息到事件所在会话，返回消息ID
+func (r *Replier) Reply(msg message.Message) (string, error) {
+	if msg == nil || msg.IsEmpty() {
+		return "", fmt.Errorf("消息不能为空")
+	}
+	if r.chatID != "" {
+		return adapter.Send(r.ctx, r.adapter, "0", r.chatID, msg)
+	}
+	return adapter.Send(r.ctx, r.adapter, r.userID, "0", msg)
+}
+
+// ReplyText 回复纯文本
+func (r *Replier) ReplyText(text string) (string, error) {
+	return r.Reply(message.Text(text))
+}
+
+// ReplyQuote 引用触发事件的消息进行回复
+func (r *Replier) ReplyQuote(msg message.Message) (string, error) {
+	if r.messageID == "" {
+		return r.Reply(msg)
+	}
+	quote := message.NewReplySegment(r.messageID)
+	return r.Reply(prepend(msg, quote))
+}
+
+// ReplyAt 在群聊中 @ 触发者进行回复，私聊时直接回复
+func (r *Replier) ReplyAt(msg message.Message) (string, error) {
+	if r.chatID == "" {
+		return r.Reply(msg)
+	}
+	at := message.NewAtSegment(r.userID)
+	space := message.NewTextSegment(" ")
+	return r.Reply(prepend(msg, at, space))
+}
+
+// ReplyForward 以合并转发回复到事件所在会话，适配器不支持时返回 adapter.ErrForwardUnsupported
+func (r *Replier) ReplyForward(nodes ...message.ForwardNode) (string, error) {
+	if r.chatID != "" {
+		return adapter.SendForward(r.ctx, r.adapter, "0", r.chatID, nodes)
+	}
+	return adapter.SendForward(r.ctx, r.adapter, r.userID, "0", nodes)
+}
+
+// Reply 回复当前事件
+func Reply(ctx context.Context, msg message.Message) (string, error) {
+	r, err := FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return r.Reply(msg)
+}
+
+// ReplyQuote 引用当前事件的消息进行回复
+func ReplyQuote(ctx context.Context, msg message.Message) (string, error) {
+	r, err := FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplyQuote(msg)
+}
+
+// ReplyAt @ 当前事件的触发者进行回复
+func ReplyAt(ctx context.Context, msg message.Message) (string, error) {
+	r, err := FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplyAt(msg)
+}
+
+func prepend(msg message.Message, segs ...message.Segment) message.Message {
+	if msg == nil {
+		return message.New(segs...)
+	}
+	return message.New(append(segs, msg.Segments()...)...)
+}
@@ -0,0 +1,180 @@
+package replier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+	"yora/pkg/hook"
+	"yora/pkg/message"
+	"yora/pkg/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sent struct {
+	selfID, userID, groupID string
+	msg                     message.Message
+}
+
+type fakeAdapter struct {
+	adapter.Adapter
+	sent []sent
+}
+
+func (a *fakeAdapter) Protocol() adapter.Protocol { return adapter.ProtocolOneBot }
+
+func (a *fakeAdapter) Send(ctx context.Context, userID, groupID string, msg message.Message) (string, error) {
+	a.sent = append(a.sent, sent{adapter.SelfIDFromContext(ctx), userID, groupID, msg})
+	return "42", nil
+}
+
+type fakeMessageEvent struct {
+	event.MessageEvent
+	group bool
+}
+
+func (e *fakeMessageEvent) UserID() string    { return "1001" }
+func (e *fakeMessageEvent) ChatID() string    { return "2002" }
+func (e *fakeMessageEvent) MessageID() string { return "3003" }
+func (e *fakeMessageEvent) IsGroup() bool     { return e.group }
+
+func TestReplyRoutesToOriginChat(t *testing.T) {
+	a := &fakeAdapter{}
+	ctx := adapter.WithSelfID(adapter.WithAdapter(context.Background(), a), "10000")
+
+	r, err := New(ctx, &fakeMessageEvent{group: true})
+	require.NoError(t, err)
+
+	id, err := r.ReplyText("hi")
+	require.NoError(t, err)
+	assert.Equal(t, "42", id)
+
+	_, err = r.ReplyQuote(message.Text("quoted"))
+	require.NoError(t, err)
+	_, err = r.ReplyAt(message.Text("at"))
+	require.NoError(t, err)
+
+	require.Len(t, a.sent, 3)
+	assert.Equal(t, sent{"10000", "0", "2002", message.Text("hi")}, a.sent[0])
+
+	quote := a.sent[1].msg.Segments()
+	assert.Equal(t, "reply", quote[0].Type())
+	assert.Equal(t, map[string]any{"id": "3003"}, quote[0].Data())
+
+	at := a.sent[2].msg.Segments()
+	assert.Equal(t, "at", at[0].Type())
+	assert.Equal(t, "at", a.sent[2].msg.PlainText()[1:])
+}
+
+func TestReplyPrivate(t *testing.T) {
+	a := &fakeAdapter{}
+	r, err := New(adapter.WithAdapter(context.Background(), a), &fakeMessageEvent{})
+	require.NoError(t, err)
+
+	_, err = r.ReplyAt(message.Text("hi"))
+	require.NoError(t, err)
+	assert.Equal(t, "1001", a.sent[0].userID)
+	assert.Equal(t, "0", a.sent[0].groupID)
+	assert.Equal(t, "hi", a.sent[0].msg.PlainText())
+}
+
+func TestNewRequiresAdapter(t *testing.T) {
+	_, err := New(context.Background(), &fakeMessageEvent{})
+	assert.ErrorIs(t, err, ErrNoAdapter)
+}
+
+func TestSendHookCanVetoReply(t *testing.T) {
+	a := &fakeAdapter{}
+	ctx := adapter.WithAdapter(context.Background(), a)
+
+	var target string
+	id := hook.RegisterGlobalHook(hook.MessageOnSend, func(hc *hook.HookContext) error {
+		mhc, ok := hook.As[*hook.MessageHookContext](hc)
+		require.True(t, ok)
+		target = mhc.GroupID
+		if mhc.Message.(message.Message).String() == "blocked" {
+			return errors.New("敏感内容")
+		}
+		return nil
+	})
+	defer hook.GlobalHookManager().RemoveHook(hook.MessageOnSend, id)
+
+	r, err := New(ctx, &fakeMessageEvent{group: true})
+	require.NoError(t, err)
+
+	_, err = r.ReplyText("blocked")
+	assert.Error(t, err)
+	assert.Empty(t, a.sent)
+	assert.Equal(t, "2002", target)
+
+	_, err = r.ReplyText("ok")
+	assert.NoError(t, err)
+	assert.Len(t, a.sent, 1)
+}
+
+// 支持合并转发的适配器
+type forwardAdapter struct {
+	fakeAdapter
+	userID, groupID string
+	nodes           []message.ForwardNode
+}
+
+func (a *forwardAdapter) SendForward(ctx context.Context, userID, groupID string, nodes []message.ForwardNode) (string, error) {
+	a.userID, a.groupID, a.nodes = userID, groupID, nodes
+	return "43", nil
+}
+
+func TestReplyForward(t *testing.T) {
+	node := message.NewForwardNode("10000", "bot", message.Text("hi"))
+
+	r, err := New(adapter.WithAdapter(context.Background(), &fakeAdapter{}), &fakeMessageEvent{group: true})
+	require.NoError(t, err)
+	_, err = r.ReplyForward(node)
+	assert.ErrorIs(t, err, adapter.ErrForwardUnsupported)
+
+	a := &forwardAdapter{}
+	r, err = New(adapter.WithAdapter(context.Background(), a), &fakeMessageEvent{group: true})
+	require.NoError(t, err)
+	id, err := r.ReplyForward(node)
+	require.NoError(t, err)
+	assert.Equal(t, "43", id)
+	assert.Equal(t, "0", a.userID)
+	assert.Equal(t, "2002", a.groupID)
+	assert.Equal(t, []message.ForwardNode{node}, a.nodes)
+
+	r, err = New(adapter.WithAdapter(context.Background(), a), &fakeMessageEvent{})
+	require.NoError(t, err)
+	_, err = r.ReplyForward(node)
+	require.NoError(t, err)
+	assert.Equal(t, "1001", a.userID)
+	assert.Equal(t, "0", a.groupID)
+}
+
+func TestReplyForwardRunsOutboundAndHook(t *testing.T) {
+	rewrite := middleware.OutboundFunc("rewrite", func(ctx context.Context, out *middleware.Outgoing, next middleware.SendFunc) (string, error) {
+		require.True(t, out.IsForward())
+		out.Forward[0].Content = message.Text("rewritten")
+		return next(ctx, out)
+	})
+
+	var hooked any
+	id := hook.RegisterGlobalHook(hook.MessageOnSend, func(hc *hook.HookContext) error {
+		mhc, _ := hook.As[*hook.MessageHookContext](hc)
+		hooked = mhc.Message
+		return nil
+	})
+	defer hook.GlobalHookManager().RemoveHook(hook.MessageOnSend, id)
+
+	a := &forwardAdapter{}
+	ctx := adapter.WithOutbound(adapter.WithAdapter(context.Background(), a), []middleware.OutboundMiddleware{rewrite})
+	r, err := New(ctx, &fakeMessageEvent{group: true})
+	require.NoError(t, err)
+
+	_, err = r.ReplyForward(message.NewForwardNode("10000", "bot", message.Text("hi")))
+	require.NoError(t, err)
+	assert.Equal(t, "rewritten", a.nodes[0].Content.PlainText())
+	assert.Equal(t, a.nodes, hooked)
+}
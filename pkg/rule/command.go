@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"yora/pkg/command"
 	"yora/pkg/event"
 )
 
@@ -77,6 +78,7 @@ func Command(caseSensitive bool, cmds ...string) Rule {
 			for _, cmd := range cmds {
 				match := cmd
 				if !caseSensitive {
+					match = strings.ToLower(cmd)
 				}
 				if strings.HasPrefix(message, match) {
 					return true
@@ -87,6 +89,23 @@ func Command(caseSensitive bool, cmds ...string) Rule {
 	})
 }
 
+// ShellCommand 命令树规则：消息的第一个参数为命令名或其别名
+func ShellCommand(cmd *command.Command) Rule {
+	parser := command.NewParser("")
+	parser.Register(cmd)
+	return CommandParser(parser)
+}
+
+// CommandParser 消息的第一个参数为解析器中注册的命令，与解析器使用相同的消息切分
+func CommandParser(p *command.Parser) Rule {
+	return RuleFunc(func(ctx context.Context, e event.Event) bool {
+		if msgEvent, ok := e.(event.MessageEvent); ok {
+			return p.Matches(msgEvent.Message())
+		}
+		return false
+	})
+}
+
 // Regex 正则表达式规则
 func Regex(pattern string) Rule {
 	re := regexp.MustCompile(pattern)
@@ -2,6 +2,7 @@ package rule
 
 import (
 	"context"
+	"slices"
 	"yora/pkg/event"
 )
 
@@ -23,6 +24,25 @@ func IsNoticeEvent() RuleFunc {
 	}
 }
 
+// IsNoticeType 通知类型为 noticeType，指定 subTypes 时子类型需为其中之一
+func IsNoticeType(noticeType string, subTypes ...string) RuleFunc {
+	return func(ctx context.Context, e event.Event) bool {
+		n, ok := e.(event.NoticeEvent)
+		if !ok || n.NoticeType() != noticeType {
+			return false
+		}
+		return len(subTypes) == 0 || slices.Contains(subTypes, n.SubType())
+	}
+}
+
+// IsEventOf 事件为具体类型 T（如适配器定义的通知类型）
+func IsEventOf[T event.Event]() RuleFunc {
+	return func(ctx context.Context, e event.Event) bool {
+		_, ok := e.(T)
+		return ok
+	}
+}
+
 func IsRequestEvent() RuleFunc {
 	return func(ctx context.Context, event event.Event) bool {
 		return event.Type() == "request"
@@ -0,0 +1,238 @@
+// Package session 提供交互式会话：在 handler 中向用户提问并等待同一用户在同一会话中的下一条消息。
+//
+// 基本用法：
+//
+//	func (p *poll) create(ctx context.Context) error {
+//		s, err := session.New(ctx, session.WithTimeout(time.Minute))
+//		if err != nil {
+//			return err
+//		}
+//		reply, err := s.Prompt(messages.New("投票标题是？"))
+//		for err == nil && strings.TrimSpace(reply.RawMessage()) == "" {
+//			reply, err = s.Reject(messages.New("标题不能为空，请重新输入"))
+//		}
+//		...
+//	}
+//
+// 等待通过注册到 MatcherRegistry 的临时高优先级匹配器实现，回复事件经过入站中间件后、进入事件队列前即被消费。
+// 开始等待时会话调用 handler.Detach 释放事件 worker，等待不会阻塞其他事件的处理。
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/plugin"
+	"yora/pkg/replier"
+	"yora/pkg/rule"
+)
+
+var (
+	ErrTimeout        = errors.New("等待回复超时")
+	ErrCanceled       = errors.New("用户取消了会话")
+	ErrTooManyRejects = errors.New("重试次数过多")
+	ErrNotMessage     = errors.New("当前事件不是消息事件，无法开启会话")
+)
+
+const (
+	// 临时匹配器优先级，高于所有普通匹配器
+	Priority = math.MaxInt32
+
+	DefaultTimeout    = 60 * time.Second
+	DefaultMaxRejects = 3
+)
+
+// 默认取消关键词
+var DefaultCancelWords = []string{"取消", "cancel"}
+
+// 会话选项
+type Option func(s *Session)
+
+// 设置单次等待的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Session) {
+		s.timeout = timeout
+	}
+}
+
+// 设置取消关键词（完全匹配，忽略首尾空白）
+func WithCancelWords(words ...string) Option {
+	return func(s *Session) {
+		s.cancelWords = words
+	}
+}
+
+// 设置最大重试次数，小于等于 0 表示不限制
+func WithMaxRejects(n int) Option {
+	return func(s *Session) {
+		s.maxRejects = n
+	}
+}
+
+// 交互式会话
+type Session struct {
+	ctx    context.Context
+	origin event.MessageEvent // 发起会话的事件
+	last   event.MessageEvent // 最近一次收到的回复
+
+	timeout     time.Duration
+	cancelWords []string
+	maxRejects  int
+	rejects     int
+}
+
+// 基于当前事件创建会话
+func New(ctx context.Context, opts ...Option) (*Session, error) {
+	scope, ok := handler.ScopeFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("上下文中缺少事件作用域")
+	}
+	origin, ok := scope.Event().(event.MessageEvent)
+	if !ok {
+		return nil, ErrNotMessage
+	}
+
+	s := &Session{
+		ctx:         ctx,
+		origin:      origin,
+		timeout:     DefaultTimeout,
+		cancelWords: DefaultCancelWords,
+		maxRejects:  DefaultMaxRejects,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// 发送提示并等待回复（一次性会话的便捷写法）
+func Prompt(ctx context.Context, prompt message.Message, opts ...Option) (event.MessageEvent, error) {
+	s, err := New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s.Prompt(prompt)
+}
+
+// 发送提示（可为 nil）并等待同一用户在同一会话中的下一条消息
+//
+// 临时匹配器在发送提示前注册，提示发出后立即到达的回复也不会被普通匹配器处理。
+func (s *Session) Prompt(prompt message.Message) (event.MessageEvent, error) {
+	replyCh, stop := s.listen()
+	defer stop()
+
+	if err := s.send(prompt); err != nil {
+		return nil, err
+	}
+	return s.wait(replyCh)
+}
+
+// 输入无效时重新提问，超过最大重试次数返回 ErrTooManyRejects
+func (s *Session) Reject(prompt message.Message) (event.MessageEvent, error) {
+	s.rejects++
+	if s.maxRejects > 0 && s.rejects > s.maxRejects {
+		return nil, ErrTooManyRejects
+	}
+	return s.Prompt(prompt)
+}
+
+// 发起会话的事件
+func (s *Session) Origin() event.MessageEvent {
+	return s.origin
+}
+
+// 最近一次收到的回复
+func (s *Session) Last() event.MessageEvent {
+	return s.last
+}
+
+// 注册临时匹配器，返回接收回复的通道与注销函数
+func (s *Session) listen() (<-chan event.MessageEvent, func()) {
+	var taken atomic.Bool
+	replyCh := make(chan event.MessageEvent, 1)
+
+	m := plugin.NewMatcher(
+		rule.RuleFunc(func(ctx context.Context, e event.Event) bool {
+			return !taken.Load() && s.isReply(e)
+		}),
+		handler.NewHandler(func(e event.MessageEvent) {
+			if taken.CompareAndSwap(false, true) {
+				replyCh <- e
+			}
+		}),
+	).SetPriority(Priority).SetBlock(true).SetTemporary(true)
+
+	registry := plugin.GetMatcherRegistry()
+	registry.RegisterMatchers(m)
+	return replyCh, func() { registry.UnregisterMatchers(m) }
+}
+
+// 等待回复
+func (s *Session) wait(replyCh <-chan event.MessageEvent) (event.MessageEvent, error) {
+	// 等待期间释放事件 worker，避免阻塞同一 worker 上的其他会话
+	handler.Detach(s.ctx)
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case e := <-replyCh:
+		s.last = e
+		if s.isCancel(e) {
+			return e, ErrCanceled
+		}
+		return e, nil
+	case <-timer.C:
+		return nil, ErrTimeout
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// 是否为同一账号、同一会话、同一用户的消息
+func (s *Session) isReply(e event.Event) bool {
+	msg, ok := e.(event.MessageEvent)
+	if !ok {
+		return false
+	}
+	if msg.SelfID() != s.origin.SelfID() || msg.UserID() != s.origin.UserID() {
+		return false
+	}
+	if msg.IsGroup() != s.origin.IsGroup() {
+		return false
+	}
+	return !msg.IsGroup() || msg.ChatID() == s.origin.ChatID()
+}
+
+func (s *Session) isCancel(e event.MessageEvent) bool {
+	text := strings.TrimSpace(e.RawMessage())
+	for _, w := range s.cancelWords {
+		if text == w {
+			return true
+		}
+	}
+	return false
+}
+
+// 通过事件来源适配器发送提示
+func (s *Session) send(msg message.Message) error {
+	if msg == nil || msg.IsEmpty() {
+		return nil
+	}
+
+	r, err := replier.New(s.ctx, s.origin)
+	if err != nil {
+		return fmt.Errorf("无法发送提示: %w", err)
+	}
+	if _, err := r.Reply(msg); err != nil {
+		return fmt.Errorf("发送提示失败: %w", err)
+	}
+	return nil
+}
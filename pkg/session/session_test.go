@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/message"
+	"yora/pkg/plugin"
+	"yora/pkg/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type msgEvent struct {
+	event.MessageEvent
+	userID, groupID, text string
+}
+
+func (e *msgEvent) Type() string             { return "message" }
+func (e *msgEvent) SelfID() string           { return "10000" }
+func (e *msgEvent) UserID() string           { return e.userID }
+func (e *msgEvent) ChatID() string           { return e.groupID }
+func (e *msgEvent) IsGroup() bool            { return e.groupID != "" }
+func (e *msgEvent) MessageID() string        { return "1" }
+func (e *msgEvent) RawMessage() string       { return e.text }
+func (e *msgEvent) Message() message.Message { return message.Text(e.text) }
+
+// 发送提示时回调 onSend 的适配器
+type promptAdapter struct {
+	adapter.Adapter
+	prompts []string
+	onSend  func()
+}
+
+func (a *promptAdapter) Protocol() adapter.Protocol { return "test" }
+
+func (a *promptAdapter) Send(ctx context.Context, userID, groupID string, msg message.Message) (string, error) {
+	a.prompts = append(a.prompts, msg.PlainText())
+	if a.onSend != nil {
+		a.onSend()
+	}
+	return "1", nil
+}
+
+func newSession(t *testing.T, a *promptAdapter, opts ...Option) *Session {
+	origin := &msgEvent{userID: "20001", groupID: "30001", text: "投票"}
+	ctx := adapter.WithAdapter(context.Background(), a)
+	ctx = handler.WithScope(ctx, handler.NewScope(origin, provider.MessageEvent()))
+
+	s, err := New(ctx, opts...)
+	require.NoError(t, err)
+	return s
+}
+
+// 模拟事件分发器将事件交给等待中的会话，返回是否被会话消费
+func deliver(e event.MessageEvent) bool {
+	ctx := handler.WithScope(context.Background(), handler.NewScope(e, provider.MessageEvent()))
+	matched := plugin.GetMatcherRegistry().MatchTemporary(ctx, e)
+	for _, m := range matched {
+		m.Call(ctx, e)
+	}
+	return len(matched) > 0
+}
+
+func TestPromptReturnsReply(t *testing.T) {
+	a := &promptAdapter{}
+	s := newSession(t, a)
+
+	// 提示发出时临时匹配器已注册，立即到达的回复也能收到
+	reply := &msgEvent{userID: "20001", groupID: "30001", text: "午饭吃什么"}
+	a.onSend = func() { assert.True(t, deliver(reply)) }
+
+	got, err := s.Prompt(message.Text("投票标题是？"))
+	require.NoError(t, err)
+	assert.Equal(t, reply, got)
+	assert.Equal(t, reply, s.Last())
+	assert.Equal(t, []string{"投票标题是？"}, a.prompts)
+
+	// 会话结束后临时匹配器已注销
+	assert.False(t, deliver(reply))
+}
+
+func TestPromptTimeout(t *testing.T) {
+	s := newSession(t, &promptAdapter{}, WithTimeout(10*time.Millisecond))
+
+	_, err := s.Prompt(nil)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestPromptCancelWord(t *testing.T) {
+	a := &promptAdapter{}
+	s := newSession(t, a, WithCancelWords("算了"))
+	a.onSend = func() { deliver(&msgEvent{userID: "20001", groupID: "30001", text: " 算了 "}) }
+
+	_, err := s.Prompt(message.Text("投票标题是？"))
+	assert.ErrorIs(t, err, ErrCanceled)
+}
+
+func TestRejectTooManyTimes(t *testing.T) {
+	a := &promptAdapter{}
+	s := newSession(t, a, WithMaxRejects(2))
+	a.onSend = func() { deliver(&msgEvent{userID: "20001", groupID: "30001", text: ""}) }
+
+	_, err := s.Prompt(message.Text("投票标题是？"))
+	require.NoError(t, err)
+	for range 2 {
+		_, err = s.Reject(message.Text("标题不能为空"))
+		require.NoError(t, err)
+	}
+
+	_, err = s.Reject(message.Text("标题不能为空"))
+	assert.ErrorIs(t, err, ErrTooManyRejects)
+	assert.Len(t, a.prompts, 3)
+}
+
+func TestIsReply(t *testing.T) {
+	s := newSession(t, &promptAdapter{})
+
+	assert.True(t, s.isReply(&msgEvent{userID: "20001", groupID: "30001"}))
+	assert.False(t, s.isReply(&msgEvent{userID: "20002", groupID: "30001"}), "其他用户")
+	assert.False(t, s.isReply(&msgEvent{userID: "20001", groupID: "30002"}), "其他群")
+	assert.False(t, s.isReply(&msgEvent{userID: "20001"}), "私聊")
+}
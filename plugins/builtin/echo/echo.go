@@ -4,13 +4,13 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"yora/adapters/onebot/events"
-	"yora/adapters/onebot/messages"
-	"yora/pkg/bot"
+	"yora/pkg/event"
 	"yora/pkg/handler"
 	"yora/pkg/log"
+	"yora/pkg/message"
 	"yora/pkg/on"
 	"yora/pkg/plugin"
+	"yora/pkg/replier"
 
 	"github.com/rs/zerolog"
 )
@@ -52,33 +52,29 @@ func (e *echo) Matchers() []*plugin.Matcher {
 	}
 }
 
-func (e *echo) echo(evt *events.MessageEvent, bot bot.Bot) error {
-	var msgs = messages.NewMessage()
+func (e *echo) echo(evt event.MessageEvent, r *replier.Replier) error {
+	msgs := message.NewBuilder()
 
 	var echoRegex = regexp.MustCompile(`(?i)echo`)
 
-	for _, seg := range evt.Message().Segments() {
-		if seg.Type() == "text" {
+	for _, seg := range evt.StandardMessage().Segments() {
+		if seg.Type() == message.TypeText {
 			content := seg.String()
 			cleaned := strings.TrimSpace(echoRegex.ReplaceAllString(content, ""))
 			if cleaned != "" {
-				s := messages.NewTextSegment(cleaned)
-				msgs = msgs.Append(s)
+				msgs.Text(cleaned)
 			}
 			continue
 		}
-		msgs = msgs.Append(seg)
+		msgs.Segment(seg)
 	}
 
-	if msgs.IsEmpty() {
+	reply := msgs.Build()
+	if reply.IsEmpty() {
 		return nil
 	}
 	time.Sleep(time.Second * 5)
-	if evt.IsGroup() {
-		bot.Send("0", evt.ChatID(), msgs)
-	} else {
-		bot.Send(evt.UserID(), "0", msgs)
-	}
-	return nil
+	_, err := r.Reply(reply)
+	return err
 
 }
@@ -1,14 +1,17 @@
 package help
 
 import (
-	"yora/adapters/onebot/events"
-	"yora/adapters/onebot/messages"
 	"yora/pkg/bot"
+	"yora/pkg/command"
+	"yora/pkg/event"
 	"yora/pkg/handler"
+	"yora/pkg/log"
+	"yora/pkg/message"
 	"yora/pkg/on"
-	"yora/pkg/params"
 	"yora/pkg/plugin"
-	"yora/pkg/provider"
+	"yora/pkg/replier"
+
+	"github.com/rs/zerolog"
 )
 
 var _ plugin.Plugin = (*helper)(nil)
@@ -19,64 +22,94 @@ var pluginMeta = &plugin.PluginInfo{
 	Description: "提供帮助信息",
 	Version:     "0.1.0",
 	Author:      "月离",
-	Usage:       "help [插件ID]",
+	Usage:       "help [插件ID|命令] [-p 页码]",
+	Examples:    []string{"help", "help echo", "help -p 2"},
 	Group:       "builtin",
 	Extra:       nil,
 }
 
 func New() plugin.Plugin {
-	return &helper{}
+	return &helper{
+		logger: log.NewPlugin("help"),
+	}
 }
 
 type helper struct {
+	logger zerolog.Logger
+}
+
+// help 命令参数
+type helpArgs struct {
+	Target string `arg:"0,optional" name:"target" help:"插件ID或命令名"`
+	Page   int    `opt:"p,page" default:"1" help:"页码"`
 }
 
 func (h *helper) Matchers() []*plugin.Matcher {
-	helpHandler := handler.NewHandler(h.listPlugins).RegisterProviders(provider.CommandArgs([]string{"help"}))
-	helpMatcher := on.OnCommand([]string{"help"}, true, helpHandler).SetPlugin(h)
+	cmd := &command.Command{
+		Name:    "help",
+		Aliases: []string{"帮助"},
+		Help:    "查看插件列表、插件详情或命令用法",
+	}
+	helpMatcher := on.OnShellCommand(cmd, handler.NewHandler(h.help)).SetPlugin(h)
 
 	return []*plugin.Matcher{helpMatcher}
-
 }
 
 func (h *helper) PluginInfo() *plugin.PluginInfo {
 	return pluginMeta
 }
 
-// PluginInfo implements plugin.Plugin.
+func (h *helper) help(b bot.Bot, r *replier.Replier, e event.MessageEvent, args *helpArgs) {
+	h.reply(r, e, h.render(b.Plugins(), args))
+}
 
-func (h *helper) listPlugins(bot bot.Bot, event *events.MessageEvent, params *params.CommandArgs) {
-	plugins := bot.Plugins()
-	// 基于 group 分组
+// 根据参数生成帮助页：无参数时列出插件，否则依次按插件ID、命令名查找
+func (h *helper) render(plugins []plugin.Plugin, args *helpArgs) page {
+	if args.Target == "" {
+		return paginate("可用插件（help <插件ID> 查看详情）", renderPluginList(plugins), args.Page)
+	}
 
-	filtered := make(map[string][]plugin.Plugin)
+	registry := plugin.GetMatcherRegistry()
 	for _, p := range plugins {
-		if p.PluginInfo().Group == "" {
-			filtered[""] = append(filtered[""], p)
-		} else {
-			filtered[p.PluginInfo().Group] = append(filtered[p.PluginInfo().Group], p)
+		if p.PluginInfo().ID == args.Target {
+			return paginate("插件帮助", renderPlugin(p, registry.MatchersByPlugin(args.Target)), args.Page)
 		}
 	}
 
-	msgs := ""
-
-	for group, ps := range filtered {
-		if group == "" {
-			msgs += "Available plugins:\n"
-			for _, p := range ps {
-				msgs += "- " + p.PluginInfo().Name + "\n"
-			}
-		} else {
-			msgs += "Available plugins in group " + group + ":\n"
-			for _, p := range ps {
-				msgs += "- " + p.PluginInfo().Name + "\n"
-			}
+	for _, p := range plugins {
+		if m := findCommand(registry.MatchersByPlugin(p.PluginInfo().ID), args.Target); m != nil {
+			return paginate("命令帮助（所属插件："+p.PluginInfo().Name+"）", []string{renderCommand(m)}, 1)
 		}
 	}
 
-	if event.IsGroup() {
-		bot.Send("0", event.ChatID(), messages.New(msgs))
-	} else {
-		bot.Send(event.UserID(), "0", messages.New(msgs))
+	return page{title: "未找到插件或命令：" + args.Target, current: 1, total: 1}
+}
+
+// 群聊中多条目使用合并转发，避免刷屏；适配器不支持或发送失败时直接发送
+func (h *helper) reply(r *replier.Replier, e event.MessageEvent, p page) {
+	if !e.IsGroup() || len(p.sections) <= 1 {
+		r.ReplyText(p.String())
+		return
+	}
+
+	if _, err := r.ReplyForward(forwardNodes(e.SelfID(), p)...); err != nil {
+		h.logger.Warn().Err(err).Msg("合并转发帮助信息失败，改为直接发送")
+		r.ReplyText(p.String())
+	}
+}
+
+// 帮助页的标题、每个条目和页脚各为一个转发节点
+func forwardNodes(selfID string, p page) []message.ForwardNode {
+	node := func(text string) message.ForwardNode {
+		return message.NewForwardNode(selfID, pluginMeta.Name, message.Text(text))
+	}
+
+	nodes := []message.ForwardNode{node(p.title)}
+	for _, s := range p.sections {
+		nodes = append(nodes, node(s))
+	}
+	if footer := p.footer(); footer != "" {
+		nodes = append(nodes, node(footer))
 	}
+	return nodes
 }
@@ -0,0 +1,71 @@
+package help
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+	"yora/pkg/message"
+	"yora/pkg/replier"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 记录发送内容的适配器，不支持合并转发
+type sendAdapter struct {
+	adapter.Adapter
+	texts []string
+	nodes []message.ForwardNode
+}
+
+func (a *sendAdapter) Protocol() adapter.Protocol { return "test" }
+
+func (a *sendAdapter) Send(ctx context.Context, userID, groupID string, msg message.Message) (string, error) {
+	a.texts = append(a.texts, msg.PlainText())
+	return "1", nil
+}
+
+type forwardAdapter struct {
+	sendAdapter
+}
+
+func (a *forwardAdapter) SendForward(ctx context.Context, userID, groupID string, nodes []message.ForwardNode) (string, error) {
+	a.nodes = nodes
+	return "2", nil
+}
+
+type groupEvent struct {
+	event.MessageEvent
+}
+
+func (e *groupEvent) SelfID() string    { return "10" }
+func (e *groupEvent) UserID() string    { return "1" }
+func (e *groupEvent) ChatID() string    { return "2" }
+func (e *groupEvent) MessageID() string { return "3" }
+func (e *groupEvent) IsGroup() bool     { return true }
+
+func TestReplyForwardInGroup(t *testing.T) {
+	h := New().(*helper)
+	p := page{title: "可用插件", sections: []string{"a", "b"}, current: 1, total: 2}
+
+	fa := &forwardAdapter{}
+	r, err := replier.New(adapter.WithAdapter(context.Background(), fa), &groupEvent{})
+	require.NoError(t, err)
+	h.reply(r, &groupEvent{}, p)
+
+	assert.Empty(t, fa.texts)
+	require.Len(t, fa.nodes, 4)
+	assert.Equal(t, "10", fa.nodes[0].UserID)
+	assert.Equal(t, pluginMeta.Name, fa.nodes[0].Nickname)
+	assert.Equal(t, "可用插件", fa.nodes[0].Content.PlainText())
+	assert.Equal(t, "b", fa.nodes[2].Content.PlainText())
+	assert.Equal(t, p.footer(), fa.nodes[3].Content.PlainText())
+
+	// 适配器不支持合并转发时直接发送文本
+	sa := &sendAdapter{}
+	r, err = replier.New(adapter.WithAdapter(context.Background(), sa), &groupEvent{})
+	require.NoError(t, err)
+	h.reply(r, &groupEvent{}, p)
+	assert.Equal(t, []string{p.String()}, sa.texts)
+}
@@ -0,0 +1,146 @@
+package help
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"yora/pkg/permission"
+	"yora/pkg/plugin"
+)
+
+// 每页条目数
+const pageSize = 8
+
+// 帮助页，sections 中每一项为一个独立的帮助条目
+type page struct {
+	title    string
+	sections []string
+	current  int
+	total    int
+}
+
+// 转为纯文本
+func (p page) String() string {
+	var b strings.Builder
+	b.WriteString(p.title)
+	for _, s := range p.sections {
+		b.WriteString("\n\n")
+		b.WriteString(s)
+	}
+	if footer := p.footer(); footer != "" {
+		b.WriteString("\n\n")
+		b.WriteString(footer)
+	}
+	return b.String()
+}
+
+func (p page) footer() string {
+	if p.total <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("第 %d/%d 页，使用 -p <页码> 翻页", p.current, p.total)
+}
+
+// 分页，页码越界时取最近的有效页
+func paginate(title string, sections []string, n int) page {
+	total := (len(sections) + pageSize - 1) / pageSize
+	if total == 0 {
+		return page{title: title, current: 1, total: 1}
+	}
+	n = max(1, min(n, total))
+
+	start := (n - 1) * pageSize
+	end := min(start+pageSize, len(sections))
+	return page{title: title, sections: sections[start:end], current: n, total: total}
+}
+
+// 插件列表（按分组、ID 排序）
+func renderPluginList(plugins []plugin.Plugin) []string {
+	groups := make(map[string][]*plugin.PluginInfo)
+	for _, p := range plugins {
+		info := p.PluginInfo()
+		groups[info.Group] = append(groups[info.Group], info)
+	}
+
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+
+	var sections []string
+	for _, g := range names {
+		infos := groups[g]
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].ID < infos[j].ID
+		})
+
+		var b strings.Builder
+		if g == "" {
+			b.WriteString("[未分组]")
+		} else {
+			fmt.Fprintf(&b, "[%s]", g)
+		}
+		for _, info := range infos {
+			fmt.Fprintf(&b, "\n- %s %s", info.ID, info.Name)
+			if info.Description != "" {
+				fmt.Fprintf(&b, "：%s", info.Description)
+			}
+		}
+		sections = append(sections, b.String())
+	}
+	return sections
+}
+
+// 插件详情：插件信息及其各个命令
+func renderPlugin(p plugin.Plugin, matchers []*plugin.Matcher) []string {
+	info := p.PluginInfo()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)", info.Name, info.ID)
+	if info.Version != "" {
+		fmt.Fprintf(&b, " v%s", info.Version)
+	}
+	if info.Author != "" {
+		fmt.Fprintf(&b, "\n作者：%s", info.Author)
+	}
+	if info.Description != "" {
+		fmt.Fprintf(&b, "\n%s", info.Description)
+	}
+	if info.Usage != "" {
+		fmt.Fprintf(&b, "\n用法：%s", info.Usage)
+	}
+	if len(info.Examples) > 0 {
+		b.WriteString("\n示例：")
+		for _, e := range info.Examples {
+			fmt.Fprintf(&b, "\n  %s", e)
+		}
+	}
+
+	sections := []string{b.String()}
+	for _, m := range matchers {
+		if m.Command != nil {
+			sections = append(sections, renderCommand(m))
+		}
+	}
+	return sections
+}
+
+// 命令详情：用法、参数、选项、别名、子命令及所需权限
+func renderCommand(m *plugin.Matcher) string {
+	return fmt.Sprintf("%s\n权限：%s", m.Command.Usage(), permission.Describe(m.Permission))
+}
+
+// 根据命令名或别名查找匹配器
+func findCommand(matchers []*plugin.Matcher, name string) *plugin.Matcher {
+	for _, m := range matchers {
+		if m.Command == nil {
+			continue
+		}
+		if slices.Contains(m.Command.Names(), name) {
+			return m
+		}
+	}
+	return nil
+}
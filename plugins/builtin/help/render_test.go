@@ -0,0 +1,66 @@
+package help
+
+import (
+	"fmt"
+	"testing"
+	"yora/pkg/command"
+	"yora/pkg/permission"
+	"yora/pkg/plugin"
+	"yora/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePlugin struct {
+	info *plugin.PluginInfo
+}
+
+func (f *fakePlugin) PluginInfo() *plugin.PluginInfo { return f.info }
+func (f *fakePlugin) Matchers() []*plugin.Matcher    { return nil }
+
+func TestRenderPlugin(t *testing.T) {
+	p := &fakePlugin{info: &plugin.PluginInfo{
+		ID:          "ban",
+		Name:        "禁言",
+		Description: "群禁言管理",
+		Examples:    []string{"ban @user -t 10"},
+	}}
+
+	cmd := &command.Command{
+		Name:    "ban",
+		Aliases: []string{"禁言"},
+		Args:    []command.Arg{{Name: "user", Type: "at", Help: "要禁言的用户"}},
+		Options: []command.Opt{{Short: "t", Long: "time", Type: "int", Default: 10, Help: "禁言时长"}},
+	}
+	m := plugin.NewMatcher(rule.ShellCommand(cmd)).SetCommand(cmd)
+	m.Permission = permission.GroupAdminOrOwner()
+
+	sections := renderPlugin(p, []*plugin.Matcher{m, plugin.NewMatcher(nil)})
+	assert.Len(t, sections, 2)
+	assert.Contains(t, sections[0], "禁言 (ban)")
+	assert.Contains(t, sections[0], "ban @user -t 10")
+	assert.Contains(t, sections[1], "用法：ban <user> [选项]")
+	assert.Contains(t, sections[1], "别名：禁言")
+	assert.Contains(t, sections[1], "-t, --time <int>  禁言时长（默认：10）")
+	assert.Contains(t, sections[1], "权限：超级用户/群主/群管理员")
+
+	assert.Equal(t, m, findCommand([]*plugin.Matcher{m}, "禁言"))
+}
+
+func TestPaginate(t *testing.T) {
+	sections := make([]string, pageSize*2+1)
+	for i := range sections {
+		sections[i] = fmt.Sprint(i)
+	}
+
+	p := paginate("title", sections, 3)
+	assert.Equal(t, []string{fmt.Sprint(pageSize * 2)}, p.sections)
+	assert.Contains(t, p.String(), "第 3/3 页")
+
+	p = paginate("title", sections, 99)
+	assert.Equal(t, 3, p.current)
+
+	p = paginate("title", sections[:1], 0)
+	assert.Equal(t, 1, p.current)
+	assert.NotContains(t, p.String(), "页码")
+}
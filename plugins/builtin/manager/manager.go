@@ -1,49 +1,226 @@
 package manager
 
 import (
-	"yora/adapters/onebot/events"
-	"yora/pkg/bot"
+	"context"
+	"fmt"
+	"strings"
+	"yora/pkg/command"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/log"
+	"yora/pkg/on"
+	"yora/pkg/permission"
 	"yora/pkg/plugin"
+	"yora/pkg/policy"
+	"yora/pkg/replier"
+
+	"github.com/rs/zerolog"
 )
 
-var _ plugin.Plugin = (*pluginDemo)(nil)
+var _ plugin.Plugin = (*manager)(nil)
+
+var pluginMeta = &plugin.PluginInfo{
+	ID:          "manager",
+	Name:        "插件管理",
+	Description: "在聊天中管理插件：查看状态、开关、重载、配置与健康检查（仅超级用户，群主和群管理员可开关本群插件）",
+	Version:     "0.1.0",
+	Author:      "月离",
+	Usage:       "plugins | enable/disable <插件ID[:匹配器]> [-g 群号] [--global] | reload <插件ID> | config <插件ID> [键] [值] | health",
+	Examples:    []string{"plugins", "disable echo", "enable echo --global", "config chat model gpt-4o", "health"},
+	Group:       "builtin",
+	Extra:       nil,
+}
 
-var pluginMeta = plugin.PluginInfo{
-	ID:          "",
-	Name:        "",
-	Description: "",
-	Version:     "",
-	Author:      "",
-	Usage:       "",
-	Examples:    []string{},
-	Group:       "",
-	Extra:       map[string]any{},
+// New 创建插件管理插件，superUsers 为可使用管理命令的超级用户
+func New(superUsers ...string) plugin.Plugin {
+	return &manager{
+		superUsers: superUsers,
+		registry:   plugin.GetPluginRegistry(),
+		policy:     policy.GetPolicy(),
+		logger:     log.NewPlugin("manager"),
+	}
 }
 
-func New() plugin.Plugin {
-	return &pluginDemo{}
+type manager struct {
+	superUsers []string
+	registry   *plugin.PluginRegistry
+	policy     *policy.Policy
+	logger     zerolog.Logger
 }
 
-type pluginDemo struct {
+// 开关命令参数
+type switchArgs struct {
+	Target string `arg:"0" name:"plugin" help:"插件ID，或 插件ID:匹配器名"`
+	Group  string `opt:"g,group" help:"群号，默认为当前群"`
+	Global bool   `opt:",global" help:"全局开关"`
 }
 
-// Matchers implements plugin.Plugin.
-func (e *pluginDemo) Matchers() []*plugin.Matcher {
-	panic("unimplemented")
+// 重载命令参数
+type reloadArgs struct {
+	ID string `arg:"0" name:"plugin" help:"插件ID"`
 }
 
-// PluginInfo implements plugin.Plugin.
-func (e *pluginDemo) PluginInfo() *plugin.PluginInfo {
-	panic("unimplemented")
+// 配置命令参数
+type configArgs struct {
+	ID    string `arg:"0" name:"plugin" help:"插件ID"`
+	Key   string `arg:"1,optional" name:"key" help:"配置项"`
+	Value string `arg:"2,optional" name:"value" help:"配置值，可为 JSON"`
 }
 
-func (e *pluginDemo) Load() error {
-	// cmdMatcher := on.OnCommand([]string{"echo"}, true, handler.NewHandler(e.echo))
+func (m *manager) Matchers() []*plugin.Matcher {
+	superUser := permission.SuperUser(m.superUsers...)
+	// 开关命令允许群主和群管理员使用，处理时再按 policy.CanManage 检查范围
+	groupAdmin := permission.GroupAdminOrOwner(m.superUsers...)
+
+	commands := []struct {
+		cmd  *command.Command
+		h    any
+		perm permission.Permission
+	}{
+		{&command.Command{Name: "plugins", Aliases: []string{"插件列表"}, Help: "查看插件状态与版本"}, m.list, superUser},
+		{&command.Command{Name: "enable", Aliases: []string{"启用"}, Help: "在本群、指定群或全局启用插件"}, m.switchHandler(true), groupAdmin},
+		{&command.Command{Name: "disable", Aliases: []string{"禁用"}, Help: "在本群、指定群或全局禁用插件"}, m.switchHandler(false), groupAdmin},
+		{&command.Command{Name: "reload", Aliases: []string{"重载"}, Help: "重新加载插件"}, m.reload, superUser},
+		{&command.Command{Name: "config", Aliases: []string{"插件配置"}, Help: "查看或修改插件配置"}, m.config, superUser},
+		{&command.Command{Name: "health", Aliases: []string{"健康检查"}, Help: "检查插件健康状态"}, m.health, superUser},
+	}
+
+	matchers := make([]*plugin.Matcher, 0, len(commands))
+	for _, c := range commands {
+		matcher := on.OnShellCommand(c.cmd, handler.NewHandler(c.h)).
+			SetPermission(c.perm).
+			SetPlugin(m)
+		matchers = append(matchers, matcher)
+	}
+	return matchers
+}
 
-	return nil
+func (m *manager) PluginInfo() *plugin.PluginInfo {
+	return pluginMeta
 }
 
-func (e *pluginDemo) echo(evt *events.MessageEvent, bot bot.Bot) error {
-	return nil
+func (m *manager) list(r *replier.Replier, e event.MessageEvent) {
+	groupID, _ := policy.Subject(e)
+	r.ReplyText(renderPluginList(m.registry.Plugins(), func(info *plugin.PluginInfo) string {
+		status := onOff(m.registry.IsPluginEnabled(info.ID) && m.policy.PluginEnabled(info, "", ""))
+		if groupID != "" {
+			status += "，本群" + onOff(m.policy.PluginEnabled(info, groupID, ""))
+		}
+		return status
+	}))
+}
+
+// 启用或禁用插件：默认作用于当前群，-g 指定群，--global 作用于全局
+// 群主和群管理员只能修改自己所在群的开关
+func (m *manager) switchHandler(enabled bool) func(ctx context.Context, r *replier.Replier, e event.MessageEvent, args *switchArgs) {
+	return func(ctx context.Context, r *replier.Replier, e event.MessageEvent, args *switchArgs) {
+		// 插件管理及其任一匹配器被禁用后都无法再通过聊天恢复
+		if id, _, _ := strings.Cut(args.Target, ":"); !enabled && id == pluginMeta.ID {
+			r.ReplyText("不能禁用插件管理本身")
+			return
+		}
+		if err := m.checkTarget(args.Target); err != nil {
+			r.ReplyText(err.Error())
+			return
+		}
+
+		scope, id, where := policy.ScopeGlobal, "", "全局"
+		if !args.Global {
+			id = args.Group
+			if id == "" {
+				id, _ = policy.Subject(e)
+			}
+			if id == "" {
+				r.ReplyText("私聊中请使用 -g <群号> 指定群，或使用 --global 全局开关")
+				return
+			}
+			scope, where = policy.ScopeGroup, "群 "+id
+		}
+		if !policy.CanManage(ctx, e, scope, id, m.superUsers...) {
+			r.ReplyText("群主和群管理员只能开关本群的插件，全局或其他群的开关需要超级用户")
+			return
+		}
+
+		if err := m.policy.Set(scope, id, args.Target, enabled); err != nil {
+			m.logger.Error().Err(err).Str("目标", args.Target).Msg("保存插件开关失败")
+			r.ReplyText("保存失败：" + err.Error())
+			return
+		}
+		r.ReplyText(fmt.Sprintf("已在%s%s %s", where, onOff(enabled), args.Target))
+	}
+}
+
+// 检查开关目标是否存在：插件ID 或 插件ID:匹配器名
+func (m *manager) checkTarget(target string) error {
+	id, name, _ := strings.Cut(target, ":")
+	if _, err := m.registry.GetPlugin(id); err != nil {
+		return err
+	}
+	if name == "" {
+		return nil
+	}
+	for _, matcher := range plugin.GetMatcherRegistry().MatchersByPlugin(id) {
+		if matcher.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("插件 %s 中未找到匹配器: %s", id, name)
+}
+
+func (m *manager) reload(r *replier.Replier, args *reloadArgs) {
+	if err := m.registry.ReloadPlugin(args.ID); err != nil {
+		r.ReplyText(err.Error())
+		return
+	}
+	r.ReplyText("已重新加载 " + args.ID)
+}
+
+// 查看全部配置、查看单项配置或设置配置项
+func (m *manager) config(r *replier.Replier, args *configArgs) {
+	p, err := m.registry.GetPlugin(args.ID)
+	if err != nil {
+		r.ReplyText(err.Error())
+		return
+	}
+	configurable, ok := p.(plugin.PluginConfigurable)
+	if !ok {
+		r.ReplyText(fmt.Sprintf("插件[%s]不支持配置", args.ID))
+		return
+	}
+
+	current := configurable.GetConfig()
+	switch {
+	case args.Key == "":
+		r.ReplyText(renderConfig(args.ID, current))
+	case args.Value == "":
+		v, ok := current[args.Key]
+		if !ok {
+			r.ReplyText(fmt.Sprintf("插件[%s]没有配置项: %s", args.ID, args.Key))
+			return
+		}
+		r.ReplyText(fmt.Sprintf("%s = %s", args.Key, formatValue(v)))
+	default:
+		updated := make(map[string]any, len(current)+1)
+		for k, v := range current {
+			updated[k] = v
+		}
+		updated[args.Key] = parseValue(args.Value)
+
+		if err := m.registry.ConfigurePlugin(args.ID, updated); err != nil {
+			r.ReplyText(err.Error())
+			return
+		}
+		r.ReplyText(fmt.Sprintf("已设置 %s.%s = %s", args.ID, args.Key, formatValue(updated[args.Key])))
+	}
+}
+
+func (m *manager) health(r *replier.Replier) {
+	r.ReplyText(renderHealth(m.registry.HealthCheck()))
+}
 
+func onOff(enabled bool) string {
+	if enabled {
+		return "启用"
+	}
+	return "禁用"
 }
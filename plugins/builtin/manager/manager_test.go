@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/adapter"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/log"
+	"yora/pkg/message"
+	"yora/pkg/permission"
+	"yora/pkg/plugin"
+	"yora/pkg/policy"
+	"yora/pkg/provider"
+	"yora/pkg/replier"
+	"yora/plugins/builtin/request"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 群消息事件，role 为发送者在群内的角色
+type groupEvent struct {
+	event.MessageEvent
+	text, userID, groupID, role string
+}
+
+func (e *groupEvent) Type() string             { return "message" }
+func (e *groupEvent) SelfID() string           { return "10000" }
+func (e *groupEvent) UserID() string           { return e.userID }
+func (e *groupEvent) ChatID() string           { return e.groupID }
+func (e *groupEvent) IsGroup() bool            { return true }
+func (e *groupEvent) IsPrivate() bool          { return false }
+func (e *groupEvent) MessageID() string        { return "" }
+func (e *groupEvent) Message() message.Message { return message.Text(e.text) }
+func (e *groupEvent) RawMessage() string       { return e.text }
+func (e *groupEvent) Sender() message.Sender   { return sender{role: e.role} }
+
+type sender struct {
+	message.Sender
+	role string
+}
+
+func (s sender) Role() string { return s.role }
+
+// 记录回复内容的适配器
+type replyAdapter struct {
+	adapter.Adapter
+	replies []string
+}
+
+func (a *replyAdapter) Protocol() adapter.Protocol { return "test" }
+
+func (a *replyAdapter) Send(ctx context.Context, userID, groupID string, msg message.Message) (string, error) {
+	a.replies = append(a.replies, msg.PlainText())
+	return "1", nil
+}
+
+func newTestManager(t *testing.T, superUsers ...string) *manager {
+	target := &testPlugin{&plugin.PluginInfo{ID: "switch-target", Name: "开关测试"}}
+	registry := plugin.GetPluginRegistry()
+	require.NoError(t, registry.RegisterPlugins(target))
+	t.Cleanup(func() { registry.UnregisterPlugin(target.info.ID) })
+
+	return &manager{
+		superUsers: superUsers,
+		registry:   registry,
+		policy:     policy.New(),
+		logger:     log.NewPlugin("manager"),
+	}
+}
+
+// 依次匹配管理命令并执行，返回回复内容
+func run(t *testing.T, m *manager, e event.MessageEvent) []string {
+	a := &replyAdapter{}
+	for _, matcher := range m.Matchers() {
+		ctx := adapter.WithAdapter(context.Background(), a)
+		ctx = handler.WithScope(ctx, handler.NewScope(e, provider.Ctx(), provider.MessageEvent(), replier.Provider()))
+		if matcher.Match(ctx, e) {
+			require.NoError(t, matcher.Call(ctx, e))
+		}
+	}
+	return a.replies
+}
+
+func TestMatcherPermissions(t *testing.T) {
+	matchers := New("10001").Matchers()
+	assert.Len(t, matchers, 6)
+	for _, m := range matchers {
+		want := "超级用户"
+		if m.Name == "enable" || m.Name == "disable" {
+			want = "超级用户/群主/群管理员"
+		}
+		assert.Equal(t, want, permission.Describe(m.Permission), m.Name)
+		assert.NotEmpty(t, m.Name)
+	}
+}
+
+func TestGroupAdminSwitchesOwnGroup(t *testing.T) {
+	m := newTestManager(t, "10001")
+	info := &plugin.PluginInfo{ID: "switch-target"}
+
+	admin := func(text string) *groupEvent {
+		return &groupEvent{text: text, userID: "20001", groupID: "30001", role: "admin"}
+	}
+
+	assert.Equal(t, []string{"已在群 30001禁用 switch-target"}, run(t, m, admin("disable switch-target")))
+	assert.False(t, m.policy.PluginEnabled(info, "30001", ""))
+
+	// 其他群与全局需要超级用户
+	assert.Contains(t, run(t, m, admin("enable switch-target -g 30002"))[0], "只能开关本群")
+	assert.Contains(t, run(t, m, admin("disable switch-target --global"))[0], "只能开关本群")
+	assert.True(t, m.policy.PluginEnabled(info, "", ""))
+
+	// 普通成员无权使用
+	member := &groupEvent{text: "enable switch-target", userID: "20002", groupID: "30001", role: "member"}
+	assert.Empty(t, run(t, m, member))
+
+	super := &groupEvent{text: "disable switch-target --global", userID: "10001", groupID: "30001", role: "member"}
+	assert.Equal(t, []string{"已在全局禁用 switch-target"}, run(t, m, super))
+	assert.False(t, m.policy.PluginEnabled(info, "", ""))
+}
+
+func TestCannotDisableManager(t *testing.T) {
+	m := newTestManager(t, "10001")
+	for _, target := range []string{"manager", "manager:enable", "manager:disable --global"} {
+		e := &groupEvent{text: "disable " + target, userID: "10001", groupID: "30001", role: "member"}
+		assert.Equal(t, []string{"不能禁用插件管理本身"}, run(t, m, e), target)
+	}
+}
+
+// 可配置的测试插件
+type configPlugin struct {
+	testPlugin
+	config map[string]any
+}
+
+func (p *configPlugin) GetConfig() map[string]any { return p.config }
+
+func (p *configPlugin) SetConfig(config map[string]any) error {
+	p.config = config
+	return nil
+}
+
+func TestConfigValueKeepsJSONQuotes(t *testing.T) {
+	m := newTestManager(t, "10001")
+	target := &configPlugin{testPlugin: testPlugin{&plugin.PluginInfo{ID: "config-target", Name: "配置测试"}}, config: map[string]any{}}
+	require.NoError(t, m.registry.RegisterPlugins(target))
+	t.Cleanup(func() { m.registry.UnregisterPlugin("config-target") })
+
+	super := func(text string) *groupEvent {
+		return &groupEvent{text: text, userID: "10001", groupID: "30001", role: "member"}
+	}
+
+	run(t, m, super(`config config-target rule {"action":"reject","keywords":["月离"]}`))
+	assert.Equal(t, map[string]any{"action": "reject", "keywords": []any{"月离"}}, target.config["rule"])
+
+	run(t, m, super(`config config-target rule '{"action": "approve"}'`))
+	assert.Equal(t, map[string]any{"action": "approve"}, target.config["rule"])
+
+	run(t, m, super(`config config-target model gpt-4o`))
+	assert.Equal(t, "gpt-4o", target.config["model"])
+}
+
+func TestRequestExamplesConfigurePlugin(t *testing.T) {
+	m := newTestManager(t, "10001")
+	p := request.New(request.DefaultConfig())
+	require.NoError(t, m.registry.RegisterPlugins(p))
+	t.Cleanup(func() { m.registry.UnregisterPlugin(p.PluginInfo().ID) })
+
+	// 插件帮助中的示例经由真实的命令解析写入配置
+	for _, example := range p.PluginInfo().Examples {
+		replies := run(t, m, &groupEvent{text: example, userID: "10001", groupID: "30001", role: "member"})
+		require.Len(t, replies, 1)
+		assert.Contains(t, replies[0], "已设置", example)
+	}
+
+	config := p.(plugin.PluginConfigurable).GetConfig()
+	join := config["join"].(map[string]any)
+	assert.Equal(t, "reject", join["action"])
+	assert.Equal(t, []any{"月离"}, join["keywords"])
+	assert.Equal(t, "答案错误", join["reason"])
+
+	friend := config["friend"].(map[string]any)
+	assert.Equal(t, "approve", friend["action"])
+	assert.Equal(t, "新朋友", friend["remark"])
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"yora/pkg/plugin"
+)
+
+// 插件列表（按ID排序），status 返回插件的开关状态
+func renderPluginList(plugins []plugin.Plugin, status func(info *plugin.PluginInfo) string) string {
+	infos := make([]*plugin.PluginInfo, 0, len(plugins))
+	for _, p := range plugins {
+		infos = append(infos, p.PluginInfo())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "插件列表（共 %d 个）", len(infos))
+	for _, info := range infos {
+		fmt.Fprintf(&b, "\n%s %s", info.ID, info.Name)
+		if info.Version != "" {
+			fmt.Fprintf(&b, " v%s", info.Version)
+		}
+		fmt.Fprintf(&b, " [%s]", status(info))
+	}
+	return b.String()
+}
+
+// 插件配置（按键排序）
+func renderConfig(id string, config map[string]any) string {
+	if len(config) == 0 {
+		return fmt.Sprintf("插件[%s]暂无配置", id)
+	}
+
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "插件[%s]配置", id)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s = %s", k, formatValue(config[k]))
+	}
+	return b.String()
+}
+
+// 健康检查结果（按插件ID排序）
+func renderHealth(results map[string]error) string {
+	if len(results) == 0 {
+		return "没有支持健康检查的插件"
+	}
+
+	ids := make([]string, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	b.WriteString("健康检查")
+	for _, id := range ids {
+		if err := results[id]; err != nil {
+			fmt.Fprintf(&b, "\n%s：异常（%v）", id, err)
+		} else {
+			fmt.Fprintf(&b, "\n%s：正常", id)
+		}
+	}
+	return b.String()
+}
+
+// 解析配置值：合法 JSON 按 JSON 解析（数字、布尔、数组等），否则作为字符串
+func parseValue(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
+
+// 格式化配置值，字符串原样输出，其余使用 JSON
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"yora/pkg/plugin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPlugin struct {
+	info *plugin.PluginInfo
+}
+
+func (p *testPlugin) PluginInfo() *plugin.PluginInfo { return p.info }
+func (p *testPlugin) Matchers() []*plugin.Matcher    { return nil }
+
+func TestRenderPluginList(t *testing.T) {
+	plugins := []plugin.Plugin{
+		&testPlugin{&plugin.PluginInfo{ID: "help", Name: "帮助插件", Version: "0.1.0"}},
+		&testPlugin{&plugin.PluginInfo{ID: "echo", Name: "Echo"}},
+	}
+	got := renderPluginList(plugins, func(info *plugin.PluginInfo) string {
+		return onOff(info.ID == "help")
+	})
+	assert.Equal(t, "插件列表（共 2 个）\necho Echo [禁用]\nhelp 帮助插件 v0.1.0 [启用]", got)
+}
+
+func TestRenderHealth(t *testing.T) {
+	assert.Equal(t, "没有支持健康检查的插件", renderHealth(nil))
+	got := renderHealth(map[string]error{"b": errors.New("连接断开"), "a": nil})
+	assert.Equal(t, "健康检查\na：正常\nb：异常（连接断开）", got)
+}
+
+func TestParseValue(t *testing.T) {
+	assert.Equal(t, float64(3), parseValue("3"))
+	assert.Equal(t, true, parseValue("true"))
+	assert.Equal(t, []any{"a", "b"}, parseValue(`["a","b"]`))
+	assert.Equal(t, "gpt-4o", parseValue("gpt-4o"))
+
+	assert.Equal(t, "plain", formatValue("plain"))
+	assert.Equal(t, `["a","b"]`, formatValue([]any{"a", "b"}))
+	assert.Equal(t, "插件[x]配置\na = 1\nb = on", renderConfig("x", map[string]any{"b": "on", "a": 1}))
+}
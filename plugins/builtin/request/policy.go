@@ -0,0 +1,90 @@
+package request
+
+import (
+	"slices"
+	"strings"
+	"yora/pkg/event"
+)
+
+// 处理方式
+const (
+	ActionApprove = "approve" // 自动同意
+	ActionReject  = "reject"  // 自动拒绝
+	ActionManual  = "manual"  // 人工处理
+)
+
+// Config 请求处理策略
+type Config struct {
+	Friend Policy            `json:"friend"` // 加好友请求
+	Join   Policy            `json:"join"`   // 加群请求
+	Groups map[string]Policy `json:"groups"` // 按群覆盖加群请求策略
+	Invite Policy            `json:"invite"` // 邀请机器人入群，超级用户的邀请总是同意
+	Notify bool              `json:"notify"` // 需要人工处理时通知管理员
+}
+
+// Policy 单类请求的处理策略
+//
+// 判定顺序：黑名单拒绝 > 验证信息包含关键字时同意 > Action。
+type Policy struct {
+	Action    string   `json:"action"`    // approve、reject 或 manual（默认）
+	Keywords  []string `json:"keywords"`  // 验证信息（或加群答案）包含任一关键字时同意
+	Blacklist []string `json:"blacklist"` // 拒绝的用户ID
+	Reason    string   `json:"reason"`    // 拒绝理由
+	Remark    string   `json:"remark"`    // 同意加好友后的备注
+}
+
+// DefaultConfig 默认策略：全部人工处理并通知管理员
+func DefaultConfig() Config {
+	return Config{Notify: true}
+}
+
+// 处理结果
+type decision struct {
+	action string
+	why    string // 判定原因（用于日志与通知）
+	policy Policy
+}
+
+// 根据策略决定请求的处理方式
+func decide(cfg Config, e event.RequestEvent, superUsers []string) decision {
+	var p Policy
+	switch {
+	case e.RequestType() == "friend":
+		p = cfg.Friend
+	case e.RequestType() == "group" && e.SubType() == "invite":
+		if slices.Contains(superUsers, e.UserID()) {
+			return decision{action: ActionApprove, why: "超级用户邀请", policy: cfg.Invite}
+		}
+		p = cfg.Invite
+	case e.RequestType() == "group":
+		p = cfg.Join
+		if gp, ok := cfg.Groups[e.ChatID()]; ok {
+			p = gp
+		}
+	default:
+		return decision{action: ActionManual, why: "未知请求类型"}
+	}
+
+	if slices.Contains(p.Blacklist, e.UserID()) {
+		return decision{action: ActionReject, why: "黑名单", policy: p}
+	}
+	comment := strings.ToLower(e.Comment())
+	for _, k := range p.Keywords {
+		if k != "" && strings.Contains(comment, strings.ToLower(k)) {
+			return decision{action: ActionApprove, why: "验证信息包含关键字 " + k, policy: p}
+		}
+	}
+
+	switch p.Action {
+	case ActionApprove:
+		return decision{action: ActionApprove, why: "自动同意", policy: p}
+	case ActionReject:
+		why := "自动拒绝"
+		if len(p.Keywords) > 0 {
+			why = "验证信息不正确"
+		}
+		return decision{action: ActionReject, why: why, policy: p}
+	default:
+		return decision{action: ActionManual, why: "需人工处理", policy: p}
+	}
+}
@@ -0,0 +1,168 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"yora/pkg/bot"
+	"yora/pkg/event"
+	"yora/pkg/handler"
+	"yora/pkg/log"
+	"yora/pkg/message"
+	"yora/pkg/on"
+	"yora/pkg/plugin"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	_ plugin.Plugin             = (*request)(nil)
+	_ plugin.PluginConfigurable = (*request)(nil)
+)
+
+var pluginMeta = &plugin.PluginInfo{
+	ID:          "request",
+	Name:        "请求处理",
+	Description: "按策略自动处理加好友、加群请求与入群邀请：关键字自动同意、黑名单拒绝、通知管理员",
+	Version:     "0.1.0",
+	Author:      "月离",
+	Usage:       "config request <friend|join|invite|groups|notify> <JSON>（JSON 中有空格时用单引号包裹）",
+	Examples:    []string{`config request join {"action":"reject","keywords":["月离"],"reason":"答案错误"}`, `config request friend '{"action": "approve", "remark": "新朋友"}'`},
+	Group:       "builtin",
+	Extra:       nil,
+}
+
+// New 创建请求处理插件，superUsers 的入群邀请总是同意，并接收待处理请求的通知
+func New(cfg Config, superUsers ...string) plugin.Plugin {
+	return &request{
+		cfg:        cfg,
+		superUsers: superUsers,
+		logger:     log.NewPlugin("request"),
+	}
+}
+
+type request struct {
+	cfg        Config
+	superUsers []string
+	logger     zerolog.Logger
+	mu         sync.RWMutex
+}
+
+func (p *request) PluginInfo() *plugin.PluginInfo {
+	return pluginMeta
+}
+
+func (p *request) Matchers() []*plugin.Matcher {
+	return []*plugin.Matcher{
+		on.OnRequest(handler.NewHandler(p.handle)).SetName("handle").SetPlugin(p),
+	}
+}
+
+// GetConfig 以 JSON 字段名返回当前策略
+func (p *request) GetConfig() map[string]any {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	raw, _ := json.Marshal(p.cfg)
+	var m map[string]any
+	json.Unmarshal(raw, &m)
+	return m
+}
+
+// SetConfig 更新策略，字段同 Config 的 JSON 字段
+func (p *request) SetConfig(config map[string]any) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("序列化请求处理策略失败: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return fmt.Errorf("解析请求处理策略失败: %w", err)
+	}
+
+	p.mu.Lock()
+	p.cfg = cfg
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *request) config() Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cfg
+}
+
+func (p *request) handle(ctx context.Context, e event.RequestEvent, b bot.Bot) error {
+	cfg := p.config()
+	d := decide(cfg, e, p.superUsers)
+
+	p.logger.Info().
+		Str("请求类型", e.RequestType()).
+		Str("子类型", e.SubType()).
+		Str("用户ID", e.UserID()).
+		Str("群ID", e.ChatID()).
+		Str("验证信息", e.Comment()).
+		Str("处理", d.action).
+		Str("原因", d.why).
+		Msg("收到请求")
+
+	var err error
+	switch d.action {
+	case ActionApprove:
+		err = e.Approve(ctx, d.policy.Remark)
+	case ActionReject:
+		err = e.Reject(ctx, d.policy.Reason)
+	default:
+		if cfg.Notify {
+			p.notify(b, e)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("处理请求失败: %w", err)
+	}
+	return nil
+}
+
+// 通知管理员：加群请求发到对应群，其他请求私聊超级用户
+func (p *request) notify(b bot.Bot, e event.RequestEvent) {
+	if b == nil {
+		return
+	}
+	b = b.Account(e.SelfID())
+	text := describe(e)
+
+	if e.RequestType() == "group" && e.SubType() != "invite" {
+		if _, err := b.Send("", e.ChatID(), message.Text(text+"\n请管理员处理")); err != nil {
+			p.logger.Warn().Err(err).Str("群ID", e.ChatID()).Msg("通知群管理员失败")
+		}
+		return
+	}
+	if len(p.superUsers) == 0 {
+		p.logger.Warn().Str("用户ID", e.UserID()).Msg("未配置超级用户，跳过请求通知")
+		return
+	}
+	for _, uid := range p.superUsers {
+		if _, err := b.Send(uid, "", message.Text(text)); err != nil {
+			p.logger.Warn().Err(err).Str("用户ID", uid).Msg("通知超级用户失败")
+		}
+	}
+}
+
+// 请求的文字描述
+func describe(e event.RequestEvent) string {
+	var text string
+	switch {
+	case e.RequestType() == "friend":
+		text = fmt.Sprintf("收到好友请求：%s", e.UserID())
+	case e.SubType() == "invite":
+		text = fmt.Sprintf("收到入群邀请：%s 邀请加入群 %s", e.UserID(), e.ChatID())
+	default:
+		text = fmt.Sprintf("收到加群请求：%s 申请加入群 %s", e.UserID(), e.ChatID())
+	}
+	if e.Comment() != "" {
+		text += "\n验证信息：" + e.Comment()
+	}
+	return text
+}
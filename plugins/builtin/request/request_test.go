@@ -0,0 +1,86 @@
+package request
+
+import (
+	"context"
+	"testing"
+	"yora/pkg/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRequest struct {
+	event.RequestEvent
+	typ, sub, user, group, comment string
+
+	approved, rejected bool
+	arg                string
+}
+
+func (r *fakeRequest) RequestType() string { return r.typ }
+func (r *fakeRequest) SubType() string     { return r.sub }
+func (r *fakeRequest) UserID() string      { return r.user }
+func (r *fakeRequest) ChatID() string      { return r.group }
+func (r *fakeRequest) Comment() string     { return r.comment }
+func (r *fakeRequest) SelfID() string      { return "bot" }
+
+func (r *fakeRequest) Approve(ctx context.Context, remark string) error {
+	r.approved, r.arg = true, remark
+	return nil
+}
+
+func (r *fakeRequest) Reject(ctx context.Context, reason string) error {
+	r.rejected, r.arg = true, reason
+	return nil
+}
+
+func TestDecide(t *testing.T) {
+	cfg := Config{
+		Friend: Policy{Action: ActionApprove, Blacklist: []string{"bad"}},
+		Join:   Policy{Action: ActionReject, Keywords: []string{"Yora"}},
+		Groups: map[string]Policy{"g2": {}},
+		Invite: Policy{Action: ActionReject},
+	}
+	root := []string{"root"}
+
+	cases := []struct {
+		req  *fakeRequest
+		want string
+	}{
+		{&fakeRequest{typ: "friend", user: "u1"}, ActionApprove},
+		{&fakeRequest{typ: "friend", user: "bad"}, ActionReject},
+		{&fakeRequest{typ: "group", sub: "add", group: "g1", comment: "问题：暗号\n答案：yora"}, ActionApprove},
+		{&fakeRequest{typ: "group", sub: "add", group: "g1", comment: "答案：不知道"}, ActionReject},
+		{&fakeRequest{typ: "group", sub: "add", group: "g2", comment: "yora"}, ActionManual},
+		{&fakeRequest{typ: "group", sub: "invite", user: "root"}, ActionApprove},
+		{&fakeRequest{typ: "group", sub: "invite", user: "u1"}, ActionReject},
+		{&fakeRequest{typ: "other"}, ActionManual},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, decide(cfg, c.req, root).action, "%+v", *c.req)
+	}
+}
+
+func TestHandleAppliesPolicy(t *testing.T) {
+	p := New(DefaultConfig(), "root").(*request)
+	require.NoError(t, p.SetConfig(map[string]any{
+		"friend": map[string]any{"action": "approve", "remark": "网友"},
+		"join":   map[string]any{"action": "reject", "keywords": []any{"yora"}, "reason": "答案错误"},
+	}))
+	assert.Equal(t, "approve", p.GetConfig()["friend"].(map[string]any)["action"])
+
+	friend := &fakeRequest{typ: "friend", user: "u1"}
+	require.NoError(t, p.handle(context.Background(), friend, nil))
+	assert.True(t, friend.approved)
+	assert.Equal(t, "网友", friend.arg)
+
+	join := &fakeRequest{typ: "group", sub: "add", group: "g1", comment: "答案：?"}
+	require.NoError(t, p.handle(context.Background(), join, nil))
+	assert.True(t, join.rejected)
+	assert.Equal(t, "答案错误", join.arg)
+
+	invite := &fakeRequest{typ: "group", sub: "invite", user: "u1", group: "g9"}
+	require.NoError(t, p.handle(context.Background(), invite, nil))
+	assert.False(t, invite.approved || invite.rejected, "默认人工处理")
+	assert.Contains(t, describe(invite), "邀请加入群 g9")
+}